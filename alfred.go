@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// alfredItem is one row of an Alfred script filter result. Raycast's script
+// filter support reads the same format.
+type alfredItem struct {
+	UID          string `json:"uid,omitempty"`
+	Title        string `json:"title"`
+	Subtitle     string `json:"subtitle,omitempty"`
+	Arg          string `json:"arg,omitempty"`
+	QuickLookURL string `json:"quicklookurl,omitempty"`
+	Valid        *bool  `json:"valid,omitempty"`
+}
+
+// alfredRenderer buffers every entry and writes a script filter result on
+// flush. Each item's arg is the event's URL, so actioning it opens the event.
+type alfredRenderer struct {
+	w     io.Writer
+	items []alfredItem
+}
+
+func (r *alfredRenderer) feed(user string, _ []Event, entries []entry) error {
+	for _, e := range entries {
+		url := eventURL(e.Event)
+		r.items = append(r.items, alfredItem{
+			UID:          e.Event.ID,
+			Title:        e.Summary,
+			Subtitle:     user + " · " + shortAgo(now().Sub(e.Event.CreatedAt)) + " · " + e.Event.Repo.Name,
+			Arg:          url,
+			QuickLookURL: url,
+		})
+	}
+	return nil
+}
+
+func (r *alfredRenderer) flush() error {
+	if len(r.items) == 0 {
+		valid := false
+		r.items = []alfredItem{{Title: "No recent activity", Valid: &valid}}
+	}
+	return json.NewEncoder(r.w).Encode(struct {
+		Items []alfredItem `json:"items"`
+	}{r.items})
+}
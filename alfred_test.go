@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlfredRenderer(t *testing.T) {
+	pinClock(t)
+	e := testEntry("IssuesEvent", "acme/app", "Opened an issue #5 in acme/app")
+	e.Event.ID = "42"
+	e.Event.CreatedAt = now().Add(-3 * time.Hour)
+	e.Event.Payload = mustRaw(map[string]any{"action": "opened", "issue": map[string]any{"number": 5}})
+
+	var b strings.Builder
+	r := &alfredRenderer{w: &b}
+	if err := r.feed("alice", nil, []entry{e}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	var got struct{ Items []alfredItem }
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := alfredItem{
+		UID:          "42",
+		Title:        "Opened an issue #5 in acme/app",
+		Subtitle:     "alice · 3h ago · acme/app",
+		Arg:          webURL + "/acme/app/issues/5",
+		QuickLookURL: webURL + "/acme/app/issues/5",
+	}
+	if len(got.Items) != 1 || got.Items[0] != want {
+		t.Errorf("got %+v\nwant %+v", got.Items, want)
+	}
+}
+
+func TestAlfredRendererEmpty(t *testing.T) {
+	var b strings.Builder
+	r := &alfredRenderer{w: &b}
+	_ = r.feed("alice", nil, nil)
+	_ = r.flush()
+	if got, want := b.String(), `{"items":[{"title":"No recent activity","valid":false}]}`+"\n"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Author   atomAuthor   `xml:"author"`
+	Link     atomLink     `xml:"link"`
+	Category atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// atomRenderer buffers every entry and writes an Atom feed on flush, so a
+// cron job can regenerate a personal activity feed for feed readers.
+type atomRenderer struct {
+	w       io.Writer
+	users   []string
+	entries []entry
+}
+
+func (r *atomRenderer) feed(user string, _ []Event, entries []entry) error {
+	r.users = append(r.users, user)
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *atomRenderer) flush() error {
+	feed := atomFeed{
+		ID:    "tag:github.com,2008:activity:" + strings.Join(r.users, ","),
+		Title: "GitHub activity for " + strings.Join(r.users, ", "),
+		Link:  atomLink{Href: webURL, Rel: "alternate"},
+	}
+	if len(r.users) == 1 {
+		profile := webURL + "/" + r.users[0]
+		feed.Author = &atomAuthor{Name: r.users[0], URI: profile}
+		feed.Link.Href = profile
+	}
+
+	// Entries arrive newest first per user; the feed is as fresh as its
+	// newest entry, and stable when nothing new happened.
+	var updated time.Time
+	for _, e := range r.entries {
+		if e.Event.CreatedAt.After(updated) {
+			updated = e.Event.CreatedAt
+		}
+		author := e.Event.Actor.Login
+		if author == "" {
+			author = e.User
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:       atomEntryID(e),
+			Title:    actorPrefix(e) + e.Summary,
+			Updated:  e.Event.CreatedAt.UTC().Format(time.RFC3339),
+			Author:   atomAuthor{Name: author, URI: webURL + "/" + author},
+			Link:     atomLink{Href: entityURL(e.Event), Rel: "alternate"},
+			Category: atomCategory{Term: e.Event.Type},
+		})
+	}
+	if updated.IsZero() {
+		updated = now()
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	if _, err := io.WriteString(r.w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(r.w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(r.w, "\n")
+	return err
+}
+
+// atomEntryID returns a permanent, unique ID for an entry. GitHub event IDs
+// are stable, so readers won't show an event twice across regenerations.
+func atomEntryID(e entry) string {
+	if e.Event.ID != "" {
+		return "tag:github.com,2008:Event/" + e.Event.ID
+	}
+	return entityURL(e.Event) + "#" + e.Event.CreatedAt.UTC().Format(time.RFC3339)
+}
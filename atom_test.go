@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestAtomRenderer(t *testing.T) {
+	var buf strings.Builder
+	r := &atomRenderer{w: &buf}
+	e := testEntry("WatchEvent", "bob/tool", "Starred bob/tool & friends")
+	e.Event.ID = "12345"
+	_ = r.feed("alice", []Event{e.Event}, []entry{e})
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(buf.String()), &feed); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, buf.String())
+	}
+	if feed.Updated != "2024-05-01T12:00:00Z" || feed.Author == nil || feed.Author.Name != "alice" {
+		t.Fatalf("unexpected feed header: %+v", feed)
+	}
+	if len(feed.Entries) != 1 {
+		t.Fatalf("want 1 entry, got %d", len(feed.Entries))
+	}
+	got := feed.Entries[0]
+	if got.ID != "tag:github.com,2008:Event/12345" || got.Title != "Starred bob/tool & friends" ||
+		got.Link.Href != "https://github.com/bob/tool" || got.Category.Term != "WatchEvent" {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+}
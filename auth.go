@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// credential is a token and where it came from, so --debug and errors can
+// say how requests were authenticated.
+type credential struct {
+	Token  string
+	Source string // e.g. "$GITHUB_TOKEN"
+}
+
+// describe says how a request with c is authenticated.
+func (c credential) describe() string {
+	if c.Token == "" {
+		return "unauthenticated"
+	}
+	return "authenticated via " + c.Source
+}
+
+// hostCredential finds the token for the API at host. In order: the one
+// read with --token-file or $GITHUB_TOKEN_FILE; the pool's current token if
+// a token pool is set up for host; $GH_TOKEN or $GITHUB_TOKEN for
+// github.com, and $GH_ENTERPRISE_TOKEN or $GITHUB_ENTERPRISE_TOKEN for
+// Enterprise Server hosts, as gh does, so a github.com token is never sent
+// to another server; the GitHub App configured in the environment; the
+// token `auth login` saved for the host; and last, the host's password in
+// ~/.netrc.
+func hostCredential(ctx context.Context, host string) credential {
+	if fileCredential.Token != "" {
+		return fileCredential
+	}
+	if p := poolFor(host); p != nil {
+		return p.pick()
+	}
+	for _, name := range tokenVars(host) {
+		if t := strings.TrimSpace(os.Getenv(name)); t != "" {
+			return credential{Token: t, Source: "$" + name}
+		}
+	}
+	if c := appCredential(ctx); c.Token != "" {
+		return c
+	}
+	if c := storedCredential(host); c.Token != "" {
+		return c
+	}
+	return netrcCredential(host)
+}
+
+// tokenVars are the environment variables holding host's token, in order.
+func tokenVars(host string) []string {
+	if !isDotCom(host) {
+		return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	return []string{"GH_TOKEN", "GITHUB_TOKEN"}
+}
+
+// hostCredentials lists every token available for host, the one
+// hostCredential picks first, so `auth status` can say which it shadows.
+func hostCredentials(ctx context.Context, host string) []credential {
+	var out []credential
+	if fileCredential.Token != "" {
+		out = append(out, fileCredential)
+	}
+	if p := poolFor(host); p != nil {
+		out = append(out, p.pick())
+	}
+	for _, name := range tokenVars(host) {
+		if t := strings.TrimSpace(os.Getenv(name)); t != "" {
+			out = append(out, credential{Token: t, Source: "$" + name})
+		}
+	}
+	if c := appCredential(ctx); c.Token != "" {
+		out = append(out, c)
+	}
+	if c := storedCredential(host); c.Token != "" {
+		out = append(out, c)
+	}
+	if c := netrcCredential(host); c.Token != "" {
+		out = append(out, c)
+	}
+	return out
+}
+
+// fileCredential is the token read from --token-file or $GITHUB_TOKEN_FILE,
+// set by main.
+var fileCredential credential
+
+// readTokenFile reads a token from path, such as a mounted Docker or
+// Kubernetes secret, or from stdin when path is "-". Surrounding whitespace,
+// like the trailing newline most secrets end with, is dropped.
+func readTokenFile(path string, stdin io.Reader) (credential, error) {
+	var data []byte
+	var err error
+	source := path
+	if path == "-" {
+		data, err = io.ReadAll(io.LimitReader(stdin, 64<<10))
+		source = "stdin"
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return credential{}, err
+	}
+	token := strings.TrimSpace(string(data))
+	switch {
+	case token == "":
+		return credential{}, fmt.Errorf("no token in %s", source)
+	case strings.ContainsAny(token, " \t\r\n"):
+		return credential{}, fmt.Errorf("%s holds more than a token", source)
+	}
+	return credential{Token: token, Source: source}, nil
+}
+
+// requestCredential is the credential to send with a request to u. Tokens
+// only go to the configured API host, never to hosts a Link header or a
+// plugin might name.
+func requestCredential(ctx context.Context, u *url.URL) credential {
+	api, err := url.Parse(apiURL)
+	if err != nil || !strings.EqualFold(u.Host, api.Host) {
+		return credential{}
+	}
+	return hostCredential(ctx, api.Host)
+}
+
+// warnPrivateAccess warns about users whose private events --include-private
+// can't show, because requests aren't authenticated as them.
+func warnPrivateAccess(ctx context.Context, users []string) {
+	api, err := url.Parse(apiURL)
+	if err != nil {
+		return
+	}
+	if hostCredential(ctx, api.Host).Token == "" {
+		fmt.Fprintln(warnings, "Warning: --include-private: requests aren't authenticated, so only public events are shown")
+		return
+	}
+	var me struct {
+		Login string `json:"login"`
+	}
+	if err := getJSON(ctx, apiURL+"/user", &me); err != nil {
+		fmt.Fprintf(warnings, "Warning: --include-private: can't tell whose token this is (%v); private events only show for its own user\n", err)
+		return
+	}
+	for _, u := range users {
+		if !strings.EqualFold(u, me.Login) {
+			fmt.Fprintf(warnings, "Warning: --include-private: authenticated as %s, so only %s's public events are shown\n", me.Login, u)
+		}
+	}
+}
+
+// debugLog receives a line per API request when --debug is on.
+var debugLog io.Writer
+
+func debugf(format string, args ...any) {
+	if debugLog != nil {
+		fmt.Fprintf(debugLog, "debug: "+format+"\n", args...)
+	}
+}
+
+// debugResponse logs a response's status, authentication, and remaining
+// rate limit.
+func debugResponse(req *http.Request, resp *http.Response, c credential) {
+	if debugLog == nil {
+		return
+	}
+	quota := ""
+	if left := resp.Header.Get("X-RateLimit-Remaining"); left != "" {
+		quota = fmt.Sprintf(", rate limit %s/%s left", left, resp.Header.Get("X-RateLimit-Limit"))
+	}
+	debugf("%s %s: %s (%s%s)", req.Method, req.URL.Redacted(), resp.Status, c.describe(), quota)
+}
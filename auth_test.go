@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHostCredential(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "dotcom-secret")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "ghe-secret")
+	tests := []struct {
+		host string
+		want credential
+	}{
+		{"api.github.com", credential{"dotcom-secret", "$GITHUB_TOKEN"}},
+		{"github.example.com", credential{"ghe-secret", "$GITHUB_ENTERPRISE_TOKEN"}},
+	}
+	for _, tt := range tests {
+		if got := hostCredential(context.Background(), tt.host); got != tt.want {
+			t.Errorf("%s: got %+v", tt.host, got)
+		}
+	}
+	t.Setenv("GH_TOKEN", "gh-first")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghe-first")
+	if got := hostCredential(context.Background(), "api.github.com"); got.Source != "$GH_TOKEN" {
+		t.Errorf("GH_TOKEN first: got %+v", got)
+	}
+	if got := hostCredential(context.Background(), "github.example.com"); got.Source != "$GH_ENTERPRISE_TOKEN" {
+		t.Errorf("GH_ENTERPRISE_TOKEN first: got %+v", got)
+	}
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	if got := hostCredential(context.Background(), "api.github.com"); got.Token != "" || got.describe() != "unauthenticated" {
+		t.Errorf("no token: got %+v", got)
+	}
+}
+
+func TestDebugAndRateLimitNameTheCredential(t *testing.T) {
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghe-secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	restoreAPI, restoreLog := apiURL, debugLog
+	apiURL = srv.URL
+	var log strings.Builder
+	debugLog = &log
+	defer func() { apiURL, debugLog = restoreAPI, restoreLog }()
+
+	var v any
+	err := getJSON(context.Background(), srv.URL+"/users/alice/events", &v)
+	if err == nil || !strings.Contains(err.Error(), "for the token from $GH_ENTERPRISE_TOKEN") {
+		t.Errorf("error: %v", err)
+	}
+	want := "debug: GET " + srv.URL + "/users/alice/events: 403 Forbidden (authenticated via $GH_ENTERPRISE_TOKEN, rate limit 0/5000 left)\n"
+	if log.String() != want {
+		t.Errorf("debug log:\n%q\nwant\n%q", log.String(), want)
+	}
+	if strings.Contains(log.String(), "ghe-secret") {
+		t.Error("token in debug log")
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gh")
+	if err := os.WriteFile(path, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readTokenFile(path, nil); err != nil || got != (credential{"file-secret", path}) {
+		t.Errorf("file: got %+v, %v", got, err)
+	}
+	if got, err := readTokenFile("-", strings.NewReader("  stdin-secret\r\n")); err != nil || got != (credential{"stdin-secret", "stdin"}) {
+		t.Errorf("stdin: got %+v, %v", got, err)
+	}
+	for _, input := range []string{"\n", "one\ntwo\n"} {
+		if _, err := readTokenFile("-", strings.NewReader(input)); err == nil {
+			t.Errorf("%q: no error", input)
+		}
+	}
+
+	t.Setenv("GH_TOKEN", "env-secret")
+	t.Setenv("GITHUB_TOKEN", "")
+	fileCredential = credential{"file-secret", "--token-file (" + path + ")"}
+	defer func() { fileCredential = credential{} }()
+	if got := hostCredential(context.Background(), "api.github.com"); got != fileCredential {
+		t.Errorf("token file doesn't take precedence: got %+v", got)
+	}
+	if got := hostCredentials(context.Background(), "api.github.com"); len(got) != 2 || got[1].Source != "$GH_TOKEN" {
+		t.Errorf("credentials: %+v", got)
+	}
+}
+
+func TestWarnPrivateAccess(t *testing.T) {
+	t.Setenv("GH_ENTERPRISE_TOKEN", "alice-secret")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"login":"alice"}`))
+	}))
+	defer srv.Close()
+	restoreAPI, restoreWarnings := apiURL, warnings
+	apiURL = srv.URL
+	var warned strings.Builder
+	warnings = &warned
+	defer func() { apiURL, warnings = restoreAPI, restoreWarnings }()
+
+	warnPrivateAccess(context.Background(), []string{"Alice", "bob"})
+	if want := "Warning: --include-private: authenticated as alice, so only bob's public events are shown\n"; warned.String() != want {
+		t.Errorf("got %q, want %q", warned.String(), want)
+	}
+	warned.Reset()
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	warnPrivateAccess(context.Background(), []string{"alice"})
+	if !strings.Contains(warned.String(), "aren't authenticated") {
+		t.Errorf("unauthenticated: %q", warned.String())
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// rateQuota is one rate limit bucket from the rate_limit API.
+type rateQuota struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+// authReport is what `auth status` found out about a host's credentials.
+type authReport struct {
+	Host     string
+	Cred     credential
+	Shadowed []string // other token sources that the active one overrides
+	Login    string
+	Scopes   []string
+	HasScope bool   // the X-OAuth-Scopes header was sent (classic tokens only)
+	Rejected string // the status GitHub refused the token with, if it did
+	Core     rateQuota
+	Search   rateQuota
+	Pool     []poolQuota // each token's quota, when host has a token pool
+}
+
+// poolQuota is one pooled token's core rate limit, or why it is unknown.
+type poolQuota struct {
+	Source string
+	Core   rateQuota
+	Err    string
+}
+
+// authGet sends an uncached GET to the API with c's token, decoding a
+// successful response into v.
+func authGet(ctx context.Context, path string, c credential, v any) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	debugResponse(req, resp, c)
+	if resp.StatusCode == http.StatusOK {
+		err = json.NewDecoder(resp.Body).Decode(v)
+	}
+	return resp, err
+}
+
+// checkAuth reports on the credentials for the configured API host: which
+// token is used, who it belongs to, its scopes, and the quota left. The
+// rate_limit request itself is free.
+func checkAuth(ctx context.Context) (authReport, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return authReport{}, err
+	}
+	r := authReport{Host: tokenHost(u.Host)}
+	creds := hostCredentials(ctx, u.Host)
+	if len(creds) > 0 {
+		r.Cred = creds[0]
+		for _, c := range creds[1:] {
+			r.Shadowed = append(r.Shadowed, c.Source)
+		}
+	}
+
+	if strings.HasPrefix(r.Cred.Source, "GitHub App") {
+		// Installation tokens act as the app, not a user, and have no
+		// scopes; /user would only refuse them.
+		r.Login = "(GitHub App installation)"
+	} else if r.Cred.Token != "" {
+		var user struct {
+			Login string `json:"login"`
+		}
+		resp, err := authGet(ctx, "/user", r.Cred, &user)
+		if err != nil {
+			return r, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			r.Rejected = resp.Status
+			return r, nil
+		}
+		r.Login = user.Login
+		r.Scopes = splitScopes(resp.Header.Get("X-OAuth-Scopes"))
+		r.HasScope = len(resp.Header.Values("X-OAuth-Scopes")) > 0
+	}
+
+	var limits struct {
+		Resources struct {
+			Core   rateQuota `json:"core"`
+			Search rateQuota `json:"search"`
+		} `json:"resources"`
+	}
+	resp, err := authGet(ctx, "/rate_limit", r.Cred, &limits)
+	if err != nil {
+		return r, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return r, fmt.Errorf("rate_limit: %s", resp.Status)
+	}
+	r.Core, r.Search = limits.Resources.Core, limits.Resources.Search
+
+	if p := poolFor(u.Host); p != nil {
+		p.mu.Lock()
+		pooled := make([]credential, len(p.tokens))
+		for i, t := range p.tokens {
+			pooled[i] = t.Cred
+		}
+		p.mu.Unlock()
+		for _, c := range pooled {
+			q := poolQuota{Source: c.Source}
+			resp, err := authGet(ctx, "/rate_limit", c, &limits)
+			switch {
+			case err != nil:
+				q.Err = err.Error()
+			case resp.StatusCode != http.StatusOK:
+				q.Err = "rejected (" + resp.Status + ")"
+			default:
+				q.Core = limits.Resources.Core
+			}
+			r.Pool = append(r.Pool, q)
+		}
+	}
+	return r, nil
+}
+
+// quotaLine describes a rate limit bucket, e.g. "4987/5000 left, resets at
+// 10:42 (in 37 minutes)".
+func quotaLine(q rateQuota) string {
+	s := fmt.Sprintf("%d/%d left", q.Remaining, q.Limit)
+	if q.Reset > 0 {
+		reset := time.Unix(q.Reset, 0)
+		s += fmt.Sprintf(", resets at %s (in %s)", reset.Local().Format("15:04"), formatLeft(reset.Sub(now())))
+	}
+	return s
+}
+
+func printAuthStatus(w io.Writer, r authReport) {
+	fmt.Fprintln(w, r.Host)
+	if r.Cred.Token == "" {
+		fmt.Fprintln(w, "  Credential:  none; requests are unauthenticated")
+		fmt.Fprintf(w, "               run auth login or set %s to raise the limit\n", tokenVars(r.Host)[1])
+	} else {
+		fmt.Fprintln(w, "  Credential: ", r.Cred.Source)
+		if len(r.Shadowed) > 0 {
+			fmt.Fprintf(w, "               (overrides %s)\n", strings.Join(r.Shadowed, ", "))
+		}
+	}
+	if r.Rejected != "" {
+		fmt.Fprintf(w, "  Token:       rejected (%s); it is invalid, expired, or revoked\n", r.Rejected)
+		return
+	}
+	if r.Login != "" {
+		fmt.Fprintln(w, "  Logged in:  ", r.Login)
+	}
+	switch {
+	case r.Cred.Token == "":
+	case !r.HasScope:
+		fmt.Fprintln(w, "  Scopes:      not reported (fine-grained token or GitHub App)")
+	case len(r.Scopes) == 0:
+		fmt.Fprintln(w, "  Scopes:      none (public data only)")
+	default:
+		fmt.Fprintln(w, "  Scopes:     ", strings.Join(r.Scopes, ", "))
+	}
+	fmt.Fprintln(w, "  Rate limit: ", quotaLine(r.Core))
+	fmt.Fprintln(w, "  Search:     ", quotaLine(r.Search))
+	if len(r.Pool) > 0 {
+		fmt.Fprintln(w, "  Token pool: ", plural(len(r.Pool), "token", "tokens"))
+		for _, q := range r.Pool {
+			if q.Err != "" {
+				fmt.Fprintf(w, "    %s: %s\n", q.Source, q.Err)
+			} else {
+				fmt.Fprintf(w, "    %s: %s\n", q.Source, quotaLine(q.Core))
+			}
+		}
+	}
+	if r.Core.Remaining == 0 && r.Core.Limit > 0 {
+		fmt.Fprintln(w, "  The rate limit is used up; requests fail until it resets.")
+	}
+}
+
+func runAuthStatus(args []string) int {
+	fs := flag.NewFlagSet("auth status", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s auth status [options]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Shows which token requests use and where it comes from, the account and scopes")
+		fmt.Fprintln(fs.Output(), "it has, and the rate limit left. Exits 1 if GitHub rejects the token.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	r, err := checkAuth(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	printAuthStatus(os.Stdout, r)
+	if r.Rejected != "" {
+		return 1
+	}
+	return 0
+}
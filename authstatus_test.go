@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckAuth(t *testing.T) {
+	pinClock(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") == "Bearer expired":
+			http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+		case r.URL.Path == "/user":
+			w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+			w.Write([]byte(`{"login": "alice"}`))
+		case r.URL.Path == "/rate_limit":
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4987, "reset": 1714815420}, "search": {"limit": 30, "remaining": 30}}}`))
+		}
+	}))
+	defer srv.Close()
+	restoreAPI, restorePath := apiURL, tokenStorePath
+	apiURL, tokenStorePath = srv.URL, ""
+	defer func() { apiURL, tokenStorePath = restoreAPI, restorePath }()
+	t.Setenv("GH_ENTERPRISE_TOKEN", "gh-secret")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "other-secret")
+
+	r, err := checkAuth(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	printAuthStatus(&b, r)
+	out := b.String()
+	for _, want := range []string{
+		"Credential:  $GH_ENTERPRISE_TOKEN\n",
+		"(overrides $GITHUB_ENTERPRISE_TOKEN)",
+		"Logged in:   alice",
+		"Scopes:      repo, read:org",
+		"Rate limit:  4987/5000 left, resets at 09:37 (in 37 minutes)",
+		"Search:      30/30 left\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "expired")
+	r, err = checkAuth(context.Background())
+	if err != nil || !strings.HasPrefix(r.Rejected, "401") {
+		t.Errorf("expired token: %+v, %v", r, err)
+	}
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	r, err = checkAuth(context.Background())
+	b.Reset()
+	printAuthStatus(&b, r)
+	if err != nil || !strings.Contains(b.String(), "requests are unauthenticated") || !strings.Contains(b.String(), "set GITHUB_ENTERPRISE_TOKEN") {
+		t.Errorf("no token: %v\n%s", err, b.String())
+	}
+}
+
+func TestQuotaLine(t *testing.T) {
+	pinClock(t)
+	reset := now().Add(90 * time.Minute).Unix()
+	if got := quotaLine(rateQuota{Limit: 60, Remaining: 0, Reset: reset}); got != "0/60 left, resets at 10:30 (in 90 minutes)" {
+		t.Errorf("got %q", got)
+	}
+}
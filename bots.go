@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// botTally counts what a bot did in one repository, for --bots-rollup.
+type botTally struct {
+	opened, merged, closed, issues, comments, reviews, pushes, other int
+}
+
+func (t *botTally) add(ev Event) {
+	d := detailsOf(ev)
+	switch {
+	case ev.Type == "PullRequestEvent" && d.Action == "opened":
+		t.opened++
+	case prMerged(ev):
+		t.merged++
+	case ev.Type == "PullRequestEvent" && d.Action == "closed":
+		t.closed++
+	case ev.Type == "IssuesEvent" && d.Action == "opened":
+		t.issues++
+	case ev.Type == "IssueCommentEvent" || ev.Type == "PullRequestReviewCommentEvent":
+		t.comments++
+	case ev.Type == "PullRequestReviewEvent":
+		t.reviews++
+	case ev.Type == "PushEvent":
+		t.pushes++
+	default:
+		t.other++
+	}
+}
+
+func (t botTally) String() string {
+	var parts []string
+	for _, p := range []struct {
+		n         int
+		one, many string
+	}{
+		{t.opened, "PR opened", "PRs opened"},
+		{t.merged, "merged", "merged"},
+		{t.closed, "closed unmerged", "closed unmerged"},
+		{t.issues, "issue opened", "issues opened"},
+		{t.comments, "comment", "comments"},
+		{t.reviews, "review", "reviews"},
+		{t.pushes, "push", "pushes"},
+		{t.other, "other event", "other events"},
+	} {
+		if p.n > 0 {
+			parts = append(parts, plural(p.n, p.one, p.many))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// rollupBots replaces the entries of each bot in each repository with one
+// summary, e.g. "dependabot in acme/app: 7 PRs opened, 5 merged", in the
+// place of its newest entry. Bot events are those isBotEvent finds, so
+// merging a Dependabot PR counts toward dependabot. A bot's only entry in a
+// repository is left as it is.
+func rollupBots(entries []entry) []entry {
+	type key struct{ bot, repo string }
+	tallies := map[key]*botTally{}
+	counts := map[key]int{}
+	for _, e := range entries {
+		if bot := botOf(e.Event); bot != "" {
+			k := key{bot, e.Event.Repo.Name}
+			if tallies[k] == nil {
+				tallies[k] = &botTally{}
+			}
+			tallies[k].add(e.Event)
+			counts[k]++
+		}
+	}
+	var out []entry
+	seen := map[key]bool{}
+	for _, e := range entries {
+		bot := botOf(e.Event)
+		k := key{bot, e.Event.Repo.Name}
+		if bot == "" || counts[k] == 1 {
+			out = append(out, e)
+			continue
+		}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		e.Summary = fmt.Sprintf("%s in %s: %s", strings.TrimSuffix(bot, "[bot]"), k.repo, tallies[k])
+		out = append(out, e)
+	}
+	return out
+}
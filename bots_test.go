@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestRollupBots(t *testing.T) {
+	botPR := func(action string, merged bool, repo string) entry {
+		e := testEntry("PullRequestEvent", repo, "original")
+		e.Event.Actor.Login = "alice"
+		e.Event.Payload = mustRaw(map[string]any{"action": action, "pull_request": map[string]any{
+			"number": 1, "merged": merged, "user": map[string]any{"login": "dependabot[bot]", "type": "Bot"},
+		}})
+		return e
+	}
+	opened := botPR("opened", false, "acme/app")
+	opened.Event.Actor.Login = "dependabot[bot]"
+	human := testEntry("PushEvent", "acme/app", "Pushed 1 commit(s) to acme/app")
+	human.Event.Actor.Login = "alice"
+	renovate := testEntry("PushEvent", "acme/app", "Pushed 1 commit(s) to acme/app")
+	renovate.Event.Actor.Login = "renovate[bot]"
+
+	entries := []entry{
+		botPR("closed", true, "acme/app"),
+		human,
+		opened,
+		botPR("closed", true, "acme/app"),
+		botPR("closed", false, "acme/app"),
+		opened,
+		botPR("closed", true, "acme/lib"),
+		renovate,
+	}
+	got := rollupBots(entries)
+	want := []string{
+		"dependabot in acme/app: 2 PRs opened, 2 merged, 1 closed unmerged",
+		"Pushed 1 commit(s) to acme/app",
+		"original", // a bot's only entry in acme/lib is left alone
+		"Pushed 1 commit(s) to acme/app",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries: %+v", len(got), got)
+	}
+	for i := range want {
+		if got[i].Summary != want[i] {
+			t.Errorf("entry %d: got %q, want %q", i, got[i].Summary, want[i])
+		}
+	}
+	if got[0].Event.Actor.Login != "alice" || got[3].Event.Actor.Login != "renovate[bot]" {
+		t.Errorf("rollup didn't keep the newest entry's event: %+v", got)
+	}
+}
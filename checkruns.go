@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Check badges for --checks.
+const (
+	checkPassed  = "✓"
+	checkFailed  = "✗"
+	checkPending = "•"
+)
+
+// headSHA is the commit a push or pull request event leaves at the head of
+// its branch, or "" for other events.
+func headSHA(ev Event) string {
+	var p struct {
+		Head        string `json:"head"`
+		PullRequest *struct {
+			Head struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if json.Unmarshal(ev.Payload, &p) != nil {
+		return ""
+	}
+	switch ev.Type {
+	case "PushEvent":
+		return p.Head
+	case "PullRequestEvent":
+		if p.PullRequest != nil {
+			return p.PullRequest.Head.SHA
+		}
+	}
+	return ""
+}
+
+// combineChecks reduces commit statuses and check run conclusions to one
+// badge: failed if any failed, pending if any is still running, passed if
+// any passed, and "" if the commit has none. Cancelled runs count as
+// failures, as on GitHub's pull request page; neutral and skipped ones
+// don't count at all.
+func combineChecks(states []string) string {
+	badge := ""
+	for _, s := range states {
+		switch s {
+		case "failure", "error", "timed_out", "cancelled", "action_required", "startup_failure":
+			return checkFailed
+		case "pending", "queued", "in_progress", "waiting", "requested":
+			badge = checkPending
+		case "success":
+			if badge == "" {
+				badge = checkPassed
+			}
+		}
+	}
+	return badge
+}
+
+// commitChecks looks up the badge of a commit from both the statuses API
+// (used by older CI services) and the check runs API (used by Actions).
+func commitChecks(ctx context.Context, repo, sha string) (string, error) {
+	var status struct {
+		Statuses []struct {
+			State string `json:"state"`
+		} `json:"statuses"`
+	}
+	base := apiURL + "/repos/" + repo + "/commits/" + sha
+	if err := getJSON(ctx, base+"/status", &status); err != nil {
+		return "", err
+	}
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := getJSON(ctx, base+"/check-runs?per_page=100", &runs); err != nil {
+		return "", err
+	}
+	var states []string
+	for _, s := range status.Statuses {
+		states = append(states, s.State)
+	}
+	for _, r := range runs.CheckRuns {
+		if r.Status == "completed" {
+			states = append(states, r.Conclusion)
+		} else {
+			states = append(states, r.Status)
+		}
+	}
+	return combineChecks(states), nil
+}
+
+// annotateChecks tags push and pull request summaries with the check badge
+// of their head commit, e.g. "Pushed 2 commits to alice/repo ✓". Commits
+// without checks are left unmarked; lookups that fail are reported as
+// warnings.
+func annotateChecks(ctx context.Context, entries []entry) []entry {
+	out := make([]entry, len(entries))
+	badges := map[string]string{} // repo@sha, for a PR and the push that updated it
+	for i, e := range entries {
+		out[i] = e
+		sha := headSHA(e.Event)
+		if sha == "" {
+			continue
+		}
+		key := e.Event.Repo.Name + "@" + sha
+		badge, ok := badges[key]
+		if !ok {
+			var err error
+			if badge, err = commitChecks(ctx, e.Event.Repo.Name, sha); err != nil {
+				fmt.Fprintf(warnings, "Warning: checks of %s: %v\n", e.Summary, err)
+				continue
+			}
+			badges[key] = badge
+		}
+		if badge != "" {
+			out[i].Summary += " " + badge
+		}
+	}
+	return out
+}
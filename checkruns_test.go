@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCombineChecks(t *testing.T) {
+	for _, c := range []struct {
+		states []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"skipped", "neutral"}, ""},
+		{[]string{"success", "neutral"}, checkPassed},
+		{[]string{"success", "in_progress"}, checkPending},
+		{[]string{"pending", "failure", "success"}, checkFailed},
+		{[]string{"cancelled"}, checkFailed},
+	} {
+		if got := combineChecks(c.states); got != c.want {
+			t.Errorf("combineChecks(%v) = %q, want %q", c.states, got, c.want)
+		}
+	}
+}
+
+func TestAnnotateChecks(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/repos/alice/repo/commits/aaa/status":
+			fmt.Fprint(w, `{"statuses":[{"state":"success"}]}`)
+		case "/repos/alice/repo/commits/aaa/check-runs":
+			fmt.Fprint(w, `{"check_runs":[{"status":"completed","conclusion":"failure"}]}`)
+		case "/repos/alice/repo/commits/bbb/status":
+			fmt.Fprint(w, `{"statuses":[]}`)
+		case "/repos/alice/repo/commits/bbb/check-runs":
+			fmt.Fprint(w, `{"check_runs":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	push := testEntry("PushEvent", "alice/repo", "Pushed 1 commit to alice/repo")
+	push.Event.Payload = mustRaw(map[string]any{"head": "aaa"})
+	pr := testEntry("PullRequestEvent", "alice/repo", "Opened PR #3 in alice/repo")
+	pr.Event.Payload = mustRaw(map[string]any{"pull_request": map[string]any{"head": map[string]any{"sha": "aaa"}}})
+	quiet := testEntry("PushEvent", "alice/repo", "Pushed 1 commit to alice/repo")
+	quiet.Event.Payload = mustRaw(map[string]any{"head": "bbb"})
+	star := testEntry("WatchEvent", "alice/repo", "Starred alice/repo")
+
+	got := annotateChecks(context.Background(), []entry{push, pr, quiet, star})
+	for i, want := range []string{
+		"Pushed 1 commit to alice/repo ✗",
+		"Opened PR #3 in alice/repo ✗",
+		"Pushed 1 commit to alice/repo",
+		"Starred alice/repo",
+	} {
+		if got[i].Summary != want {
+			t.Errorf("entry %d = %q, want %q", i, got[i].Summary, want)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("%d API calls, want 4 (one lookup per commit)", calls)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ciStatus is a repository whose default branch has failing GitHub Actions
+// workflows.
+type ciStatus struct {
+	Repo      string
+	Branch    string
+	Workflows []string  // names of the failing workflows
+	Since     time.Time // when the oldest of their current runs of failures began
+	URL       string    // the newest failed run
+}
+
+// workflowRun is a run from the Actions API, newest first.
+type workflowRun struct {
+	Name       string    `json:"name"`
+	WorkflowID int64     `json:"workflow_id"`
+	Conclusion string    `json:"conclusion"`
+	CreatedAt  time.Time `json:"created_at"`
+	HTMLURL    string    `json:"html_url"`
+}
+
+// failingWorkflows finds the workflows whose latest decided run in runs
+// (newest first) failed. Cancelled and skipped runs decide nothing.
+func failingWorkflows(repo, branch string, runs []workflowRun) (ciStatus, bool) {
+	st := ciStatus{Repo: repo, Branch: branch}
+	decided := map[int64]bool{}
+	for _, run := range runs {
+		if decided[run.WorkflowID] {
+			continue
+		}
+		switch run.Conclusion {
+		case "success":
+			decided[run.WorkflowID] = true
+		case "failure", "timed_out", "startup_failure":
+			if !slices.Contains(st.Workflows, run.Name) {
+				st.Workflows = append(st.Workflows, run.Name)
+			}
+			if st.URL == "" {
+				st.URL = run.HTMLURL
+			}
+			if st.Since.IsZero() || run.CreatedAt.Before(st.Since) {
+				st.Since = run.CreatedAt
+			}
+		}
+	}
+	return st, len(st.Workflows) > 0
+}
+
+// fetchCIStatus checks the recent completed workflow runs on repo's default
+// branch. ok is false when nothing is failing, including for repositories
+// that don't use Actions.
+func fetchCIStatus(ctx context.Context, repo string) (st ciStatus, ok bool, err error) {
+	info, err := lookupRepo(ctx, repo)
+	if err != nil || info.DefaultBranch == "" {
+		return ciStatus{}, false, err
+	}
+	var page struct {
+		WorkflowRuns []workflowRun `json:"workflow_runs"`
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/actions/runs?branch=%s&status=completed&per_page=50", apiURL, repo, url.QueryEscape(info.DefaultBranch))
+	if err := getJSON(ctx, endpoint, &page); errors.Is(err, errNotFound) {
+		return ciStatus{}, false, nil // Actions is disabled or hidden from us
+	} else if err != nil {
+		return ciStatus{}, false, err
+	}
+	st, ok = failingWorkflows(repo, info.DefaultBranch, page.WorkflowRuns)
+	return st, ok, nil
+}
+
+// fetchCIStatuses checks CI on each repository in entries, in the order
+// they first appear, warning about lookups that fail.
+func fetchCIStatuses(ctx context.Context, entries []entry) []ciStatus {
+	var out []ciStatus
+	seen := map[string]bool{}
+	for _, e := range entries {
+		repo := e.Event.Repo.Name
+		if seen[repo] {
+			continue
+		}
+		seen[repo] = true
+		st, ok, err := fetchCIStatus(ctx, repo)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: CI status of %s: %v\n", repo, err)
+			continue
+		}
+		if ok {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// relativeDay names t's day relative to now: "today", "yesterday", a
+// weekday within the last week, or a date.
+func relativeDay(t time.Time) string {
+	today, _, _ := periodBounds("day", now())
+	day, _, _ := periodBounds("day", t)
+	switch days := int(today.Sub(day).Hours()/24 + 0.5); {
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 7:
+		return day.Weekday().String()
+	}
+	return day.Format("Jan 2")
+}
+
+// String describes st, e.g. "CI failing on main since yesterday in
+// acme/app (build, lint)".
+func (st ciStatus) String() string {
+	return fmt.Sprintf("CI failing on %s since %s in %s (%s)", st.Branch, relativeDay(st.Since), st.Repo, strings.Join(st.Workflows, ", "))
+}
+
+// ciAware is implemented by renderers that note CI status alongside a user's
+// activity; --ci-status hands them each user's before feeding the entries.
+type ciAware interface {
+	setCI(user string, statuses []ciStatus)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFailingWorkflows(t *testing.T) {
+	at := func(s string) time.Time { tm, _ := time.Parse(time.RFC3339, s); return tm }
+	runs := []workflowRun{
+		{Name: "build", WorkflowID: 1, Conclusion: "failure", CreatedAt: at("2024-05-03T10:00:00Z"), HTMLURL: "https://github.com/acme/app/actions/runs/9"},
+		{Name: "lint", WorkflowID: 2, Conclusion: "success", CreatedAt: at("2024-05-03T09:00:00Z")},
+		{Name: "build", WorkflowID: 1, Conclusion: "cancelled", CreatedAt: at("2024-05-03T08:00:00Z")},
+		{Name: "build", WorkflowID: 1, Conclusion: "timed_out", CreatedAt: at("2024-05-03T07:00:00Z")},
+		{Name: "lint", WorkflowID: 2, Conclusion: "failure", CreatedAt: at("2024-05-02T07:00:00Z")},
+		{Name: "build", WorkflowID: 1, Conclusion: "success", CreatedAt: at("2024-05-01T07:00:00Z")},
+		{Name: "build", WorkflowID: 1, Conclusion: "failure", CreatedAt: at("2024-04-30T07:00:00Z")},
+	}
+	st, ok := failingWorkflows("acme/app", "main", runs)
+	if !ok || strings.Join(st.Workflows, ",") != "build" || !st.Since.Equal(at("2024-05-03T07:00:00Z")) || st.URL != runs[0].HTMLURL {
+		t.Errorf("failingWorkflows = %+v, %v", st, ok)
+	}
+	if _, ok := failingWorkflows("acme/app", "main", runs[1:2]); ok {
+		t.Error("passing workflow reported as failing")
+	}
+}
+
+func TestRelativeDay(t *testing.T) {
+	pinClock(t) // Saturday, May 4 2024
+	for in, want := range map[string]string{
+		"2024-05-04T01:00:00Z": "today",
+		"2024-05-03T23:00:00Z": "yesterday",
+		"2024-04-29T12:00:00Z": "Monday",
+		"2024-04-20T12:00:00Z": "Apr 20",
+	} {
+		tm, _ := time.Parse(time.RFC3339, in)
+		if got := relativeDay(tm); got != want {
+			t.Errorf("relativeDay(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFetchCIStatuses(t *testing.T) {
+	pinClock(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/app":
+			fmt.Fprint(w, `{"default_branch":"main"}`)
+		case "/repos/acme/app/actions/runs":
+			if r.URL.Query().Get("branch") != "main" {
+				t.Errorf("branch = %q", r.URL.Query().Get("branch"))
+			}
+			fmt.Fprint(w, `{"workflow_runs":[{"name":"build","workflow_id":1,"conclusion":"failure","created_at":"2024-05-03T10:00:00Z","html_url":"https://github.com/acme/app/actions/runs/9"}]}`)
+		case "/repos/bob/lib":
+			fmt.Fprint(w, `{"default_branch":"trunk"}`)
+		default:
+			http.NotFound(w, r) // bob/lib has Actions disabled
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+	repoInfoCache.m = map[string]repoInfo{}
+
+	entries := []entry{
+		testEntry("PushEvent", "acme/app", "Pushed 1 commit to acme/app"),
+		testEntry("PushEvent", "bob/lib", "Pushed 1 commit to bob/lib"),
+		testEntry("IssuesEvent", "acme/app", "Opened an issue in acme/app"),
+	}
+	statuses := fetchCIStatuses(context.Background(), entries)
+	if len(statuses) != 1 {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+	if got, want := statuses[0].String(), "CI failing on main since yesterday in acme/app (build)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	var b strings.Builder
+	r := &textRenderer{w: &b}
+	r.setCI("alice", statuses)
+	r.feed("alice", []Event{entries[0].Event}, entries[:1])
+	if want := "- Pushed 1 commit to acme/app\n! CI failing on main since yesterday in acme/app (build)\n"; b.String() != want {
+		t.Errorf("text output = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	d := &digestRenderer{w: &b}
+	d.setCI("alice", statuses)
+	d.feed("alice", nil, entries[:1])
+	d.flush()
+	if out := b.String(); !strings.Contains(out, "! CI failing on main since yesterday in acme/app (build) [2]\n") ||
+		!strings.Contains(out, "[2] https://github.com/acme/app/actions/runs/9\n") {
+		t.Errorf("digest output:\n%s", out)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// codeownersLocations are where GitHub looks for a CODEOWNERS file, in order.
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeownersRule is one line of a CODEOWNERS file.
+type codeownersRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// parseCodeowners reads CODEOWNERS rules, skipping comments and blank lines.
+func parseCodeowners(r io.Reader) []codeownersRule {
+	var rules []codeownersRule
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, codeownersRule{Pattern: fields[0], Owners: fields[1:]})
+	}
+	return rules
+}
+
+// ownersOf returns the owners of a file: those of the last matching rule, as
+// on GitHub. A matching rule without owners leaves the file unowned.
+func ownersOf(rules []codeownersRule, file string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if codeownersMatch(rules[i].Pattern, file) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// codeownersMatch applies CODEOWNERS (gitignore-style) pattern rules: a
+// pattern with no inner slash matches at any depth, a leading slash anchors
+// it to the root, and a pattern naming a directory covers everything in it.
+func codeownersMatch(pattern, file string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.Trim(pattern, "/")
+	if p == "" {
+		return false
+	}
+	if !strings.HasPrefix(pattern, "/") && !strings.Contains(p, "/") {
+		p = "**/" + p
+	}
+	return matchPath(p+"/*/**", file) || (!dirOnly && matchPath(p, file))
+}
+
+// fetchCodeowners downloads a repository's CODEOWNERS file from the first of
+// the standard locations that has one.
+func fetchCodeowners(ctx context.Context, repo string) ([]codeownersRule, error) {
+	for _, loc := range codeownersLocations {
+		var file struct {
+			Content  string `json:"content"`
+			Encoding string `json:"encoding"`
+		}
+		err := getJSON(ctx, apiURL+"/repos/"+repo+"/contents/"+loc, &file)
+		if errors.Is(err, errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if file.Encoding != "base64" {
+			return nil, fmt.Errorf("%s: unexpected encoding %q", loc, file.Encoding)
+		}
+		// The API wraps base64 content at 60 columns.
+		data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", loc, err)
+		}
+		return parseCodeowners(strings.NewReader(string(data))), nil
+	}
+	return nil, fmt.Errorf("%s has no CODEOWNERS file", repo)
+}
+
+// ownedActivity is an event touching files a team owns.
+type ownedActivity struct {
+	Event   Event
+	Summary string
+	Files   []string // the team's files the change touched
+}
+
+// teamActivity keeps the pushes and pull requests that changed files owned
+// by team, up to limit. Changes whose files can't be fetched are reported as
+// warnings and skipped.
+func teamActivity(ctx context.Context, events []Event, rules []codeownersRule, team string, limit int) []ownedActivity {
+	var out []ownedActivity
+	for _, ev := range events {
+		if len(out) >= limit {
+			break
+		}
+		files, ok, err := changedFiles(ctx, ev)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: files of %s event in %s: %v\n", ev.Type, ev.Repo.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		var owned []string
+		for _, f := range files {
+			if containsFold(ownersOf(rules, f), team) {
+				owned = append(owned, f)
+			}
+		}
+		summary, known := formatEvent(ev)
+		if len(owned) > 0 && known {
+			out = append(out, ownedActivity{Event: ev, Summary: summary, Files: owned})
+		}
+	}
+	return out
+}
+
+func runOwnersFeed(args []string) int {
+	fs := flag.NewFlagSet("owners-feed", flag.ExitOnError)
+	repoArg := fs.String("repo", "", "Repository whose CODEOWNERS and activity to use, as <owner>/<repo> (required).")
+	team := fs.String("owner-team", "", "Code owner to follow, as written in CODEOWNERS, e.g. @acme/platform or @alice (required).")
+	limit := fs.Int("n", 20, "Max number of events to show.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s owners-feed --repo=<owner>/<repo> --owner-team=<@org/team>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Shows recent pushes and pull requests in a repository that touched files the")
+		fmt.Fprintln(fs.Output(), "given code owner is responsible for, according to the repository's CODEOWNERS.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 || *repoArg == "" || *team == "" {
+		fs.Usage()
+		return 2
+	}
+	repo, err := parseRepo(*repoArg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	owner := *team
+	if !strings.HasPrefix(owner, "@") && !strings.Contains(owner, "@") {
+		owner = "@" + owner // allow acme/platform for @acme/platform
+	}
+
+	ctx := context.Background()
+	rules, err := fetchCodeowners(ctx, repo)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	events, err := fetchRepoEvents(ctx, repo)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	printOwnedActivity(os.Stdout, *repoArg, owner, teamActivity(ctx, events, rules, owner, *limit))
+	return 0
+}
+
+func printOwnedActivity(w io.Writer, repo, team string, activity []ownedActivity) {
+	if len(activity) == 0 {
+		fmt.Fprintf(w, "No recent changes in %s touched files owned by %s.\n", repo, team)
+		return
+	}
+	fmt.Fprintf(w, "Recent changes in %s touching %s's files:\n", repo, team)
+	for _, a := range activity {
+		files := a.Files[0]
+		if len(a.Files) > 1 {
+			files += fmt.Sprintf(", +%d more", len(a.Files)-1)
+		}
+		fmt.Fprintf(w, "- %s %s: %s (%s)\n", a.Event.CreatedAt.Local().Format("2006-01-02"), a.Event.Actor.Login, a.Summary, files)
+	}
+}
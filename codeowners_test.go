@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testCodeowners = `# Default owners
+*                 @acme/core
+*.md              @acme/docs
+/services/auth/   @acme/platform @alice
+services/billing  @acme/payments
+/vendor/          # unowned
+`
+
+func TestOwnersOf(t *testing.T) {
+	rules := parseCodeowners(strings.NewReader(testCodeowners))
+	if len(rules) != 5 {
+		t.Fatalf("got %d rules", len(rules))
+	}
+	tests := map[string]string{
+		"main.go":                     "@acme/core",
+		"docs/guide.md":               "@acme/docs",
+		"services/auth/token.go":      "@acme/platform,@alice",
+		"services/auth/README.md":     "@acme/platform,@alice", // later rule wins
+		"services/authz/main.go":      "@acme/core",
+		"services/billing/invoice.go": "@acme/payments",
+		"vendor/lib/x.go":             "",
+	}
+	for file, want := range tests {
+		if got := strings.Join(ownersOf(rules, file), ","); got != want {
+			t.Errorf("%s: got %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestTeamActivity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/mono/contents/.github/CODEOWNERS":
+			http.NotFound(w, r)
+		case "/repos/acme/mono/contents/CODEOWNERS":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"encoding": "base64",
+				"content":  base64.StdEncoding.EncodeToString([]byte(testCodeowners)),
+			})
+		case "/repos/acme/mono/compare/a1...a2":
+			_, _ = w.Write([]byte(`{"files":[{"filename":"services/auth/main.go"},{"filename":"go.mod"}]}`))
+		case "/repos/acme/mono/compare/b1...b2":
+			_, _ = w.Write([]byte(`{"files":[{"filename":"services/billing/main.go"}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	rules, err := fetchCodeowners(context.Background(), "acme/mono")
+	if err != nil {
+		t.Fatal(err)
+	}
+	push := func(before, head string) Event {
+		ev := Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"before": before, "head": head, "size": 1})}
+		ev.Repo.Name = "acme/mono"
+		ev.Actor.Login = "bob"
+		return ev
+	}
+	got := teamActivity(context.Background(), []Event{push("a1", "a2"), {Type: "WatchEvent"}, push("b1", "b2")}, rules, "@ACME/platform", 10)
+	if len(got) != 1 || strings.Join(got[0].Files, ",") != "services/auth/main.go" {
+		t.Fatalf("got %+v", got)
+	}
+
+	var b strings.Builder
+	printOwnedActivity(&b, "acme/mono", "@acme/platform", got)
+	if !strings.Contains(b.String(), "bob: Pushed 1 commit(s) to acme/mono (services/auth/main.go)") {
+		t.Errorf("got:\n%s", b.String())
+	}
+}
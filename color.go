@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ANSI SGR codes used by the palette.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// typeColors assigns each event type a color; unlisted types stay plain.
+var typeColors = map[string]string{
+	"PushEvent":                     ansiGreen,
+	"PullRequestEvent":              ansiMagenta,
+	"PullRequestReviewCommentEvent": ansiMagenta,
+	"IssuesEvent":                   ansiYellow,
+	"IssueCommentEvent":             ansiYellow,
+	"WatchEvent":                    ansiYellow,
+	"ForkEvent":                     ansiCyan,
+	"CreateEvent":                   ansiCyan,
+	"DeleteEvent":                   ansiRed,
+	"ReleaseEvent":                  ansiCyan,
+	"DeploymentEvent":               ansiBlue,
+	"DeploymentStatusEvent":         ansiBlue,
+}
+
+// palette colors pieces of output and, with links, turns repositories and
+// issue/PR numbers into OSC 8 terminal hyperlinks. The zero value returns
+// text unchanged, so renderers can use it unconditionally.
+type palette struct {
+	enabled bool
+	links   bool
+}
+
+func (p palette) wrap(code, s string) string {
+	if !p.enabled || code == "" || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// link makes s a hyperlink to url in terminals that support OSC 8; others
+// ignore the escape and show s.
+func (p palette) link(url, s string) string {
+	if !p.links || url == "" || s == "" {
+		return s
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + s + "\x1b]8;;\x1b\\"
+}
+
+func (p palette) eventType(typ, s string) string { return p.wrap(typeColors[typ], s) }
+func (p palette) repo(s string) string           { return p.wrap(ansiBold+ansiBlue, s) }
+func (p palette) number(s string) string         { return p.wrap(ansiBold, s) }
+func (p palette) dim(s string) string            { return p.wrap(ansiDim, s) }
+
+// summary colors an entry's summary: the leading verb in its event type's
+// color, plus the repository name and issue/PR number, which are also linked.
+func (p palette) summary(e entry) string {
+	line := e.Summary
+	if !p.enabled && !p.links {
+		return line
+	}
+	if repo := e.Event.Repo.Name; repo != "" {
+		line = strings.ReplaceAll(line, repo, p.link(repoURL(repo), p.repo(repo)))
+	}
+	if d := detailsOf(e.Event); d.Number != 0 {
+		num := fmt.Sprintf("#%d", d.Number)
+		line = strings.Replace(line, num, p.link(entityURL(e.Event), p.number(num)), 1)
+	}
+	if verb, rest, ok := strings.Cut(line, " "); ok {
+		line = p.eventType(e.Event.Type, verb) + " " + rest
+	}
+	return line
+}
+
+// resolveColor decides whether to color output written to f for a --color
+// mode. In auto mode, NO_COLOR (https://no-color.org) and TERM=dumb turn
+// color off, and otherwise color is used only on terminals.
+func resolveColor(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		return isTerminal(f), nil
+	}
+	return false, fmt.Errorf("invalid --color %q (want auto, always, or never)", mode)
+}
+
+// resolveHyperlinks decides whether to emit OSC 8 hyperlinks to f for a
+// --hyperlinks mode. Terminals that don't understand OSC 8 may print it as
+// garbage, so auto mode only enables links for terminals known to support it.
+func resolveHyperlinks(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		return isTerminal(f) && supportsHyperlinks(), nil
+	}
+	return false, fmt.Errorf("invalid --hyperlinks %q (want auto, always, or never)", mode)
+}
+
+// supportsHyperlinks recognizes OSC 8 capable terminals from the
+// environment variables they set.
+func supportsHyperlinks() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, v := range []string{"WT_SESSION", "KITTY_WINDOW_ID", "WEZTERM_EXECUTABLE", "KONSOLE_VERSION", "DOMTERM"} {
+		if os.Getenv(v) != "" {
+			return true
+		}
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty", "Tabby":
+		return true
+	}
+	// VTE (GNOME Terminal, Tilix, ...) gained OSC 8 in 0.50.
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	switch os.Getenv("TERM") {
+	case "xterm-kitty", "alacritty", "foot", "xterm-ghostty":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPaletteSummary(t *testing.T) {
+	ev := Event{Type: "IssuesEvent", Payload: mustRaw(map[string]any{
+		"action": "opened",
+		"issue":  map[string]any{"number": 42, "title": "Crash"},
+	})}
+	ev.Repo.Name = "alice/repo"
+	e := entry{User: "alice", Event: ev, Summary: "Opened issue #42 in alice/repo"}
+
+	if got := (palette{}).summary(e); got != e.Summary {
+		t.Errorf("disabled palette changed summary: %q", got)
+	}
+
+	got := palette{enabled: true}.summary(e)
+	for _, want := range []string{
+		ansiYellow + "Opened" + ansiReset,
+		ansiBold + "#42" + ansiReset,
+		ansiBold + ansiBlue + "alice/repo" + ansiReset,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary %q missing %q", got, want)
+		}
+	}
+}
+
+func TestPaletteSummaryLinks(t *testing.T) {
+	ev := Event{Type: "PullRequestEvent", Payload: mustRaw(map[string]any{
+		"action":       "opened",
+		"pull_request": map[string]any{"number": 7, "title": "Fix"},
+	})}
+	ev.Repo.Name = "alice/repo"
+	e := entry{User: "alice", Event: ev, Summary: "Opened PR #7 in alice/repo"}
+
+	got := palette{links: true}.summary(e)
+	want := "Opened PR " +
+		"\x1b]8;;https://github.com/alice/repo/pull/7\x1b\\#7\x1b]8;;\x1b\\" +
+		" in " +
+		"\x1b]8;;https://github.com/alice/repo\x1b\\alice/repo\x1b]8;;\x1b\\"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestSupportsHyperlinks(t *testing.T) {
+	for _, v := range []string{"WT_SESSION", "KITTY_WINDOW_ID", "WEZTERM_EXECUTABLE", "KONSOLE_VERSION", "DOMTERM", "TERM_PROGRAM", "VTE_VERSION"} {
+		t.Setenv(v, "")
+	}
+	t.Setenv("TERM", "xterm-256color")
+	if supportsHyperlinks() {
+		t.Error("plain xterm reported as supporting hyperlinks")
+	}
+	t.Setenv("VTE_VERSION", "6003")
+	if !supportsHyperlinks() {
+		t.Error("VTE 0.60 not recognized")
+	}
+	t.Setenv("VTE_VERSION", "")
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	if !supportsHyperlinks() {
+		t.Error("iTerm not recognized")
+	}
+	t.Setenv("TERM", "dumb")
+	if supportsHyperlinks() {
+		t.Error("TERM=dumb reported as supporting hyperlinks")
+	}
+}
+
+func TestTableColorKeepsAlignment(t *testing.T) {
+	var plain, colored strings.Builder
+	entries := []entry{
+		testEntry("PushEvent", "alice/repo", "Pushed 1 commit(s) to alice/repo"),
+		testEntry("ForkEvent", "bob/x", "Forked bob/x"),
+	}
+	for _, c := range []struct {
+		w     *strings.Builder
+		color bool
+	}{{&plain, false}, {&colored, true}} {
+		r, err := newRenderer(c.w, outputOptions{Format: "table", Width: 80, Color: c.color})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.feed("alice", nil, entries)
+		if err := r.flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.Contains(colored.String(), "\x1b[") {
+		t.Fatalf("no escape codes in colored table:\n%s", colored.String())
+	}
+	if got := stripANSI(colored.String()); got != plain.String() {
+		t.Errorf("colored table differs from plain once codes are stripped:\n%s\nwant:\n%s", got, plain.String())
+	}
+}
+
+func stripANSI(s string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "\x1b[")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		s = s[strings.IndexByte(s, 'm')+1:]
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	// A regular file is never a terminal.
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+	tests := []struct {
+		mode    string
+		noColor string
+		want    bool
+	}{
+		{"always", "1", true},
+		{"never", "", false},
+		{"auto", "", false},
+		{"auto", "1", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		got, err := resolveColor(tt.mode, f)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveColor(%q) with NO_COLOR=%q = %v, %v; want %v", tt.mode, tt.noColor, got, err, tt.want)
+		}
+	}
+	if _, err := resolveColor("sometimes", f); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	if on, err := resolveHyperlinks("auto", f); on || err != nil {
+		t.Errorf("hyperlinks on a regular file: %v, %v", on, err)
+	}
+	if on, err := resolveHyperlinks("always", f); !on || err != nil {
+		t.Errorf("--hyperlinks=always: %v, %v", on, err)
+	}
+	if _, err := resolveHyperlinks("maybe", f); err == nil {
+		t.Error("expected error for invalid --hyperlinks")
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Commit is one commit from a push, with the message's first line kept
+// separately for listings.
+type Commit struct {
+	SHA     string
+	Repo    string
+	Author  string
+	Message string
+}
+
+// URL links to the commit on GitHub.
+func (c Commit) URL() string { return repoURL(c.Repo) + "/commit/" + c.SHA }
+
+// Subject is the first line of the commit message.
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// pushCommitsPayload is the part of a PushEvent payload that identifies its
+// commits. GitHub no longer always inlines commits, in which case they are
+// fetched with the compare API from before and head.
+type pushCommitsPayload struct {
+	Before  string `json:"before"`
+	Head    string `json:"head"`
+	Commits []struct {
+		SHA     string `json:"sha"`
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commits"`
+}
+
+// apiCommit is a commit as returned by the commits and compare APIs.
+type apiCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+func runCommits(args []string) int {
+	fs := flag.NewFlagSet("commits", flag.ExitOnError)
+	grep := fs.String("grep", "", "Only list commits whose message matches this regular expression (use (?i) to ignore case).")
+	limit := fs.Int("n", 50, "Max number of commits to list.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s commits [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Expands the user's recent pushes into individual commits and lists them, optionally filtered by message.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+	// Allow flags after the username too, as in "commits alice --grep=fix".
+	user := fs.Arg(0)
+	_ = fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := applyConnectionFlags(fs); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	re, err := regexp.Compile(*grep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --grep: %v\n", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	events, err := fetchEventPages(ctx, user, pageOptions{PerPage: 100})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	var matches []Commit
+	seen := map[string]bool{} // the same commit can be pushed to several branches
+	for _, ev := range events {
+		if ev.Type != "PushEvent" {
+			continue
+		}
+		commits, err := pushCommits(ctx, ev)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: %s: %v\n", ev.Repo.Name, err)
+			continue
+		}
+		for _, c := range commits {
+			if seen[c.SHA] || !re.MatchString(c.Message) {
+				continue
+			}
+			seen[c.SHA] = true
+			matches = append(matches, c)
+		}
+		if len(matches) >= *limit {
+			matches = matches[:*limit]
+			break
+		}
+	}
+	printCommits(os.Stdout, matches)
+	return 0
+}
+
+// pushCommits returns the commits of a PushEvent, newest first. Inline
+// payload commits are used when present; otherwise they are looked up.
+func pushCommits(ctx context.Context, ev Event) ([]Commit, error) {
+	var p pushCommitsPayload
+	if err := json.Unmarshal(ev.Payload, &p); err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	if len(p.Commits) > 0 {
+		for _, c := range p.Commits {
+			commits = append(commits, Commit{SHA: c.SHA, Repo: ev.Repo.Name, Author: c.Author.Name, Message: c.Message})
+		}
+	} else if p.Head != "" {
+		api, err := fetchPushCommits(ctx, ev.Repo.Name, p.Before, p.Head)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range api {
+			commits = append(commits, Commit{SHA: c.SHA, Repo: ev.Repo.Name, Author: c.Commit.Author.Name, Message: c.Commit.Message})
+		}
+	}
+	// Payloads and the compare API list commits oldest first.
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// fetchPushCommits lists the commits between before and head. A branch
+// creation has no usable before, so only the head commit is returned.
+func fetchPushCommits(ctx context.Context, repo, before, head string) ([]apiCommit, error) {
+	if before == "" || strings.Trim(before, "0") == "" {
+		var c apiCommit
+		if err := getJSON(ctx, apiURL+"/repos/"+repo+"/commits/"+head, &c); err != nil {
+			return nil, err
+		}
+		return []apiCommit{c}, nil
+	}
+	var cmp struct {
+		Commits []apiCommit `json:"commits"`
+	}
+	if err := getJSON(ctx, apiURL+"/repos/"+repo+"/compare/"+before+"..."+head, &cmp); err != nil {
+		return nil, err
+	}
+	return cmp.Commits, nil
+}
+
+func printCommits(w io.Writer, commits []Commit) {
+	if len(commits) == 0 {
+		fmt.Fprintln(w, "No matching commits found.")
+		return
+	}
+	for _, c := range commits {
+		sha := c.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		fmt.Fprintf(w, "- %s %s: %s\n  %s\n", sha, c.Repo, c.Subject(), c.URL())
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPushCommits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/alice/repo/compare/aaa...ccc":
+			_, _ = w.Write([]byte(`{"commits":[
+				{"sha":"bbb","commit":{"message":"fix race in watcher\n\nDetails","author":{"name":"Alice"}}},
+				{"sha":"ccc","commit":{"message":"add docs","author":{"name":"Alice"}}}]}`))
+		case "/repos/alice/repo/commits/ddd":
+			_, _ = w.Write([]byte(`{"sha":"ddd","commit":{"message":"initial","author":{"name":"Alice"}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	push := func(payload map[string]any) Event {
+		ev := Event{Type: "PushEvent", Payload: mustRaw(payload)}
+		ev.Repo.Name = "alice/repo"
+		return ev
+	}
+	tests := []struct {
+		name string
+		ev   Event
+		want []string
+	}{
+		{"inline", push(map[string]any{"commits": []map[string]any{
+			{"sha": "111", "message": "one"}, {"sha": "222", "message": "two"},
+		}}), []string{"222", "111"}},
+		{"compare", push(map[string]any{"before": "aaa", "head": "ccc"}), []string{"ccc", "bbb"}},
+		{"new branch", push(map[string]any{"before": "0000000000000000000000000000000000000000", "head": "ddd"}), []string{"ddd"}},
+	}
+	for _, tt := range tests {
+		commits, err := pushCommits(context.Background(), tt.ev)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, c := range commits {
+			got = append(got, c.SHA)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrintCommits(t *testing.T) {
+	var b strings.Builder
+	printCommits(&b, []Commit{{SHA: "0123456789", Repo: "alice/repo", Message: "fix race\n\nbody"}})
+	want := "- 0123456 alice/repo: fix race\n  https://github.com/alice/repo/commit/0123456789\n"
+	if b.String() != want {
+		t.Errorf("got %q want %q", b.String(), want)
+	}
+	b.Reset()
+	printCommits(&b, nil)
+	if b.String() != "No matching commits found.\n" {
+		t.Errorf("empty: %q", b.String())
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// configPath is the file flag defaults are read from: $GITHUB_ACTIVITY_CONFIG,
+// or github-activity/config.json in the user's config directory
+// (~/.config on Linux, ~/Library/Application Support on macOS, %AppData% on
+// Windows). It returns "" when there is no config directory.
+func configPath() string {
+	if p := os.Getenv("GITHUB_ACTIVITY_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "github-activity", "config.json")
+}
+
+// loadConfig reads a config file: a JSON object whose keys are flag names
+// of the main command, plus one object per subcommand for its flags, e.g.
+//
+//	{"emoji": true, "n": 10, "actor": ["alice", "bob"], "tags": {"n": 5}}
+//
+// A missing file is an empty config.
+func loadConfig(path string) (map[string]json.RawMessage, error) {
+	cfg := map[string]json.RawMessage{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// configSection picks the settings for the command fs belongs to: the
+// top-level keys for the main command, whose flag set is named programName,
+// or the top-level connection flags overlaid with the object under the
+// subcommand's name. (Looking names up in commands here would be an
+// initialization cycle, but no main-command flag takes an object, so objects
+// can be skipped instead.)
+func configSection(cfg map[string]json.RawMessage, fs *flag.FlagSet) (map[string]json.RawMessage, error) {
+	section := map[string]json.RawMessage{}
+	if fs.Name() == programName {
+		for k, v := range cfg {
+			if !bytes.HasPrefix(bytes.TrimSpace(v), []byte("{")) {
+				section[k] = v
+			}
+		}
+		return section, nil
+	}
+	for k, v := range cfg {
+		if slices.Contains(connectionFlags, k) {
+			section[k] = v
+		}
+	}
+	if raw, ok := cfg[fs.Name()]; ok {
+		if err := json.Unmarshal(raw, &section); err != nil {
+			return nil, fmt.Errorf("%q: want an object of flag settings", fs.Name())
+		}
+	}
+	return section, nil
+}
+
+// applyConfig sets flags in fs from section before the command line is
+// parsed, so explicit flags still win. Arrays set repeatable flags once per
+// element.
+func applyConfig(fs *flag.FlagSet, section map[string]json.RawMessage) error {
+	for name, raw := range section {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown option %q", name)
+		}
+		var values []json.RawMessage
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return fmt.Errorf("%q: %w", name, err)
+			}
+		} else {
+			values = []json.RawMessage{raw}
+		}
+		for _, v := range values {
+			var s string
+			if json.Unmarshal(v, &s) != nil {
+				s = string(bytes.TrimSpace(v)) // true, 10, 1.5
+			}
+			if err := fs.Set(name, s); err != nil {
+				return fmt.Errorf("%q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyConfigFile loads the config file and applies its settings for fs.
+func applyConfigFile(fs *flag.FlagSet) error {
+	path := configPath()
+	cfg, err := loadConfig(path)
+	var section map[string]json.RawMessage
+	if err == nil {
+		section, err = configSection(cfg, fs)
+	}
+	if err == nil {
+		err = applyConfig(fs, section)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
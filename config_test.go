@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("GITHUB_ACTIVITY_CONFIG", path)
+	write := func(s string) {
+		if err := os.WriteFile(path, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newRoot := func() (*flag.FlagSet, *bool, *int, *string, *stringList) {
+		fs := flag.NewFlagSet(programName, flag.ContinueOnError)
+		var actors stringList
+		fs.Var(&actors, "actor", "")
+		return fs, fs.Bool("emoji", false, ""), fs.Int("n", 30, ""), fs.String("format", "text", ""), &actors
+	}
+
+	// A missing file changes nothing.
+	fs, emoji, n, _, _ := newRoot()
+	if err := applyConfigFile(fs); err != nil || *emoji || *n != 30 {
+		t.Fatalf("missing file: %v %v %v", err, *emoji, *n)
+	}
+
+	write(`{"emoji": true, "n": 10, "format": "table", "actor": ["alice", "bob"], "tags": {"n": 5}}`)
+	fs, emoji, n, format, actors := newRoot()
+	if err := applyConfigFile(fs); err != nil {
+		t.Fatal(err)
+	}
+	if !*emoji || *n != 10 || *format != "table" || strings.Join(*actors, ",") != "alice,bob" {
+		t.Errorf("got emoji=%v n=%d format=%q actors=%v", *emoji, *n, *format, *actors)
+	}
+	// Explicit flags still win.
+	if err := fs.Parse([]string{"--n=3"}); err != nil || *n != 3 {
+		t.Errorf("command line override: n=%d, %v", *n, err)
+	}
+
+	tags := flag.NewFlagSet("tags", flag.ContinueOnError)
+	limit := tags.Int("n", 10, "")
+	if err := applyConfigFile(tags); err != nil || *limit != 5 {
+		t.Errorf("tags section: n=%d, %v", *limit, err)
+	}
+
+	write(`{"colour": "never"}`)
+	fs, _, _, _, _ = newRoot()
+	if err := applyConfigFile(fs); err == nil || !strings.Contains(err.Error(), `unknown option "colour"`) {
+		t.Errorf("unknown key: %v", err)
+	}
+	write(`{"n": "many"}`)
+	fs, _, _, _, _ = newRoot()
+	if err := applyConfigFile(fs); err == nil {
+		t.Error("expected error for invalid value")
+	}
+	write(`{"n": `)
+	fs, _, _, _, _ = newRoot()
+	if err := applyConfigFile(fs); err == nil || !strings.HasPrefix(err.Error(), path) {
+		t.Errorf("malformed file: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// conventionalPrefix matches a Conventional Commits subject such as
+// "feat(api)!: add pagination", capturing the type.
+var conventionalPrefix = regexp.MustCompile(`^([A-Za-z]+)(\([^)]*\))?!?: `)
+
+// commitType returns the lowercased Conventional Commits type of a commit
+// message, or "other" when it doesn't follow the convention.
+func commitType(message string) string {
+	if m := conventionalPrefix.FindStringSubmatch(message); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return "other"
+}
+
+// userCommits expands the user's pushes into their distinct commits. Pushes
+// whose commits can't be fetched are reported as warnings and skipped.
+func userCommits(ctx context.Context, events []Event) []Commit {
+	var all []Commit
+	seen := map[string]bool{} // the same commit can be pushed to several branches
+	for _, ev := range events {
+		if ev.Type != "PushEvent" {
+			continue
+		}
+		commits, err := pushCommits(ctx, ev)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: %s: %v\n", ev.Repo.Name, err)
+			continue
+		}
+		for _, c := range commits {
+			if !seen[c.SHA] {
+				seen[c.SHA] = true
+				all = append(all, c)
+			}
+		}
+	}
+	return all
+}
+
+func printCommitTypes(w io.Writer, user string, commits []Commit) {
+	if len(commits) == 0 {
+		fmt.Fprintf(w, "%s pushed no commits recently.\n", user)
+		return
+	}
+	var types []string
+	for _, c := range commits {
+		types = append(types, commitType(c.Message))
+	}
+	fmt.Fprintf(w, "%s: %d commits by type:\n", user, len(commits))
+	printCounts(w, rank(types))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCommitType(t *testing.T) {
+	for msg, want := range map[string]string{
+		"feat: add --sizes":             "feat",
+		"fix(api): retry on 502":        "fix",
+		"Feat(ui)!: drop old flags":     "feat",
+		"chore!: bump go":               "chore",
+		"docs: readme\n\nfix: not this": "docs",
+		"Merge branch 'main'":           "other",
+		"fix:missing space":             "other",
+		"update(deps) things: whatever": "other",
+	} {
+		if got := commitType(msg); got != want {
+			t.Errorf("commitType(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestPrintCommitTypes(t *testing.T) {
+	push := func(msgs ...string) Event {
+		var commits []map[string]any
+		for i, m := range msgs {
+			commits = append(commits, map[string]any{"sha": m[:3] + string(rune('a'+i)), "message": m})
+		}
+		ev := Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"commits": commits})}
+		ev.Repo.Name = "alice/repo"
+		return ev
+	}
+	// The second push repeats the first push's commit on another branch.
+	events := []Event{push("fix: a", "feat: b"), push("fix: a"), push("docs: c", "wip")}
+	var b strings.Builder
+	printCommitTypes(&b, "alice", userCommits(context.Background(), events))
+	want := "alice: 4 commits by type:\n  docs   1\n  feat   1\n  fix    1\n  other  1\n"
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	printCommitTypes(&b, "alice", nil)
+	if got := b.String(); got != "alice pushed no commits recently.\n" {
+		t.Errorf("empty: %q", got)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// daemonName names the background watch: the systemd unit and the Windows
+// scheduled task. launchd jobs go by daemonLabel instead.
+const (
+	daemonName  = "github-activity-watch"
+	daemonLabel = "com.github-activity.watch"
+)
+
+// daemonEnv lists the environment variables a service keeps from the shell
+// that installs it, so it reads the same config and tokens file. Secrets
+// such as $GITHUB_TOKEN are left out of the service definition; the service
+// uses the auth login token instead.
+var daemonEnv = []string{"GITHUB_ACTIVITY_CONFIG", "GITHUB_ACTIVITY_TOKENS", "GITHUB_ACTIVITY_NO_KEYRING", "GITHUB_API_URL"}
+
+// daemonSetup is how one platform's service manager runs watch in the
+// background.
+type daemonSetup struct {
+	File    string     // service definition to write; empty when the manager keeps it
+	Content string     // its contents
+	Stop    [][]string // stop a running instance; failures are ignored, as there may be none
+	Start   [][]string // register and start the service once File is written
+	Remove  [][]string // unregister it before File is removed
+	Reload  []string   // make the manager reread its files after a change
+	Status  []string
+}
+
+// daemonSetupFor describes running exe watch args as a service on goos for
+// the user whose home and config directories are given: a systemd user unit
+// on Linux and other Unixes, a launchd agent on macOS, and a scheduled task
+// started at logon on Windows. env holds "NAME=value" pairs for the service.
+func daemonSetupFor(goos, home, configDir, exe string, args, env []string) daemonSetup {
+	cmdline := append([]string{exe, "watch"}, args...)
+	switch goos {
+	case "darwin":
+		path := filepath.Join(home, "Library", "LaunchAgents", daemonLabel+".plist")
+		return daemonSetup{
+			File:    path,
+			Content: launchdPlist(cmdline, env, filepath.Join(home, "Library", "Logs", daemonName+".log")),
+			Stop:    [][]string{{"launchctl", "unload", path}},
+			Start:   [][]string{{"launchctl", "load", "-w", path}},
+			Remove:  [][]string{{"launchctl", "unload", "-w", path}},
+			Status:  []string{"launchctl", "list", daemonLabel},
+		}
+	case "windows":
+		return daemonSetup{
+			Stop: [][]string{{"schtasks", "/End", "/TN", daemonName}},
+			Start: [][]string{
+				{"schtasks", "/Create", "/F", "/TN", daemonName, "/SC", "ONLOGON", "/TR", windowsCommandLine(cmdline)},
+				{"schtasks", "/Run", "/TN", daemonName},
+			},
+			Remove: [][]string{{"schtasks", "/Delete", "/F", "/TN", daemonName}},
+			Status: []string{"schtasks", "/Query", "/V", "/FO", "LIST", "/TN", daemonName},
+		}
+	}
+	unit := daemonName + ".service"
+	return daemonSetup{
+		File:    filepath.Join(configDir, "systemd", "user", unit),
+		Content: systemdUnit(cmdline, env),
+		Start:   [][]string{{"systemctl", "--user", "enable", unit}, {"systemctl", "--user", "restart", unit}},
+		Remove:  [][]string{{"systemctl", "--user", "disable", "--now", unit}},
+		Reload:  []string{"systemctl", "--user", "daemon-reload"},
+		Status:  []string{"systemctl", "--user", "status", unit},
+	}
+}
+
+// systemdUnit is a user unit running cmdline, restarted if it fails.
+func systemdUnit(cmdline, env []string) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\nDescription=GitHub activity watch\nWants=network-online.target\nAfter=network-online.target\n\n[Service]\n")
+	quoted := make([]string, len(cmdline))
+	for i, arg := range cmdline {
+		quoted[i] = systemdQuote(arg)
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	for _, kv := range env {
+		// Environment= expands specifiers, but not variables.
+		fmt.Fprintf(&b, "Environment=\"%s\"\n", strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(kv))
+	}
+	b.WriteString("Restart=on-failure\nRestartSec=30\n\n[Install]\nWantedBy=default.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes a word of an ExecStart command line, escaping the
+// specifiers and variables systemd would otherwise expand.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(s)
+	if s == "" || strings.ContainsAny(s, " \t';") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// launchdPlist is a launch agent running cmdline at login, restarted if it
+// exits, with output appended to logPath.
+func launchdPlist(cmdline, env []string, logPath string) string {
+	esc := func(s string) string {
+		var b strings.Builder
+		_ = xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", daemonLabel)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range cmdline {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", esc(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if len(env) > 0 {
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, kv := range env {
+			name, value, _ := strings.Cut(kv, "=")
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", esc(name), esc(value))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n\t<key>KeepAlive</key>\n\t<true/>\n")
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", esc(logPath))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", esc(logPath))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// windowsCommandLine joins cmdline the way Windows programs split it:
+// arguments with spaces or quotes are quoted, with inner quotes and the
+// backslashes before them escaped.
+func windowsCommandLine(cmdline []string) string {
+	quoted := make([]string, len(cmdline))
+	for i, arg := range cmdline {
+		if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+			quoted[i] = arg
+			continue
+		}
+		var b strings.Builder
+		b.WriteByte('"')
+		slashes := 0
+		for _, c := range arg {
+			switch c {
+			case '\\':
+				slashes++
+			case '"':
+				b.WriteString(strings.Repeat(`\`, slashes+1))
+				slashes = 0
+			default:
+				slashes = 0
+			}
+			b.WriteRune(c)
+		}
+		b.WriteString(strings.Repeat(`\`, slashes))
+		b.WriteByte('"')
+		quoted[i] = b.String()
+	}
+	return strings.Join(quoted, " ")
+}
+
+// runService runs a service manager command, passing its output through.
+// Tests replace it.
+var runService = func(argv []string) error {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(argv, " "), err)
+	}
+	return nil
+}
+
+// installDaemon writes and starts the service, replacing any earlier one.
+func installDaemon(d daemonSetup) error {
+	for _, argv := range d.Stop {
+		_ = runService(argv)
+	}
+	if d.File != "" {
+		if err := os.MkdirAll(filepath.Dir(d.File), 0o700); err != nil {
+			return err
+		}
+		// The command line can hold secrets such as a Telegram bot token.
+		if err := os.WriteFile(d.File, []byte(d.Content), 0o600); err != nil {
+			return err
+		}
+	}
+	if d.Reload != nil {
+		if err := runService(d.Reload); err != nil {
+			return err
+		}
+	}
+	for _, argv := range d.Start {
+		if err := runService(argv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uninstallDaemon stops and unregisters the service and removes its file.
+// Failures to unregister are warnings, so a half-installed service can
+// still be cleaned up.
+func uninstallDaemon(d daemonSetup) error {
+	for _, argv := range d.Stop {
+		_ = runService(argv)
+	}
+	for _, argv := range d.Remove {
+		if err := runService(argv); err != nil {
+			fmt.Fprintln(warnings, "Warning:", err)
+		}
+	}
+	if d.File != "" {
+		if err := os.Remove(d.File); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if d.Reload != nil {
+		return runService(d.Reload)
+	}
+	return nil
+}
+
+func runDaemon(args []string) int {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the service definition and commands instead of installing.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s daemon [--dry-run] install <watch options> <github-username>...\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s daemon uninstall | status\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Runs watch in the background, started at login and restarted if it fails: as a")
+		fmt.Fprintln(fs.Output(), "systemd user unit on Linux, a launchd agent on macOS, or a scheduled task on")
+		fmt.Fprintln(fs.Output(), "Windows. install takes the same options and usernames as watch, and the service")
+		fmt.Fprintln(fs.Output(), "keeps $GITHUB_ACTIVITY_CONFIG and $GITHUB_API_URL; authenticate it with auth login.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() < 1 || (fs.Arg(0) == "install") != (fs.NArg() > 1) {
+		fs.Usage()
+		return 2
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	var env []string
+	for _, name := range daemonEnv {
+		if v := os.Getenv(name); v != "" {
+			env = append(env, name+"="+v)
+		}
+	}
+	d := daemonSetupFor(runtime.GOOS, home, configDir, exe, fs.Args()[1:], env)
+
+	switch fs.Arg(0) {
+	case "install":
+		if *dryRun {
+			printDaemonSetup(d)
+			return 0
+		}
+		if err := installDaemon(d); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, "Installed and started the background watch; see daemon status.")
+	case "uninstall":
+		if err := uninstallDaemon(d); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, "Removed the background watch.")
+	case "status":
+		if err := runService(d.Status); err != nil {
+			return 1
+		}
+	default:
+		fs.Usage()
+		return 2
+	}
+	return 0
+}
+
+// printDaemonSetup shows what install would do.
+func printDaemonSetup(d daemonSetup) {
+	if d.File != "" {
+		fmt.Printf("# %s\n%s\n", d.File, d.Content)
+	}
+	if d.Reload != nil {
+		fmt.Println(strings.Join(d.Reload, " "))
+	}
+	for _, argv := range d.Start {
+		fmt.Println(strings.Join(argv, " "))
+	}
+}
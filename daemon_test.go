@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDaemonSetupFor(t *testing.T) {
+	args := []string{"--webhook-template", `{"text": "100% $x"}`, "alice"}
+	env := []string{"GITHUB_ACTIVITY_CONFIG=/home/a/my config.json"}
+
+	linux := daemonSetupFor("linux", "/home/a", "/home/a/.config", "/usr/bin/github-activity", args, env)
+	if linux.File != "/home/a/.config/systemd/user/github-activity-watch.service" {
+		t.Errorf("unit file %s", linux.File)
+	}
+	for _, want := range []string{
+		`ExecStart=/usr/bin/github-activity watch --webhook-template "{\"text\": \"100%% $$x\"}" alice` + "\n",
+		`Environment="GITHUB_ACTIVITY_CONFIG=/home/a/my config.json"` + "\n",
+		"Restart=on-failure\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(linux.Content, want) {
+			t.Errorf("unit missing %q:\n%s", want, linux.Content)
+		}
+	}
+
+	mac := daemonSetupFor("darwin", "/Users/a", "", "/usr/local/bin/github-activity", args, env)
+	if mac.File != "/Users/a/Library/LaunchAgents/com.github-activity.watch.plist" {
+		t.Errorf("plist file %s", mac.File)
+	}
+	for _, want := range []string{
+		"<string>--webhook-template</string>\n\t\t<string>{&#34;text&#34;: &#34;100% $x&#34;}</string>",
+		"<key>GITHUB_ACTIVITY_CONFIG</key>\n\t\t<string>/home/a/my config.json</string>",
+		"<string>/Users/a/Library/Logs/github-activity-watch.log</string>",
+	} {
+		if !strings.Contains(mac.Content, want) {
+			t.Errorf("plist missing %q:\n%s", want, mac.Content)
+		}
+	}
+
+	win := daemonSetupFor("windows", `C:\Users\a`, "", `C:\Program Files\github-activity.exe`, args, env)
+	if win.File != "" || len(win.Start) != 2 {
+		t.Fatalf("windows: %+v", win)
+	}
+	if tr := win.Start[0][len(win.Start[0])-1]; tr != `"C:\Program Files\github-activity.exe" watch --webhook-template "{\"text\": \"100% $x\"}" alice` {
+		t.Errorf("task command %s", tr)
+	}
+}
+
+func TestWindowsCommandLine(t *testing.T) {
+	for in, want := range map[string]string{
+		`plain`:       `plain`,
+		``:            `""`,
+		`a b`:         `"a b"`,
+		`say "hi"`:    `"say \"hi\""`,
+		`C:\dir\ x\`:  `"C:\dir\ x\\"`,
+		`q\"uote`:     `"q\\\"uote"`,
+		`no\slash\es`: `no\slash\es`,
+	} {
+		if got := windowsCommandLine([]string{in}); got != want {
+			t.Errorf("%s: got %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestInstallDaemon(t *testing.T) {
+	var ran []string
+	restoreRun, restoreWarnings := runService, warnings
+	var warned strings.Builder
+	runService = func(argv []string) error {
+		ran = append(ran, strings.Join(argv, " "))
+		if argv[1] == "--user" && argv[2] == "disable" {
+			return errors.New("unit not loaded")
+		}
+		return nil
+	}
+	warnings = &warned
+	defer func() { runService, warnings = restoreRun, restoreWarnings }()
+
+	d := daemonSetupFor("linux", "", t.TempDir(), "/bin/ga", []string{"alice"}, nil)
+	if err := installDaemon(d); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(d.File)
+	if err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("unit file: %v, %v", fi, err)
+	}
+	want := "systemctl --user daemon-reload, systemctl --user enable github-activity-watch.service, systemctl --user restart github-activity-watch.service"
+	if strings.Join(ran, ", ") != want {
+		t.Errorf("install ran %q", ran)
+	}
+
+	ran = nil
+	if err := uninstallDaemon(d); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(d.File); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unit file left behind: %v", err)
+	}
+	if strings.Join(ran, ", ") != "systemctl --user disable --now github-activity-watch.service, systemctl --user daemon-reload" {
+		t.Errorf("uninstall ran %q", ran)
+	}
+	if !strings.Contains(warned.String(), "unit not loaded") {
+		t.Errorf("warnings %q", warned.String())
+	}
+	if filepath.Base(d.File) != "github-activity-watch.service" {
+		t.Errorf("unit name %s", d.File)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Limits applied to API responses before any event is trusted. Feeds can
+// carry payloads from untrusted sources, so keep a hostile response from
+// exhausting memory or the stack.
+const (
+	maxResponseBytes = 16 << 20
+	maxEventBytes    = 1 << 20
+	maxPayloadDepth  = 32
+)
+
+// lenient makes decodeEvents skip malformed events, with a warning on
+// stderr, instead of failing. Set by --lenient.
+var lenient bool
+
+// warnings is where lenient decoding reports skipped events.
+var warnings io.Writer = os.Stderr
+
+// decodeEvents decodes each element of an events array independently.
+func decodeEvents(raw []json.RawMessage) ([]Event, error) {
+	events := make([]Event, 0, len(raw))
+	for i, r := range raw {
+		ev, err := decodeEvent(r)
+		if err != nil {
+			if lenient {
+				fmt.Fprintf(warnings, "Warning: skipping malformed event %d: %v\n", i, err)
+				continue
+			}
+			return nil, fmt.Errorf("decode failed: event %d: %w", i, err)
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func decodeEvent(raw json.RawMessage) (Event, error) {
+	var ev Event
+	if len(raw) > maxEventBytes {
+		return ev, fmt.Errorf("event exceeds %d bytes", maxEventBytes)
+	}
+	if err := json.Unmarshal(raw, &ev); err != nil {
+		return ev, err
+	}
+	if depth, err := jsonDepth(ev.Payload); err != nil {
+		return ev, err
+	} else if depth > maxPayloadDepth {
+		return ev, fmt.Errorf("payload nested deeper than %d levels", maxPayloadDepth)
+	}
+	if len(ev.Payload) == 0 || bytes.Equal(ev.Payload, []byte("null")) {
+		ev.Payload = json.RawMessage("{}")
+	}
+	return ev, nil
+}
+
+// jsonDepth returns how deeply objects and arrays nest in data. It walks
+// tokens rather than decoding so hostile input can't blow the stack.
+func jsonDepth(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	depth, max := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return max, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > max {
+				max = depth
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func rawEvents(t *testing.T, s string) []json.RawMessage {
+	t.Helper()
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+const mixedEvents = `[
+	{"type":"WatchEvent","created_at":"2024-05-01T12:00:00Z","payload":{"action":"started"}},
+	{"type":"PushEvent","created_at":"not a time","payload":{}},
+	{"type":"CreateEvent","created_at":"2024-05-01T12:00:00Z","payload":null}
+]`
+
+func TestDecodeEvents_StrictFailsOnMalformedEvent(t *testing.T) {
+	_, err := decodeEvents(rawEvents(t, mixedEvents))
+	if err == nil || !strings.Contains(err.Error(), "event 1") {
+		t.Fatalf("expected error naming event 1, got %v", err)
+	}
+}
+
+func TestDecodeEvents_LenientSkipsMalformedEvent(t *testing.T) {
+	var warned strings.Builder
+	restoreLenient, restoreWarnings := lenient, warnings
+	lenient, warnings = true, &warned
+	defer func() { lenient, warnings = restoreLenient, restoreWarnings }()
+
+	events, err := decodeEvents(rawEvents(t, mixedEvents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Type != "WatchEvent" || events[1].Type != "CreateEvent" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if string(events[1].Payload) != "{}" {
+		t.Fatalf("null payload should default to {}, got %s", events[1].Payload)
+	}
+	if !strings.Contains(warned.String(), "skipping malformed event 1") {
+		t.Fatalf("missing warning: %q", warned.String())
+	}
+}
+
+func TestDecodeEvent_RejectsDeepPayload(t *testing.T) {
+	deep := strings.Repeat("[", maxPayloadDepth+1) + strings.Repeat("]", maxPayloadDepth+1)
+	_, err := decodeEvent(json.RawMessage(`{"type":"PushEvent","payload":` + deep + `}`))
+	if err == nil || !strings.Contains(err.Error(), "nested deeper") {
+		t.Fatalf("expected depth error, got %v", err)
+	}
+}
+
+func TestJSONDepth(t *testing.T) {
+	tests := map[string]int{
+		``:                  0,
+		`1`:                 0,
+		`{}`:                1,
+		`{"a":[1,{"b":2}]}`: 3,
+		`[[],[[]]]`:         3,
+	}
+	for in, want := range tests {
+		if got, err := jsonDepth([]byte(in)); err != nil || got != want {
+			t.Fatalf("jsonDepth(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+}
+
+// FuzzDecodeEvent checks that arbitrary event JSON never panics the decoder
+// or the formatters, whatever it decodes to.
+func FuzzDecodeEvent(f *testing.F) {
+	f.Add([]byte(`{"type":"PushEvent","payload":{"size":2}}`))
+	f.Add([]byte(`{"type":"IssuesEvent","payload":{"action":"","issue":null}}`))
+	f.Add([]byte(`{"type":"PullRequestEvent","payload":{"pull_request":{"base":{"ref":7}}}}`))
+	f.Add([]byte(`{"type":"ForkEvent","payload":[[[[]]]]}`))
+	f.Add([]byte(`{"payload":null}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ev, err := decodeEvent(data)
+		if err != nil {
+			return
+		}
+		line, ok := formatEvent(ev)
+		if ok && line == "" {
+			t.Fatalf("formatEvent returned ok with empty line for %s", data)
+		}
+		_ = detailsOf(ev)
+		_, _ = branchOf(ev)
+		_ = entityURL(ev)
+	})
+}
+
+// FuzzFormatEvent feeds arbitrary payloads to every known event type.
+func FuzzFormatEvent(f *testing.F) {
+	for _, typ := range []string{"PushEvent", "IssuesEvent", "PullRequestEvent", "WatchEvent", "ForkEvent", "CreateEvent", "ReleaseEvent"} {
+		f.Add(typ, []byte(`{}`))
+	}
+	f.Add("IssuesEvent", []byte(`{"action":"x","issue":{"number":-1,"title":"\u0000"}}`))
+	f.Fuzz(func(t *testing.T, typ string, payload []byte) {
+		ev := Event{Type: typ, Payload: payload}
+		_, _ = formatEvent(ev)
+		_ = detailsOf(ev)
+		_, _ = branchOf(ev)
+	})
+}
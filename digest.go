@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// digestWidth is the conventional line length for plain-text email.
+const digestWidth = 72
+
+// digestRenderer writes a plain-text digest for mailing lists: lines wrapped
+// at 72 columns, with links collected as numbered footnotes instead of being
+// inlined.
+type digestRenderer struct {
+	w        io.Writer
+	sections []digestSection
+	ci       []ciStatus // set by setCI for the next section
+}
+
+type digestSection struct {
+	user    string
+	entries []entry
+	ci      []ciStatus
+}
+
+func (r *digestRenderer) setCI(_ string, statuses []ciStatus) { r.ci = statuses }
+
+func (r *digestRenderer) feed(user string, _ []Event, entries []entry) error {
+	r.sections = append(r.sections, digestSection{user: user, entries: entries, ci: r.ci})
+	r.ci = nil
+	return nil
+}
+
+func (r *digestRenderer) flush() error {
+	var users []string
+	total := 0
+	for _, s := range r.sections {
+		users = append(users, s.user)
+		total += len(s.entries)
+	}
+
+	var b strings.Builder
+	for _, line := range wrapText("GitHub activity digest: "+strings.Join(users, ", "), digestWidth, "") {
+		b.WriteString(line + "\n")
+	}
+	fmt.Fprintf(&b, "Generated %s, %d events\n", now().Format("Mon, 02 Jan 2006"), total)
+	b.WriteString(strings.Repeat("=", digestWidth) + "\n")
+
+	var links []string
+	footnote := map[string]int{}
+	ref := func(u string) int {
+		n, ok := footnote[u]
+		if !ok {
+			links = append(links, u)
+			n = len(links)
+			footnote[u] = n
+		}
+		return n
+	}
+	for _, s := range r.sections {
+		b.WriteString("\n")
+		if len(r.sections) > 1 {
+			b.WriteString(s.user + "\n" + strings.Repeat("-", utf8.RuneCountInString(s.user)) + "\n")
+		}
+		if len(s.entries) == 0 {
+			b.WriteString("No activity.\n")
+		}
+		for _, e := range s.entries {
+			text := fmt.Sprintf("* %s: %s%s [%d]", e.Event.CreatedAt.Local().Format("Jan 02"), actorPrefix(e), e.Summary, ref(entityURL(e.Event)))
+			for _, line := range wrapText(text, digestWidth, "  ") {
+				b.WriteString(line + "\n")
+			}
+		}
+		for _, st := range s.ci {
+			for _, line := range wrapText(fmt.Sprintf("! %s [%d]", st, ref(st.URL)), digestWidth, "  ") {
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+
+	if len(links) > 0 {
+		b.WriteString("\nLinks:\n")
+		for i, u := range links {
+			fmt.Fprintf(&b, "[%d] %s\n", i+1, u)
+		}
+	}
+	_, err := io.WriteString(r.w, b.String())
+	return err
+}
+
+// wrapText breaks s into lines of at most width characters at spaces.
+// Continuation lines are prefixed with indent (which counts toward width).
+// Words longer than a line are left intact rather than split.
+func wrapText(s string, width int, indent string) []string {
+	var lines []string
+	line, n, empty := "", 0, true
+	for _, word := range strings.Fields(s) {
+		wl := utf8.RuneCountInString(word)
+		if !empty && n+1+wl > width {
+			lines = append(lines, line)
+			line, n, empty = indent, utf8.RuneCountInString(indent), true
+		}
+		if !empty {
+			line += " "
+			n++
+		}
+		line += word
+		n += wl
+		empty = false
+	}
+	if !empty {
+		lines = append(lines, line)
+	}
+	return lines
+}
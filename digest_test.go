@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWrapText(t *testing.T) {
+	got := wrapText("* one two three four", 10, "  ")
+	want := []string{"* one two", "  three", "  four"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("got %q want %q", got, want)
+	}
+	// Over-long words stay whole; multi-byte text is measured in runes.
+	got = wrapText("ééééé ééééééééééééé", 6, "")
+	if len(got) != 2 || got[1] != "ééééééééééééé" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestDigestRenderer(t *testing.T) {
+	var buf strings.Builder
+	r := &digestRenderer{w: &buf}
+	long := testEntry("IssuesEvent", "alice/repo", "Opened an issue #42 “A very long issue title that certainly does not fit on a single seventy-two column line” in alice/repo")
+	long.Event.Payload = mustRaw(map[string]any{"issue": map[string]any{"number": 42}})
+	same := long // same URL reuses the footnote
+	star := testEntry("WatchEvent", "bob/tool", "Starred bob/tool")
+	_ = r.feed("alice", nil, []entry{long, same, star})
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range strings.Split(out, "\n") {
+		if utf8.RuneCountInString(line) > digestWidth {
+			t.Fatalf("line exceeds %d columns: %q", digestWidth, line)
+		}
+	}
+	for _, want := range []string{
+		"[1] https://github.com/alice/repo/issues/42\n",
+		"[2] https://github.com/bob/tool\n",
+		"Starred bob/tool [2]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "[1]") != 3 {
+		t.Fatalf("expected footnote 1 to be reused:\n%s", out)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// programName is the command name used in generated documentation.
+const programName = "github-activity"
+
+// rootCommand describes the default activity listing for docs.
+var rootCommand = command{
+	args:    "<github-username>...",
+	summary: "Show recent public GitHub activity for one or more users.",
+	run:     runActivity,
+}
+
+// docs refers to commands, so it is registered here rather than in the
+// commands literal to avoid an initialization cycle.
+func init() {
+	commands = append(commands, command{
+		name:    "docs",
+		args:    "man|markdown",
+		summary: "Generate man pages or Markdown reference docs for every command.",
+		run:     runDocs,
+	})
+}
+
+func runDocs(args []string) int {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	dir := fs.String("dir", ".", "Directory to write the generated files to.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s docs [options] man|markdown\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Writes one man page (section 1) or Markdown file per command, generated from the commands' own flag definitions.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 || (fs.Arg(0) != "man" && fs.Arg(0) != "markdown") {
+		fs.Usage()
+		return 2
+	}
+
+	write, ext := writeManPage, ".1"
+	if fs.Arg(0) == "markdown" {
+		write, ext = writeMarkdownDoc, ".md"
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	for _, d := range commandDocs() {
+		path := filepath.Join(*dir, d.pageName()+ext)
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		write(f, d)
+		if err := f.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Println(path)
+	}
+	return 0
+}
+
+// commandDoc is a command together with its flag definitions.
+type commandDoc struct {
+	command
+	flags []*flag.Flag
+}
+
+// pageName is the file name (without extension) and man page title.
+func (d commandDoc) pageName() string {
+	if d.name == "" {
+		return programName
+	}
+	return programName + "-" + d.name
+}
+
+func (d commandDoc) synopsis() string {
+	s := programName
+	if d.name != "" {
+		s += " " + d.name
+	}
+	if len(d.flags) > 0 {
+		s += " [options]"
+	}
+	if d.args != "" {
+		s += " " + d.args
+	}
+	return s
+}
+
+// commandDocs collects the root command and every subcommand with their
+// flags, by running each one with describeFlags set.
+func commandDocs() []commandDoc {
+	defer func() { describeFlags = nil }()
+	var docs []commandDoc
+	for _, c := range append([]command{rootCommand}, commands...) {
+		d := commandDoc{command: c}
+		describeFlags = func(fs *flag.FlagSet) {
+			fs.VisitAll(func(f *flag.Flag) { d.flags = append(d.flags, f) })
+		}
+		c.run(nil)
+		docs = append(docs, d)
+	}
+	return docs
+}
+
+// flagSynopsis renders a flag as "--name value", naming the value after
+// the backquoted word in its usage, as flag.PrintDefaults does.
+func flagSynopsis(f *flag.Flag) (synopsis, usage string) {
+	name, usage := flag.UnquoteUsage(f)
+	synopsis = "--" + f.Name
+	if name != "" {
+		synopsis += " " + name
+	}
+	return synopsis, usage
+}
+
+// flagDefault is the default worth documenting, or "" for zero values.
+func flagDefault(f *flag.Flag) string {
+	switch f.DefValue {
+	case "", "false", "0", "0s", "[]":
+		return ""
+	}
+	return f.DefValue
+}
+
+func writeManPage(w io.Writer, d commandDoc) {
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n", strings.ToUpper(d.pageName()), programName, version)
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "%s \\- %s\n", roffEscape(d.pageName()), roffEscape(d.summary))
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintln(w, roffEscape(d.synopsis()))
+	if len(d.flags) > 0 {
+		fmt.Fprintln(w, ".SH OPTIONS")
+		for _, f := range d.flags {
+			synopsis, usage := flagSynopsis(f)
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintf(w, ".B %s\n", roffEscape(synopsis))
+			fmt.Fprintln(w, roffEscape(usage))
+			if def := flagDefault(f); def != "" {
+				fmt.Fprintf(w, "(default: %s)\n", roffEscape(def))
+			}
+		}
+	}
+	fmt.Fprintln(w, ".SH SEE ALSO")
+	var refs []string
+	for _, c := range append([]command{rootCommand}, commands...) {
+		if other := (commandDoc{command: c}).pageName(); other != d.pageName() {
+			refs = append(refs, fmt.Sprintf(".BR %s (1)", roffEscape(other)))
+		}
+	}
+	fmt.Fprintln(w, strings.Join(refs, ",\n"))
+}
+
+// roffEscape makes s safe as roff text: backslashes and hyphens are escaped
+// and a leading control character is neutralized.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+func writeMarkdownDoc(w io.Writer, d commandDoc) {
+	title := programName
+	if d.name != "" {
+		title += " " + d.name
+	}
+	fmt.Fprintf(w, "# %s\n\n%s\n\n", title, d.summary)
+	fmt.Fprintf(w, "## Usage\n\n```\n%s\n```\n", d.synopsis())
+	if len(d.flags) > 0 {
+		fmt.Fprint(w, "\n## Options\n\n")
+		for _, f := range d.flags {
+			synopsis, usage := flagSynopsis(f)
+			fmt.Fprintf(w, "- `%s`: %s", synopsis, usage)
+			if def := flagDefault(f); def != "" {
+				fmt.Fprintf(w, " (default: `%s`)", def)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	if d.name == "" {
+		fmt.Fprint(w, "\n## Commands\n\n")
+		for _, c := range commands {
+			fmt.Fprintf(w, "- [%s](%s.md): %s\n", c.name, programName+"-"+c.name, c.summary)
+		}
+	}
+}
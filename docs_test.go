@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandDocs(t *testing.T) {
+	docs := commandDocs()
+	if describeFlags != nil {
+		t.Fatal("describeFlags left set")
+	}
+	if len(docs) != len(commands)+1 {
+		t.Fatalf("got %d docs, want root + %d commands", len(docs), len(commands))
+	}
+	root, tags := docs[0], docFor(t, docs, "tags")
+	if root.pageName() != "github-activity" || root.synopsis() != "github-activity [options] <github-username>..." {
+		t.Errorf("root: %q %q", root.pageName(), root.synopsis())
+	}
+	if tags.pageName() != "github-activity-tags" || tags.synopsis() != "github-activity tags [options] <owner>/<repo>" {
+		t.Errorf("tags: %q %q", tags.pageName(), tags.synopsis())
+	}
+	var names []string
+	for _, f := range root.flags {
+		names = append(names, f.Name)
+	}
+	for _, want := range []string{"type", "format", "n", "group-by"} {
+		if !strings.Contains(" "+strings.Join(names, " ")+" ", " "+want+" ") {
+			t.Errorf("root flags %v missing %q", names, want)
+		}
+	}
+}
+
+func docFor(t *testing.T, docs []commandDoc, name string) commandDoc {
+	t.Helper()
+	for _, d := range docs {
+		if d.name == name {
+			return d
+		}
+	}
+	t.Fatalf("no docs for %q", name)
+	return commandDoc{}
+}
+
+func TestWriteManPage(t *testing.T) {
+	d := docFor(t, commandDocs(), "tags")
+	var b strings.Builder
+	writeManPage(&b, d)
+	for _, want := range []string{
+		".TH GITHUB-ACTIVITY-TAGS 1",
+		"github\\-activity\\-tags \\- Summarize recent tag",
+		".B \\-\\-n int\n",
+		"(default: 10)",
+		".BR github\\-activity (1)",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestWriteMarkdownDoc(t *testing.T) {
+	docs := commandDocs()
+	var b strings.Builder
+	writeMarkdownDoc(&b, docs[0])
+	for _, want := range []string{
+		"# github-activity\n",
+		"```\ngithub-activity [options] <github-username>...\n```",
+		"- `--n int`: Max number of events to show per user (1-100). Defaults to what fits the terminal height, or 30 when not on a terminal. (default: `30`)",
+		"- [tags](github-activity-tags.md): ",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	if got := roffEscape(`.a-b\c`); got != `\&.a\-b\ec` {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// typeEmoji decorates text lines under --emoji.
+var typeEmoji = map[string]string{
+	"PushEvent":                     "⬆️",
+	"IssuesEvent":                   "🐛",
+	"IssueCommentEvent":             "💬",
+	"PullRequestEvent":              "🔀",
+	"PullRequestReviewCommentEvent": "💬",
+	"WatchEvent":                    "⭐",
+	"ForkEvent":                     "🍴",
+	"CreateEvent":                   "✨",
+	"DeleteEvent":                   "🗑️",
+	"ReleaseEvent":                  "🚀",
+	"DeploymentEvent":               "🚢",
+	"DeploymentStatusEvent":         "🚢",
+}
+
+func eventEmoji(typ string) string {
+	if e, ok := typeEmoji[typ]; ok {
+		return e
+	}
+	return "•"
+}
+
+// utf8Terminal guesses whether the terminal can show emoji: on Unix from the
+// locale (the first of LC_ALL, LC_CTYPE, LANG that is set), on Windows only
+// inside Windows Terminal, as the classic console uses legacy code pages.
+func utf8Terminal() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != ""
+	}
+	for _, v := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(v); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
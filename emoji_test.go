@@ -0,0 +1,38 @@
+package main
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestUTF8Terminal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("locale variables are not used on Windows")
+	}
+	tests := []struct {
+		lcAll, lang string
+		want        bool
+	}{
+		{"", "en_US.UTF-8", true},
+		{"", "de_DE.utf8", true},
+		{"C", "en_US.UTF-8", false}, // LC_ALL takes precedence
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+		if got := utf8Terminal(); got != tt.want {
+			t.Errorf("LC_ALL=%q LANG=%q: got %v", tt.lcAll, tt.lang, got)
+		}
+	}
+}
+
+func TestEventEmoji(t *testing.T) {
+	if got := eventEmoji("ForkEvent"); got != "🍴" {
+		t.Errorf("fork: %q", got)
+	}
+	if got := eventEmoji("GollumEvent"); got != "•" {
+		t.Errorf("unknown: %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// setAPIURL points the tool at a GitHub instance: api.github.com, or a
+// GitHub Enterprise Server given by its host URL, e.g.
+// https://github.example.com, with or without the /api/v3 prefix. Links go
+// to the same instance's web UI.
+func setAPIURL(raw string) error {
+	u, err := url.Parse(strings.TrimSuffix(raw, "/"))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid API URL %q (want e.g. https://github.example.com/api/v3)", raw)
+	}
+	if isDotCom(u.Host) {
+		apiURL, webURL = "https://api.github.com", "https://github.com"
+		return nil
+	}
+	web := u.Scheme + "://" + u.Host
+	apiURL, webURL = web+"/api/v3", web
+	return nil
+}
+
+// isDotCom reports whether host is github.com rather than an Enterprise
+// Server.
+func isDotCom(host string) bool {
+	host = strings.ToLower(host)
+	return host == "github.com" || host == "api.github.com"
+}
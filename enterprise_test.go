@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetAPIURL(t *testing.T) {
+	restoreAPI, restoreWeb := apiURL, webURL
+	defer func() { apiURL, webURL = restoreAPI, restoreWeb }()
+
+	tests := []struct {
+		in       string
+		api, web string
+	}{
+		{"https://github.example.com", "https://github.example.com/api/v3", "https://github.example.com"},
+		{"https://github.example.com/api/v3/", "https://github.example.com/api/v3", "https://github.example.com"},
+		{"http://ghe.internal:8080/api/v3", "http://ghe.internal:8080/api/v3", "http://ghe.internal:8080"},
+		{"https://api.github.com", "https://api.github.com", "https://github.com"},
+		{"https://GitHub.com", "https://api.github.com", "https://github.com"},
+	}
+	for _, tt := range tests {
+		if err := setAPIURL(tt.in); err != nil || apiURL != tt.api || webURL != tt.web {
+			t.Errorf("%s: got %s, %s, %v", tt.in, apiURL, webURL, err)
+		}
+	}
+	for _, bad := range []string{"github.example.com", "ftp://x", "https://"} {
+		if err := setAPIURL(bad); err == nil {
+			t.Errorf("%s: want error", bad)
+		}
+	}
+}
+
+func TestAPIURLFlagEveryCommand(t *testing.T) {
+	restoreAPI, restoreWeb := apiURL, webURL
+	defer func() { apiURL, webURL = restoreAPI, restoreWeb }()
+	t.Setenv("GITHUB_ACTIVITY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	for _, name := range []string{"stats", "auth login", "watch"} {
+		apiURL, webURL = restoreAPI, restoreWeb
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		if !parseFlags(fs, []string{"--api-url=https://ghe.example.com"}) {
+			t.Fatalf("%s: parseFlags false", name)
+		}
+		if apiURL != "https://ghe.example.com/api/v3" || webURL != "https://ghe.example.com" {
+			t.Errorf("%s: got %s, %s", name, apiURL, webURL)
+		}
+	}
+}
+
+func TestEnterpriseTokenSentOnlyToAPIHost(t *testing.T) {
+	t.Setenv("GH_ENTERPRISE_TOKEN", "ghe-secret")
+	var auth []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	api := httptest.NewServer(handler)
+	defer api.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+	restore := apiURL
+	apiURL = api.URL
+	defer func() { apiURL = restore }()
+
+	var v any
+	for _, u := range []string{api.URL + "/users/alice", other.URL} {
+		if err := getJSON(context.Background(), u, &v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(auth) != 2 || auth[0] != "Bearer ghe-secret" || auth[1] != "" {
+		t.Errorf("Authorization headers: %q", auth)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// timesheetHeader uses column names that Toggl, Clockify, and Harvest all
+// recognize (or can map) on CSV import.
+var timesheetHeader = []string{"Date", "Project", "Description", "Hours", "Duration", "Events"}
+
+// writeTimesheet writes one row per day and repository of the estimated
+// time in entries, with the day's event summaries as the description.
+func writeTimesheet(w io.Writer, comma rune, entries []timeEntry) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	cw.Write(timesheetHeader)
+	for _, e := range entries {
+		cw.Write([]string{
+			e.Day,
+			e.Repo,
+			timesheetDescription(e.Events),
+			fmt.Sprintf("%.2f", e.Duration.Hours()),
+			clockDuration(e.Duration),
+			fmt.Sprint(len(e.Events)),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// timesheetDescription joins the distinct summaries of events, in order.
+func timesheetDescription(events []Event) string {
+	var parts []string
+	seen := map[string]bool{}
+	for _, ev := range events {
+		s, ok := formatEvent(ev)
+		if !ok {
+			s = ev.Type + " in " + ev.Repo.Name
+		}
+		if !seen[s] {
+			seen[s] = true
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// clockDuration renders d as h:mm, rounded to the minute.
+func clockDuration(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%d:%02d", m/60, m%60)
+}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	timesheet := fs.Bool("timesheet", false, "Export estimated time per day and repository (see stats --time-estimate) as CSV.")
+	delimiter := fs.String("delimiter", ",", `Field delimiter (a single character, or "tab").`)
+	tz := fs.String("tz", "", "Time zone that days are counted in (default: local).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s export --timesheet [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Exports recent activity for use elsewhere. --timesheet writes a CSV with one row")
+		fmt.Fprintln(fs.Output(), "per day and repository of estimated working time, for timesheet and invoicing tools.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 || !*timesheet {
+		fs.Usage()
+		return 2
+	}
+	comma, err := parseDelimiter(*delimiter)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	events, err := fetchEventPages(context.Background(), fs.Arg(0), pageOptions{PerPage: 100})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if err := writeTimesheet(os.Stdout, comma, timeEntries(sessions(events))); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
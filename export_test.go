@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteTimesheet(t *testing.T) {
+	pinClock(t)
+	events := timedEvents(
+		0*time.Minute, "a/x",
+		30*time.Minute, "a/x",
+		75*time.Minute, "a/y",
+	)
+	events[0].Type, events[0].Payload = "WatchEvent", mustRaw(WatchPayload{Action: "started"})
+	var b strings.Builder
+	if err := writeTimesheet(&b, ';', timeEntries(sessions(events))); err != nil {
+		t.Fatal(err)
+	}
+	want := "Date;Project;Description;Hours;Duration;Events\n" +
+		"2024-05-03;a/x;PushEvent in a/x;1.00;1:00;2\n" +
+		"2024-05-03;a/y;Starred a/y;0.50;0:30;1\n"
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestClockDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                               "0:00",
+		15 * time.Minute:                "0:15",
+		90*time.Minute + 40*time.Second: "1:31",
+		10 * time.Hour:                  "10:00",
+	} {
+		if got := clockDuration(d); got != want {
+			t.Errorf("clockDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// filters holds the event filters selected on the command line. The zero
+// value matches every event.
+type filters struct {
+	Types         []string // keep only these event types; empty means all
+	ExcludeTypes  []string
+	Actors        []string // keep only these actors; empty means everyone
+	ExcludeActors []string
+	Repos         []string       // globs matched against owner/name; empty means any
+	Owners        []string       // keep only repositories of these users or orgs
+	Branch        string         // glob matched against push and PR base branches
+	Since, Until  time.Time      // keep events created in [Since, Until); zero means unbounded
+	SinceID       string         // keep events with a greater ID; "" for all
+	NoBots        bool           // drop events by bots and on bot-authored issues/PRs
+	Private       bool           // keep private events, which are dropped otherwise
+	Grep          *regexp.Regexp // matched against searchText; nil for none
+	Expr          exprNode       // --filter expression, nil for none
+}
+
+func (f filters) match(ev Event) bool {
+	if ev.private() && !f.Private {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, ev.Type) {
+		return false
+	}
+	if slices.Contains(f.ExcludeTypes, ev.Type) {
+		return false
+	}
+	if len(f.Actors) > 0 && !containsFold(f.Actors, ev.Actor.Login) {
+		return false
+	}
+	if containsFold(f.ExcludeActors, ev.Actor.Login) {
+		return false
+	}
+	if len(f.Repos) > 0 && !matchRepo(f.Repos, ev.Repo.Name) {
+		return false
+	}
+	if len(f.Owners) > 0 {
+		owner, _, _ := strings.Cut(ev.Repo.Name, "/")
+		if !containsFold(f.Owners, owner) {
+			return false
+		}
+	}
+	if f.Branch != "" {
+		branch, ok := branchOf(ev)
+		if !ok {
+			return false
+		}
+		if matched, _ := path.Match(f.Branch, branch); !matched {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && ev.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !ev.CreatedAt.Before(f.Until) {
+		return false
+	}
+	if f.SinceID != "" && !newerID(ev.ID, f.SinceID) {
+		return false
+	}
+	if f.NoBots && isBotEvent(ev) {
+		return false
+	}
+	if f.Grep != nil && !slices.ContainsFunc(searchText(ev), f.Grep.MatchString) {
+		return false
+	}
+	if f.Expr != nil && !f.Expr.eval(ev) {
+		return false
+	}
+	return true
+}
+
+// matchRepo reports whether repo matches any of the globs, ignoring case as
+// GitHub does. "*" doesn't cross the slash, so "myorg/*" is every repository
+// of myorg.
+func matchRepo(globs []string, repo string) bool {
+	for _, g := range globs {
+		if ok, _ := path.Match(strings.ToLower(g), strings.ToLower(repo)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// eventCheck is a filter that needs API lookups. Checks run after the other
+// filters, and only until enough events are kept, to spend as few requests as
+// possible.
+type eventCheck func(ctx context.Context, ev Event) (bool, error)
+
+// selectChecked keeps the events that pass f and every check, stopping at
+// limit. Events whose checks fail are reported as warnings and dropped.
+func selectChecked(ctx context.Context, events []Event, f filters, checks []eventCheck, limit int) []Event {
+	var kept []Event
+	for _, ev := range events {
+		if len(kept) >= limit {
+			break
+		}
+		if !f.match(ev) {
+			continue
+		}
+		ok := true
+		for _, check := range checks {
+			var err error
+			if ok, err = check(ctx, ev); err != nil {
+				fmt.Fprintf(warnings, "Warning: %s event in %s: %v\n", ev.Type, ev.Repo.Name, err)
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+// newerID reports whether event ID a was issued after b. IDs are decimal and
+// increase over time but can outgrow int64, so they are compared as digit
+// strings.
+func newerID(a, b string) bool {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// isBotEvent reports whether a bot performed the event, or the issue or pull
+// request it is about was opened by one (e.g. a comment on a Dependabot PR).
+func isBotEvent(ev Event) bool { return botOf(ev) != "" }
+
+// botOf is the bot behind an event: its actor if that is a bot, else the bot
+// that opened the issue or pull request it is about. It is "" for neither.
+func botOf(ev Event) string {
+	if isBotLogin(ev.Actor.Login) {
+		return ev.Actor.Login
+	}
+	type author struct {
+		User struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+	}
+	var p struct {
+		Issue       *author `json:"issue"`
+		PullRequest *author `json:"pull_request"`
+	}
+	if json.Unmarshal(ev.Payload, &p) != nil {
+		return ""
+	}
+	for _, a := range []*author{p.Issue, p.PullRequest} {
+		if a != nil && (a.User.Type == "Bot" || isBotLogin(a.User.Login)) {
+			return a.User.Login
+		}
+	}
+	return ""
+}
+
+// isBotLogin reports whether a login is a GitHub App bot account.
+func isBotLogin(login string) bool { return strings.HasSuffix(login, "[bot]") }
+
+// searchText is what --grep searches: the repository name, the issue, pull
+// request, or release title, and the messages of commits inlined in a push.
+func searchText(ev Event) []string {
+	text := []string{ev.Repo.Name}
+	if title := detailsOf(ev).Title; title != "" {
+		text = append(text, title)
+	}
+	if ev.Type == "PushEvent" {
+		var p pushCommitsPayload
+		if json.Unmarshal(ev.Payload, &p) == nil {
+			for _, c := range p.Commits {
+				text = append(text, c.Message)
+			}
+		}
+	}
+	return text
+}
+
+// parseTimeArg parses a --since or --until value: a local date
+// (2024-05-01, meaning its midnight), an RFC 3339 timestamp, or a duration
+// before now (72h). "" is the zero time.
+func parseTimeArg(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want a date like 2024-05-01, an RFC 3339 timestamp, or a duration like 72h)", s)
+}
+
+// shortcutRange is the since/until range of --today, --yesterday, or
+// --this-week at time t, in the local time zone.
+func shortcutRange(name string, t time.Time) (since, until time.Time) {
+	switch name {
+	case "yesterday":
+		today, _, _ := periodBounds("day", t)
+		return today.AddDate(0, 0, -1), today
+	case "this-week":
+		since, until, _ = periodBounds("week", t)
+	default:
+		since, until, _ = periodBounds("day", t)
+	}
+	return since, until
+}
+
+// containsFold reports whether list contains s, ignoring case as GitHub
+// logins do.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList is a flag.Value that collects repeated and comma-separated
+// values, so --actor=a --actor=b and --actor=a,b are equivalent.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// repeatedFlag collects each value of a repeatable flag as given, for values
+// such as URLs, headers, and commands that may contain commas themselves.
+type repeatedFlag []string
+
+func (l *repeatedFlag) String() string { return strings.Join(*l, " ") }
+
+func (l *repeatedFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// eventTypes lists the event types the GitHub events API emits.
+var eventTypes = []string{
+	"CommitCommentEvent", "CreateEvent", "DeleteEvent", "DeploymentEvent", "DeploymentStatusEvent", "DiscussionEvent", "ForkEvent",
+	"GollumEvent", "IssueCommentEvent", "IssuesEvent", "MemberEvent", "PublicEvent",
+	"PullRequestEvent", "PullRequestReviewEvent", "PullRequestReviewCommentEvent",
+	"PullRequestReviewThreadEvent", "PushEvent", "ReleaseEvent", "SponsorshipEvent", "WatchEvent",
+}
+
+// eventTypeAliases maps short, lower-case names to event types.
+var eventTypeAliases = map[string]string{
+	"push":           "PushEvent",
+	"pr":             "PullRequestEvent",
+	"pull":           "PullRequestEvent",
+	"pull-request":   "PullRequestEvent",
+	"review":         "PullRequestReviewEvent",
+	"review-comment": "PullRequestReviewCommentEvent",
+	"issue":          "IssuesEvent",
+	"issues":         "IssuesEvent",
+	"comment":        "IssueCommentEvent",
+	"issue-comment":  "IssueCommentEvent",
+	"commit-comment": "CommitCommentEvent",
+	"star":           "WatchEvent",
+	"watch":          "WatchEvent",
+	"fork":           "ForkEvent",
+	"create":         "CreateEvent",
+	"delete":         "DeleteEvent",
+	"release":        "ReleaseEvent",
+	"deploy":         "DeploymentEvent",
+	"deployment":     "DeploymentEvent",
+	"deploy-status":  "DeploymentStatusEvent",
+	"wiki":           "GollumEvent",
+	"member":         "MemberEvent",
+	"public":         "PublicEvent",
+	"discussion":     "DiscussionEvent",
+	"sponsor":        "SponsorshipEvent",
+}
+
+// resolveEventTypes maps --type values, which may be event type names or
+// aliases in any case, to event types. Unknown names are errors with a
+// suggestion when one is close.
+func resolveEventTypes(names []string) ([]string, error) {
+	var types []string
+	for _, name := range names {
+		typ, err := resolveEventType(name)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(types, typ) {
+			types = append(types, typ)
+		}
+	}
+	return types, nil
+}
+
+func resolveEventType(name string) (string, error) {
+	lower := strings.ToLower(name)
+	if typ, ok := eventTypeAliases[lower]; ok {
+		return typ, nil
+	}
+	for _, typ := range eventTypes {
+		if strings.EqualFold(typ, name) || strings.EqualFold(typ, name+"Event") {
+			return typ, nil
+		}
+	}
+	best, bestDist := "", 3 // suggest only near misses
+	for alias := range eventTypeAliases {
+		if d := editDistance(lower, alias); d < bestDist || (d == bestDist && alias < best) {
+			best, bestDist = alias, d
+		}
+	}
+	for _, typ := range eventTypes {
+		if d := editDistance(lower, strings.ToLower(typ)); d < bestDist || (d == bestDist && typ < best) {
+			best, bestDist = typ, d
+		}
+	}
+	if best != "" {
+		return "", fmt.Errorf("unknown event type %q (did you mean %q?)", name, best)
+	}
+	return "", fmt.Errorf("unknown event type %q", name)
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions, and swaps of adjacent characters
+// (the most common typo) each count as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
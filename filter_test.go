@@ -0,0 +1,312 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func actorEvent(typ, login string) Event {
+	ev := Event{Type: typ}
+	ev.Actor.Login = login
+	return ev
+}
+
+func TestFilters_Actors(t *testing.T) {
+	f := filters{Actors: []string{"alice", "Bob"}, ExcludeActors: []string{"bob"}}
+	tests := []struct {
+		login string
+		want  bool
+	}{
+		{"alice", true},
+		{"ALICE", true},
+		{"bob", false}, // excluded wins
+		{"carol", false},
+	}
+	for _, tc := range tests {
+		if got := f.match(actorEvent("PushEvent", tc.login)); got != tc.want {
+			t.Fatalf("match(%q)=%v want %v", tc.login, got, tc.want)
+		}
+	}
+}
+
+func TestFilters_ZeroMatchesAll(t *testing.T) {
+	if !(filters{}).match(actorEvent("AnyEvent", "")) {
+		t.Fatal("zero filters should match every event")
+	}
+}
+
+func TestStringList_Set(t *testing.T) {
+	var l stringList
+	_ = l.Set("alice, bob")
+	_ = l.Set("carol")
+	_ = l.Set(",")
+	if got := l.String(); got != "alice,bob,carol" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFilters_Branch(t *testing.T) {
+	push := func(ref string) Event {
+		return Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"ref": ref})}
+	}
+	pr := func(base string) Event {
+		return Event{Type: "PullRequestEvent", Payload: mustRaw(map[string]any{
+			"pull_request": map[string]any{"base": map[string]any{"ref": base}},
+		})}
+	}
+	tests := []struct {
+		pattern string
+		ev      Event
+		want    bool
+	}{
+		{"main", push("refs/heads/main"), true},
+		{"main", push("refs/heads/dev"), false},
+		{"main", push("refs/tags/main"), false},
+		{"release/*", push("refs/heads/release/1.2"), true},
+		{"release/*", pr("release/2.0"), true},
+		{"main", pr("dev"), false},
+		{"main", Event{Type: "IssuesEvent"}, false},
+	}
+	for i, tc := range tests {
+		if got := (filters{Branch: tc.pattern}).match(tc.ev); got != tc.want {
+			t.Fatalf("case %d: match(%q)=%v want %v", i, tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestFilters_Repos(t *testing.T) {
+	repo := func(name string) Event {
+		ev := Event{Type: "WatchEvent", Payload: mustRaw(map[string]any{"action": "started"})}
+		ev.Repo.Name = name
+		return ev
+	}
+	f := filters{Repos: []string{"myorg/*", "alice/tool-?"}}
+	for name, want := range map[string]bool{
+		"myorg/api": true, "MyOrg/Web": true, "alice/tool-a": true,
+		"alice/tool-ab": false, "other/api": false, "myorg": false,
+	} {
+		if got := f.match(repo(name)); got != want {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+
+	// The filter applies before the limit, so matches further back still
+	// fill the page.
+	events := []Event{repo("other/a"), repo("myorg/a"), repo("other/b"), repo("other/c"), repo("myorg/b"), repo("myorg/c")}
+	entries := selectEntries("alice", events, filters{Repos: []string{"myorg/*"}}, 2)
+	if len(entries) != 2 || entries[1].Event.Repo.Name != "myorg/b" {
+		t.Errorf("got %+v", entries)
+	}
+}
+
+func TestFilters_Owners(t *testing.T) {
+	f := filters{Owners: []string{"myorg", "Alice"}}
+	for name, want := range map[string]bool{"myorg/api": true, "alice/dotfiles": true, "bob/api": false, "myorganization/x": false} {
+		ev := Event{Type: "WatchEvent"}
+		ev.Repo.Name = name
+		if got := f.match(ev); got != want {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseTimeArg(t *testing.T) {
+	pinClock(t)
+	tests := map[string]time.Time{
+		"":                     {},
+		"2024-05-01":           time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local),
+		"2024-05-01T12:30:00Z": time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+		"72h":                  time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+	}
+	for arg, want := range tests {
+		if got, err := parseTimeArg(arg); err != nil || !got.Equal(want) {
+			t.Errorf("parseTimeArg(%q) = %v, %v; want %v", arg, got, err, want)
+		}
+	}
+	for _, bad := range []string{"yesterday", "2024-13-01", "-5h"} {
+		if _, err := parseTimeArg(bad); err == nil {
+			t.Errorf("parseTimeArg(%q) should fail", bad)
+		}
+	}
+}
+
+func TestShortcutRange(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	restore := time.Local
+	time.Local = berlin
+	defer func() { time.Local = restore }()
+
+	// Saturday 00:30 in Berlin is still Friday in UTC.
+	at := time.Date(2024, 5, 3, 22, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, berlin) }
+	tests := []struct {
+		name         string
+		since, until time.Time
+	}{
+		{"today", day(4), day(5)},
+		{"yesterday", day(3), day(4)},
+		{"this-week", time.Date(2024, 4, 29, 0, 0, 0, 0, berlin), day(6)},
+	}
+	for _, tt := range tests {
+		since, until := shortcutRange(tt.name, at)
+		if !since.Equal(tt.since) || !until.Equal(tt.until) {
+			t.Errorf("%s: got %v - %v, want %v - %v", tt.name, since, until, tt.since, tt.until)
+		}
+	}
+}
+
+func TestFilters_TimeRange(t *testing.T) {
+	at := func(day int) Event {
+		return Event{Type: "WatchEvent", CreatedAt: time.Date(2024, 5, day, 12, 0, 0, 0, time.UTC)}
+	}
+	f := filters{Since: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)}
+	for day, want := range map[int]bool{1: false, 2: true, 3: true, 4: false} {
+		if got := f.match(at(day)); got != want {
+			t.Errorf("May %d: got %v, want %v", day, got, want)
+		}
+	}
+}
+
+func TestFilters_Grep(t *testing.T) {
+	ev := func(typ, repo string, payload any) Event {
+		e := Event{Type: typ, Payload: mustRaw(payload)}
+		e.Repo.Name = repo
+		return e
+	}
+	f := filters{Grep: regexp.MustCompile(`(?i)login`)}
+	tests := []struct {
+		name string
+		ev   Event
+		want bool
+	}{
+		{"repo name", ev("WatchEvent", "acme/login-service", map[string]any{}), true},
+		{"pr title", ev("PullRequestEvent", "acme/web", map[string]any{"pull_request": map[string]any{"title": "Fix Login redirect"}}), true},
+		{"issue title", ev("IssuesEvent", "acme/web", map[string]any{"issue": map[string]any{"title": "Crash on start"}}), false},
+		{"release", ev("ReleaseEvent", "acme/web", map[string]any{"release": map[string]any{"name": "SSO login"}}), true},
+		{"commit", ev("PushEvent", "acme/web", map[string]any{"commits": []map[string]any{{"message": "tidy"}, {"message": "add login throttling"}}}), true},
+		{"no match", ev("PushEvent", "acme/web", map[string]any{"commits": []map[string]any{{"message": "tidy"}}}), false},
+	}
+	for _, tt := range tests {
+		if got := f.match(tt.ev); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilters_NoBots(t *testing.T) {
+	comment := func(actor string, author map[string]any) Event {
+		ev := Event{Type: "IssueCommentEvent", Payload: mustRaw(map[string]any{"issue": map[string]any{"user": author}})}
+		ev.Actor.Login = actor
+		return ev
+	}
+	pr := func(actor, author string) Event {
+		ev := Event{Type: "PullRequestEvent", Payload: mustRaw(map[string]any{"pull_request": map[string]any{"user": map[string]any{"login": author}}})}
+		ev.Actor.Login = actor
+		return ev
+	}
+	tests := []struct {
+		name string
+		ev   Event
+		want bool
+	}{
+		{"bot actor", actorEvent("PushEvent", "dependabot[bot]"), false},
+		{"human actor", actorEvent("PushEvent", "alice"), true},
+		{"merging a bot PR", pr("alice", "renovate[bot]"), false},
+		{"human PR", pr("alice", "bob"), true},
+		{"comment on bot issue", comment("alice", map[string]any{"login": "some-app", "type": "Bot"}), false},
+		{"comment on human issue", comment("alice", map[string]any{"login": "bob", "type": "User"}), true},
+	}
+	for _, tt := range tests {
+		if got := (filters{NoBots: true}).match(tt.ev); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilters_Private(t *testing.T) {
+	public, private := true, false
+	events := []Event{
+		{Type: "PushEvent", Public: &public},
+		{Type: "PushEvent", Public: &private},
+		{Type: "PushEvent"}, // e.g. from a source plugin
+	}
+	for i, want := range []bool{true, false, true} {
+		if got := (filters{}).match(events[i]); got != want {
+			t.Errorf("event %d: got %v by default", i, got)
+		}
+		if !(filters{Private: true}).match(events[i]) {
+			t.Errorf("event %d: dropped with Private", i)
+		}
+	}
+
+	ev := testEntry("PushEvent", "alice/secret", "").Event
+	ev.Payload, ev.Public = mustRaw(PushPayload{Size: 2}), &private
+	entries := selectEntries("alice", []Event{ev}, filters{Private: true}, 10)
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Summary, " (private)") || !toJSONEvent(entries[0]).Private {
+		t.Errorf("private event not marked: %+v", entries)
+	}
+}
+
+func TestFilters_SinceID(t *testing.T) {
+	f := filters{SinceID: "40000000005"}
+	for id, want := range map[string]bool{
+		"40000000006": true, "40000000005": false, "40000000004": false,
+		"100000000000": true, "9": false, "99999999999999999999": true,
+	} {
+		if got := f.match(Event{ID: id}); got != want {
+			t.Errorf("ID %s: got %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestFilters_Types(t *testing.T) {
+	f := filters{Types: []string{"PushEvent", "IssuesEvent"}}
+	for typ, want := range map[string]bool{"PushEvent": true, "IssuesEvent": true, "WatchEvent": false} {
+		if got := f.match(Event{Type: typ}); got != want {
+			t.Errorf("%s: got %v", typ, got)
+		}
+	}
+}
+
+func TestFilters_ExcludeTypes(t *testing.T) {
+	f := filters{ExcludeTypes: []string{"WatchEvent", "ForkEvent"}}
+	for typ, want := range map[string]bool{"PushEvent": true, "WatchEvent": false, "ForkEvent": false} {
+		if got := f.match(Event{Type: typ}); got != want {
+			t.Errorf("%s: got %v", typ, got)
+		}
+	}
+}
+
+func TestResolveEventTypes(t *testing.T) {
+	got, err := resolveEventTypes([]string{"push", "PR", "IssuesEvent", "watchevent", "Release", "star", "deploy", "DeploymentStatus"})
+	want := "PushEvent,PullRequestEvent,IssuesEvent,WatchEvent,ReleaseEvent,DeploymentEvent,DeploymentStatusEvent"
+	if err != nil || strings.Join(got, ",") != want {
+		t.Errorf("got %v, %v; want %s", got, err, want)
+	}
+
+	for name, wantErr := range map[string]string{
+		"puhs":     `unknown event type "puhs" (did you mean "push"?)`,
+		"PushEvnt": `unknown event type "PushEvnt" (did you mean "PushEvent"?)`,
+		"workflow": `unknown event type "workflow"`,
+	} {
+		if _, err := resolveEventTypes([]string{name}); err == nil || err.Error() != wantErr {
+			t.Errorf("%s: got %v, want %s", name, err, wantErr)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{{"", "abc", 3}, {"kitten", "sitting", 3}, {"push", "push", 0}, {"puhs", "push", 1}} {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
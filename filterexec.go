@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// shellCommand runs line through the platform's shell, so --filter-exec can
+// be a pipeline or a one-liner with quoted arguments.
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
+
+// eventKey identifies an event echoed back by a filter command: its ID, or
+// for events without one (from some source plugins) its JSON.
+func eventKey(ev Event, line []byte) string {
+	if ev.ID != "" {
+		return "id:" + ev.ID
+	}
+	return "json:" + string(bytes.TrimSpace(line))
+}
+
+// execFilter writes events to command's stdin as NDJSON, in GitHub's event
+// format, and keeps those whose lines it writes back, e.g.
+// --filter-exec="jq -c 'select(.payload.size > 3)'". Order is kept from
+// events, not from the command's output.
+func execFilter(ctx context.Context, command string, events []Event) ([]Event, error) {
+	var in bytes.Buffer
+	keys := make([]string, len(events))
+	for i, ev := range events {
+		line, err := json.Marshal(ev)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = eventKey(ev, line)
+		in.Write(line)
+		in.WriteByte('\n')
+	}
+	cmd := shellCommand(ctx, command)
+	cmd.Stdin = &in
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("filter %q: %w", command, err)
+	}
+	keep := map[string]bool{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(nil, maxResponseBytes)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("filter %q: output line %q is not an event: %w", command, truncateWidth(string(line), 60), err)
+		}
+		keep[eventKey(ev, line)] = true
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("filter %q: %w", command, err)
+	}
+	var kept []Event
+	for i, ev := range events {
+		if keep[keys[i]] {
+			kept = append(kept, ev)
+		}
+	}
+	return kept, nil
+}
+
+// selectCandidates narrows events by the built-in filters f, then the
+// --filter-exec commands, then the API-backed checks. The built-in filters
+// go first so commands never see events the user didn't ask for, such as
+// private ones without --include-private.
+func selectCandidates(ctx context.Context, events []Event, f filters, commands []string, checks []eventCheck, limit int) ([]Event, error) {
+	if len(commands) == 0 && len(checks) == 0 {
+		return events, nil
+	}
+	var candidates []Event
+	for _, ev := range events {
+		if f.match(ev) {
+			candidates = append(candidates, ev)
+		}
+	}
+	if len(commands) > 0 {
+		var err error
+		if candidates, err = execFilters(ctx, commands, candidates); err != nil {
+			return nil, err
+		}
+	}
+	if len(checks) > 0 {
+		candidates = selectChecked(ctx, candidates, f, checks, limit)
+	}
+	return candidates, nil
+}
+
+// execFilters runs events through each command in turn.
+func execFilters(ctx context.Context, commands []string, events []Event) ([]Event, error) {
+	for _, c := range commands {
+		if strings.TrimSpace(c) == "" {
+			continue
+		}
+		var err error
+		if events, err = execFilter(ctx, c, events); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
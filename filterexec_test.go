@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExecFilters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+	events := []Event{
+		{ID: "1", Type: "PushEvent"},
+		{ID: "2", Type: "WatchEvent"},
+		{ID: "3", Type: "PushEvent"},
+		{Type: "PushEvent"}, // no ID
+	}
+	ids := func(evs []Event) string {
+		var s []string
+		for _, ev := range evs {
+			s = append(s, ev.ID+":"+ev.Type)
+		}
+		return strings.Join(s, ",")
+	}
+	ctx := context.Background()
+
+	got, err := execFilters(ctx, []string{"grep PushEvent"}, events)
+	if err != nil || ids(got) != "1:PushEvent,3:PushEvent,:PushEvent" {
+		t.Errorf("grep: got %s, %v", ids(got), err)
+	}
+	// Chained, and echoed in a different order.
+	got, err = execFilters(ctx, []string{"grep -v WatchEvent", `grep -v '"id":""' | sort -r`}, events)
+	if err != nil || ids(got) != "1:PushEvent,3:PushEvent" {
+		t.Errorf("chain: got %s, %v", ids(got), err)
+	}
+	if _, err := execFilters(ctx, []string{"exit 3"}, events); err == nil {
+		t.Error("failing command: want error")
+	}
+	if _, err := execFilters(ctx, []string{"echo not json"}, events); err == nil || !strings.Contains(err.Error(), "not an event") {
+		t.Errorf("bad output: got %v", err)
+	}
+}
+
+func TestSelectCandidates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+	private := false
+	events := []Event{
+		{ID: "1", Type: "PushEvent"},
+		{ID: "2", Type: "WatchEvent"},
+		{ID: "3", Type: "PushEvent", Public: &private},
+		{ID: "4", Type: "PushEvent"},
+	}
+	sent := filepath.Join(t.TempDir(), "sent")
+	notWatch := func(_ context.Context, ev Event) (bool, error) { return ev.Type != "WatchEvent", nil }
+
+	// The command drops event 4 and the check drops event 2; both apply.
+	got, err := selectCandidates(context.Background(), events, filters{}, []string{`tee ` + sent + ` | grep -v '"id":"4"'`}, []eventCheck{notWatch}, 10)
+	if err != nil || len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("got %+v, %v; want event 1 only", got, err)
+	}
+	// The private event was filtered out before reaching the command.
+	if data, err := os.ReadFile(sent); err != nil || strings.Contains(string(data), `"id":"3"`) {
+		t.Errorf("command was sent %q, %v", data, err)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A filter expression, as given to --filter, is a small boolean language over
+// event fields:
+//
+//	type == PushEvent && repo =~ "^acme/" && commits > 2
+//	!(actor == "dependabot[bot]") || title =~ "(?i)security"
+//
+// Operators: == != =~ !~ < <= > >= && || ! and parentheses. Operands are
+// field names, numbers, quoted strings, or barewords; a bareword that isn't a
+// field name is a string, so type==PushEvent needs no quotes. A field on its
+// own is true when it is non-empty and non-zero.
+
+// exprFields lists the fields available to filter expressions.
+var exprFields = map[string]func(ev Event) value{
+	"type":    func(ev Event) value { return strValue(ev.Type) },
+	"repo":    func(ev Event) value { return strValue(ev.Repo.Name) },
+	"owner":   func(ev Event) value { owner, _, _ := strings.Cut(ev.Repo.Name, "/"); return strValue(owner) },
+	"actor":   func(ev Event) value { return strValue(ev.Actor.Login) },
+	"action":  func(ev Event) value { return strValue(detailsOf(ev).Action) },
+	"number":  func(ev Event) value { return numValue(float64(detailsOf(ev).Number)) },
+	"title":   func(ev Event) value { return strValue(detailsOf(ev).Title) },
+	"branch":  func(ev Event) value { b, _ := branchOf(ev); return strValue(b) },
+	"commits": func(ev Event) value { return numValue(float64(pushSize(ev))) },
+	"summary": func(ev Event) value { s, _ := formatEvent(ev); return strValue(s) },
+}
+
+func pushSize(ev Event) int {
+	if ev.Type != "PushEvent" {
+		return 0
+	}
+	var p PushPayload
+	_ = json.Unmarshal(ev.Payload, &p)
+	return p.Size
+}
+
+// value is a field or literal: numeric when it came from a number field or
+// literal, or when a string parses as one.
+type value struct {
+	s     string
+	n     float64
+	isNum bool
+}
+
+func strValue(s string) value { return value{s: s} }
+
+func numValue(n float64) value {
+	return value{s: strconv.FormatFloat(n, 'f', -1, 64), n: n, isNum: true}
+}
+
+func (v value) num() (float64, bool) {
+	if v.isNum {
+		return v.n, true
+	}
+	n, err := strconv.ParseFloat(v.s, 64)
+	return n, err == nil
+}
+
+func (v value) truthy() bool {
+	if v.isNum {
+		return v.n != 0
+	}
+	return v.s != ""
+}
+
+// exprNode is a parsed filter expression.
+type exprNode interface {
+	eval(ev Event) bool
+}
+
+type andNode struct{ l, r exprNode }
+type orNode struct{ l, r exprNode }
+type notNode struct{ x exprNode }
+
+func (n andNode) eval(ev Event) bool { return n.l.eval(ev) && n.r.eval(ev) }
+func (n orNode) eval(ev Event) bool  { return n.l.eval(ev) || n.r.eval(ev) }
+func (n notNode) eval(ev Event) bool { return !n.x.eval(ev) }
+
+// operand is a field reference or a literal.
+type operand struct {
+	field func(Event) value
+	lit   value
+}
+
+func (o operand) get(ev Event) value {
+	if o.field != nil {
+		return o.field(ev)
+	}
+	return o.lit
+}
+
+type truthyNode struct{ x operand }
+
+func (n truthyNode) eval(ev Event) bool { return n.x.get(ev).truthy() }
+
+type compareNode struct {
+	op   string
+	l, r operand
+	re   *regexp.Regexp // =~ and !~
+}
+
+func (n compareNode) eval(ev Event) bool {
+	l, r := n.l.get(ev), n.r.get(ev)
+	switch n.op {
+	case "=~":
+		return n.re.MatchString(l.s)
+	case "!~":
+		return !n.re.MatchString(l.s)
+	}
+	cmp := strings.Compare(l.s, r.s)
+	if ln, ok := l.num(); ok {
+		if rn, ok := r.num(); ok {
+			cmp = 0
+			if ln < rn {
+				cmp = -1
+			} else if ln > rn {
+				cmp = 1
+			}
+		}
+	}
+	switch n.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // ">="
+		return cmp >= 0
+	}
+}
+
+// parseFilterExpr compiles a --filter expression.
+func parseFilterExpr(src string) (exprNode, error) {
+	toks, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("filter: unexpected %q at offset %d", t.text, t.pos)
+	}
+	return n, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-./", r)
+}
+
+func lexExpr(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				if rs[j] == '\\' && r == '"' {
+					j++
+				}
+				j++
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("filter: unterminated string at offset %d", i)
+			}
+			text := string(rs[i+1 : j])
+			if r == '"' {
+				unq, err := strconv.Unquote(string(rs[i : j+1]))
+				if err != nil {
+					return nil, fmt.Errorf("filter: bad string at offset %d: %v", i, err)
+				}
+				text = unq
+			}
+			toks = append(toks, token{tokString, text, i})
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			if j < len(rs) && isIdentRune(rs[j]) {
+				// Something like 2fa or 1.2.3: treat it as a bareword.
+				for j < len(rs) && isIdentRune(rs[j]) {
+					j++
+				}
+				toks = append(toks, token{tokIdent, string(rs[i:j]), i})
+			} else {
+				toks = append(toks, token{tokNumber, string(rs[i:j]), i})
+			}
+			i = j
+		case isIdentRune(r):
+			j := i
+			for j < len(rs) && isIdentRune(rs[j]) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, string(rs[i:j]), i})
+			i = j
+		default:
+			op := ""
+			for _, cand := range []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!"} {
+				if strings.HasPrefix(string(rs[i:]), cand) {
+					op = cand
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("filter: unexpected %q at offset %d", r, i)
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{tokEOF, "end of expression", len(rs)}), nil
+}
+
+type exprParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *exprParser) peek() token { return p.toks[p.pos] }
+
+func (p *exprParser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "||" {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = orNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "&&" {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = andNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if t := p.peek(); t.kind == tokOp && t.text == "!" {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, fmt.Errorf("filter: expected ) at offset %d, found %q", t.pos, t.text)
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokOp || t.text == "&&" || t.text == "||" || t.text == "!" {
+		return truthyNode{l}, nil
+	}
+	p.next()
+	r, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	n := compareNode{op: t.text, l: l, r: r}
+	if t.text == "=~" || t.text == "!~" {
+		if r.field != nil {
+			return nil, fmt.Errorf("filter: %s needs a pattern on the right at offset %d", t.text, t.pos)
+		}
+		if n.re, err = regexp.Compile(r.lit.s); err != nil {
+			return nil, fmt.Errorf("filter: bad pattern %q: %v", r.lit.s, err)
+		}
+	}
+	return n, nil
+}
+
+func (p *exprParser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case tokIdent:
+		if f, ok := exprFields[strings.ToLower(t.text)]; ok {
+			return operand{field: f}, nil
+		}
+		return operand{lit: strValue(t.text)}, nil
+	case tokString:
+		return operand{lit: strValue(t.text)}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return operand{}, fmt.Errorf("filter: bad number %q at offset %d", t.text, t.pos)
+		}
+		return operand{lit: numValue(n)}, nil
+	}
+	return operand{}, fmt.Errorf("filter: expected a field or value at offset %d, found %q", t.pos, t.text)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func exprEvent(typ, repo, actor string, payload any) Event {
+	ev := Event{Type: typ, Payload: mustRaw(payload)}
+	ev.Repo.Name = repo
+	ev.Actor.Login = actor
+	return ev
+}
+
+func TestFilterExpr(t *testing.T) {
+	push := exprEvent("PushEvent", "acme/api", "alice", map[string]any{"size": 3, "ref": "refs/heads/main"})
+	smallPush := exprEvent("PushEvent", "acme/web", "bob", map[string]any{"size": 1, "ref": "refs/heads/dev"})
+	issue := exprEvent("IssuesEvent", "other/tool", "dependabot[bot]", map[string]any{
+		"action": "opened", "issue": map[string]any{"number": 12, "title": "Security: bump yaml"},
+	})
+
+	tests := []struct {
+		expr string
+		ev   Event
+		want bool
+	}{
+		{`type==PushEvent && repo =~ "^acme/" && commits > 2`, push, true},
+		{`type==PushEvent && repo =~ "^acme/" && commits > 2`, smallPush, false},
+		{`type == "PushEvent" || number >= 12`, issue, true},
+		{`!(actor == "dependabot[bot]")`, issue, false},
+		{`title =~ '(?i)security' && action == opened`, issue, true},
+		{`repo !~ "^acme/"`, push, false},
+		{`branch == main`, push, true},
+		{`owner == acme && branch != main`, smallPush, true},
+		{`title`, issue, true},
+		{`title`, push, false},
+		{`number < 10`, issue, false},
+		{`commits <= 1 || commits >= 3`, push, true},
+		{`TYPE == PushEvent`, push, true},
+		{`a || b && c`, push, true}, // barewords are non-empty strings
+	}
+	for _, tc := range tests {
+		n, err := parseFilterExpr(tc.expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.expr, err)
+		}
+		if got := n.eval(tc.ev); got != tc.want {
+			t.Fatalf("%q on %s: got %v want %v", tc.expr, tc.ev.Type, got, tc.want)
+		}
+	}
+}
+
+func TestFilterExpr_Precedence(t *testing.T) {
+	ev := exprEvent("WatchEvent", "acme/api", "alice", nil)
+	// && binds tighter than ||: false || (true && true)
+	n, err := parseFilterExpr(`type == PushEvent || repo == acme/api && actor == alice`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.eval(ev) {
+		t.Fatal("expected && to bind tighter than ||")
+	}
+}
+
+func TestFilterExpr_Errors(t *testing.T) {
+	tests := map[string]string{
+		`type ==`:             "expected a field or value",
+		`(type == PushEvent`:  "expected )",
+		`repo =~ "["`:         "bad pattern",
+		`title =~ repo`:       "needs a pattern",
+		`type == "PushEvent`:  "unterminated string",
+		`type == PushEvent )`: "unexpected",
+		`type # 1`:            "unexpected",
+	}
+	for src, want := range tests {
+		_, err := parseFilterExpr(src)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("parse %q: got %v, want error containing %q", src, err, want)
+		}
+	}
+}
+
+func TestFilters_Expr(t *testing.T) {
+	n, _ := parseFilterExpr(`commits > 1`)
+	f := filters{Expr: n}
+	if f.match(exprEvent("PushEvent", "a/b", "", map[string]any{"size": 1})) {
+		t.Fatal("expected expression to reject small push")
+	}
+	if !f.match(exprEvent("PushEvent", "a/b", "", map[string]any{"size": 2})) {
+		t.Fatal("expected expression to keep larger push")
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appTokenMargin is how long before an installation token expires that it
+// is replaced, so a request never goes out with one about to lapse.
+const appTokenMargin = 5 * time.Minute
+
+// appMintTimeout bounds minting a token. Minting runs apart from the
+// request that needs the token, so a caller giving up doesn't fail it.
+const appMintTimeout = 30 * time.Second
+
+// appRetryMin and appRetryMax bound the wait after a failed mint before the
+// next try, which doubles with each failure in a row.
+const (
+	appRetryMin = 30 * time.Second
+	appRetryMax = 10 * time.Minute
+)
+
+// appAuth authenticates as a GitHub App installation: it signs a JWT with
+// the app's private key and exchanges it for an installation token, which
+// lasts an hour and is renewed as needed.
+type appAuth struct {
+	ID           string // app ID or client ID
+	Key          *rsa.PrivateKey
+	Installation string // installation ID; empty finds the app's only one
+
+	mu       sync.Mutex
+	token    string
+	expires  time.Time
+	failures int       // failed mints in a row
+	retryAt  time.Time // when to try minting again after a failure
+}
+
+// errAppFailed is returned by appAuth.credential while it waits to retry a
+// failed mint, whose error has already been returned once.
+var errAppFailed = errors.New("GitHub App token unavailable")
+
+// githubApp is the app to authenticate as, set by main from the
+// environment. Nil means no app.
+var githubApp *appAuth
+
+// appFromEnv reads GitHub App settings: $GITHUB_APP_ID, the PEM private key
+// in $GITHUB_APP_PRIVATE_KEY or the file $GITHUB_APP_PRIVATE_KEY_FILE, and
+// optionally $GITHUB_APP_INSTALLATION_ID. It returns nil without an app ID.
+func appFromEnv() (*appAuth, error) {
+	id := strings.TrimSpace(os.Getenv("GITHUB_APP_ID"))
+	if id == "" {
+		return nil, nil
+	}
+	data := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"); len(data) == 0 && path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if len(data) == 0 {
+		return nil, errors.New("$GITHUB_APP_ID is set, but neither $GITHUB_APP_PRIVATE_KEY nor $GITHUB_APP_PRIVATE_KEY_FILE is")
+	}
+	key, err := parseAppKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return &appAuth{ID: id, Key: key, Installation: strings.TrimSpace(os.Getenv("GITHUB_APP_INSTALLATION_ID"))}, nil
+}
+
+// parseAppKey reads an app's private key as GitHub issues it (PKCS #1), or
+// converted to PKCS #8.
+func parseAppKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("GitHub App private key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub App private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key: not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// jwt signs the short-lived token that authenticates as the app itself. It
+// is backdated a minute for clock drift and lasts nine, under GitHub's
+// ten-minute cap.
+func (a *appAuth) jwt(t time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+	var iss any = a.ID
+	if n, err := strconv.ParseInt(a.ID, 10, 64); err == nil {
+		iss = n
+	}
+	claims, err := json.Marshal(map[string]any{"iat": t.Add(-time.Minute).Unix(), "exp": t.Add(9 * time.Minute).Unix(), "iss": iss})
+	if err != nil {
+		return "", err
+	}
+	signed := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.Key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
+
+// appRequest calls an app endpoint with the app's JWT.
+func appRequest(ctx context.Context, method, path, jwt string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	debugf("%s %s: %s (GitHub App JWT)", method, req.URL.Redacted(), resp.Status)
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("%s %s: %s %s", method, path, resp.Status, e.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// installationID is a.Installation, or the app's only installation.
+func (a *appAuth) installationID(ctx context.Context, jwt string) (string, error) {
+	if a.Installation != "" {
+		return a.Installation, nil
+	}
+	var installs []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := appRequest(ctx, http.MethodGet, "/app/installations", jwt, &installs); err != nil {
+		return "", err
+	}
+	switch len(installs) {
+	case 0:
+		return "", errors.New("the app isn't installed anywhere")
+	case 1:
+		a.Installation = strconv.FormatInt(installs[0].ID, 10)
+		return a.Installation, nil
+	}
+	var names []string
+	for _, in := range installs {
+		names = append(names, fmt.Sprintf("%d (%s)", in.ID, in.Account.Login))
+	}
+	return "", fmt.Errorf("the app has several installations; set $GITHUB_APP_INSTALLATION_ID to one of %s", strings.Join(names, ", "))
+}
+
+// credential returns a current installation token, fetching a new one when
+// there is none or it is about to expire. A failed fetch is retried after a
+// backoff, so a service recovers from an outage; until then credential
+// returns errAppFailed rather than trying again on every request. Safe for
+// concurrent use.
+func (a *appAuth) credential(ctx context.Context) (credential, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == "" || !now().Before(a.expires.Add(-appTokenMargin)) {
+		if now().Before(a.retryAt) {
+			return credential{}, errAppFailed
+		}
+		mintCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), appMintTimeout)
+		defer cancel()
+		if err := a.mint(mintCtx); err != nil {
+			a.failures++
+			a.retryAt = now().Add(min(appRetryMin<<min(a.failures-1, 5), appRetryMax))
+			return credential{}, err
+		}
+		a.failures, a.retryAt = 0, time.Time{}
+	}
+	return credential{Token: a.token, Source: fmt.Sprintf("GitHub App %s (installation %s)", a.ID, a.Installation)}, nil
+}
+
+// mint fetches a new installation token. The caller holds a.mu.
+func (a *appAuth) mint(ctx context.Context) error {
+	jwt, err := a.jwt(now())
+	if err != nil {
+		return err
+	}
+	id, err := a.installationID(ctx, jwt)
+	if err != nil {
+		return err
+	}
+	var tok struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := appRequest(ctx, http.MethodPost, "/app/installations/"+id+"/access_tokens", jwt, &tok); err != nil {
+		return err
+	}
+	a.token, a.expires = tok.Token, tok.ExpiresAt
+	return nil
+}
+
+// appCredential is the installation token of githubApp, if one is set up.
+// Each failed mint is warned about; while the app has no token, requests
+// fall back to the other credentials.
+func appCredential(ctx context.Context) credential {
+	if githubApp == nil {
+		return credential{}
+	}
+	c, err := githubApp.credential(ctx)
+	if err != nil && !errors.Is(err, errAppFailed) {
+		fmt.Fprintf(warnings, "Warning: GitHub App: %v\n", err)
+	}
+	return c
+}
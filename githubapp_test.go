@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAppKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestAppJWT(t *testing.T) {
+	key := testAppKey(t)
+	a := &appAuth{ID: "12345", Key: key}
+	at := time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC)
+	jwt, err := a.jwt(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("got %q", jwt)
+	}
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig); err != nil {
+		t.Errorf("signature: %v", err)
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]any
+	json.Unmarshal(payload, &claims)
+	if claims["iss"] != float64(12345) || claims["iat"] != float64(at.Unix()-60) || claims["exp"] != float64(at.Unix()+540) {
+		t.Errorf("claims: %v", claims)
+	}
+}
+
+func TestParseAppKey(t *testing.T) {
+	key := testAppKey(t)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	for name, data := range map[string][]byte{"PKCS #1": pkcs1, "PKCS #8": pkcs8} {
+		if got, err := parseAppKey(data); err != nil || !got.Equal(key) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := parseAppKey([]byte("not a key")); err == nil {
+		t.Error("garbage: no error")
+	}
+}
+
+func TestAppCredential(t *testing.T) {
+	pinClock(t)
+	key := testAppKey(t)
+	exchanges := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ey") {
+			t.Errorf("%s: no JWT", r.URL.Path)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/app/installations":
+			w.Write([]byte(`[{"id": 678, "account": {"login": "acme"}}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/678/access_tokens":
+			exchanges++
+			fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, exchanges, now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	a := &appAuth{ID: "12345", Key: key}
+	c, err := a.credential(context.Background())
+	if err != nil || c != (credential{"ghs_1", "GitHub App 12345 (installation 678)"}) {
+		t.Fatalf("got %+v, %v", c, err)
+	}
+	if c, _ := a.credential(context.Background()); c.Token != "ghs_1" {
+		t.Errorf("reused: got %+v", c)
+	}
+
+	later := now().Add(56 * time.Minute) // inside the renewal margin
+	now = func() time.Time { return later }
+	if c, _ := a.credential(context.Background()); c.Token != "ghs_2" || exchanges != 2 {
+		t.Errorf("renewed: got %+v after %d exchanges", c, exchanges)
+	}
+}
+
+func TestAppCredentialRecovers(t *testing.T) {
+	pinClock(t)
+	requests, failing := 0, true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, `{"token": "ghs_ok", "expires_at": %q}`, now().Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer srv.Close()
+	restoreURL, restoreApp, restoreWarnings := apiURL, githubApp, warnings
+	var warned strings.Builder
+	apiURL, githubApp, warnings = srv.URL, &appAuth{ID: "12345", Key: testAppKey(t), Installation: "678"}, &warned
+	defer func() { apiURL, githubApp, warnings = restoreURL, restoreApp, restoreWarnings }()
+
+	// A failure is reported once, and requests don't retry it until the
+	// backoff ends.
+	for range 3 {
+		if c := appCredential(context.Background()); c.Token != "" {
+			t.Fatalf("got %+v", c)
+		}
+	}
+	if requests != 1 || strings.Count(warned.String(), "Warning: GitHub App") != 1 {
+		t.Errorf("%d requests, warnings %q; want one of each", requests, warned.String())
+	}
+
+	// Once the outage is over, the next try after the backoff succeeds,
+	// even for a caller that has already given up.
+	failing = false
+	later := now().Add(appRetryMin)
+	now = func() time.Time { return later }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if c := appCredential(ctx); c.Token != "ghs_ok" || requests != 2 {
+		t.Errorf("after backoff: got %+v after %d requests", c, requests)
+	}
+}
+
+func TestAppCredentialBackoff(t *testing.T) {
+	pinClock(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "A JSON web token could not be decoded"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	a := &appAuth{ID: "12345", Key: testAppKey(t), Installation: "678"}
+	var waits []time.Duration
+	for range 7 {
+		if _, err := a.credential(context.Background()); err == nil || errors.Is(err, errAppFailed) {
+			t.Fatalf("got %v, want the mint's error", err)
+		}
+		waits = append(waits, a.retryAt.Sub(now()))
+		retry := a.retryAt
+		now = func() time.Time { return retry }
+	}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, appRetryMax, appRetryMax}
+	if fmt.Sprint(waits) != fmt.Sprint(want) {
+		t.Errorf("waits %v, want %v", waits, want)
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// goalRiskShare is how far into its period a goal that isn't met yet counts
+// as at risk.
+const goalRiskShare = 0.75
+
+// Goal is an activity target from the goals file, e.g.
+//
+//	{"name": "PR reviews", "types": ["review"], "count": 5, "per": "week"}
+//	{"name": "OSS contribution", "filter": "owner != \"alice\"", "count": 1, "per": "day"}
+type Goal struct {
+	Name   string   `json:"name"`
+	Types  []string `json:"types"`  // event types or --type aliases; empty means any
+	Filter string   `json:"filter"` // optional --filter expression
+	Count  int      `json:"count"`
+	Per    string   `json:"per"` // day, week, or month
+}
+
+// goalProgress is a goal's standing in the current period.
+type goalProgress struct {
+	Goal
+	Done    int
+	Elapsed float64 // share of the period that has passed, 0-1
+	Left    time.Duration
+}
+
+func (p goalProgress) met() bool    { return p.Done >= p.Count }
+func (p goalProgress) atRisk() bool { return !p.met() && p.Elapsed >= goalRiskShare }
+
+// goalsPath is where goals are read from: $GITHUB_ACTIVITY_GOALS, or
+// goals.json next to the config file.
+func goalsPath() string {
+	if p := os.Getenv("GITHUB_ACTIVITY_GOALS"); p != "" {
+		return p
+	}
+	if c := configPath(); c != "" {
+		return filepath.Join(filepath.Dir(c), "goals.json")
+	}
+	return ""
+}
+
+func loadGoals(path string) ([]Goal, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no goals file at %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var goals []Goal
+	if err := json.Unmarshal(data, &goals); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, g := range goals {
+		if g.Name == "" {
+			return nil, fmt.Errorf("%s: goal %d has no name", path, i+1)
+		}
+		if g.Count < 1 {
+			return nil, fmt.Errorf("%s: goal %q needs a count of at least 1", path, g.Name)
+		}
+		if _, _, err := periodBounds(g.Per, now()); err != nil {
+			return nil, fmt.Errorf("%s: goal %q: %w", path, g.Name, err)
+		}
+	}
+	return goals, nil
+}
+
+// periodBounds returns the local day, week (from Monday), or month that t
+// falls in.
+func periodBounds(per string, t time.Time) (start, end time.Time, err error) {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch per {
+	case "day":
+		return day, day.AddDate(0, 0, 1), nil
+	case "week":
+		start = day.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), nil
+	case "month":
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q (want day, week, or month)", per)
+}
+
+// goalStatus counts the events that match g in its current period.
+func goalStatus(g Goal, events []Event) (goalProgress, error) {
+	types, err := resolveEventTypes(g.Types)
+	if err != nil {
+		return goalProgress{}, err
+	}
+	f := filters{Types: types}
+	if g.Filter != "" {
+		if f.Expr, err = parseFilterExpr(g.Filter); err != nil {
+			return goalProgress{}, err
+		}
+	}
+	t := now()
+	start, end, err := periodBounds(g.Per, t)
+	if err != nil {
+		return goalProgress{}, err
+	}
+	p := goalProgress{
+		Goal:    g,
+		Elapsed: float64(t.Sub(start)) / float64(end.Sub(start)),
+		Left:    end.Sub(t),
+	}
+	for _, ev := range events {
+		if !ev.CreatedAt.Before(start) && f.match(ev) {
+			p.Done++
+		}
+	}
+	return p, nil
+}
+
+func runGoals(args []string) int {
+	fs := flag.NewFlagSet("goals", flag.ExitOnError)
+	file := fs.String("file", "", "Goals file, a JSON array of {name, types, filter, count, per} (default: $GITHUB_ACTIVITY_GOALS, or goals.json next to the config file).")
+	notify := fs.Bool("notify", false, "Send a desktop notification for goals at risk of being missed.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s goals [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Reports progress toward activity goals for the current day, week, or month.")
+		fmt.Fprintf(fs.Output(), "A goal is at risk when it isn't met %d%% of the way through its period.\n", int(goalRiskShare*100))
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *file == "" {
+		*file = goalsPath()
+	}
+	goals, err := loadGoals(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	events, err := fetchEvents(context.Background(), fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	var progress []goalProgress
+	for _, g := range goals {
+		p, err := goalStatus(g, events)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: goal %q: %v\n", g.Name, err)
+			return 2
+		}
+		progress = append(progress, p)
+	}
+	printGoals(os.Stdout, progress)
+
+	if *notify {
+		for _, p := range progress {
+			if !p.atRisk() {
+				continue
+			}
+			msg := fmt.Sprintf("%d of %d done, %s left", p.Done, p.Count, formatLeft(p.Left))
+			if err := notifyDesktop("Goal at risk: "+p.Name, msg); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: notification failed:", err)
+			}
+		}
+	}
+	return 0
+}
+
+var periodNames = map[string]string{"day": "today", "week": "this week", "month": "this month"}
+
+func printGoals(w io.Writer, progress []goalProgress) {
+	width := 0
+	for _, p := range progress {
+		width = max(width, displayWidth(p.Name))
+	}
+	for _, p := range progress {
+		status := fmt.Sprintf("%d/%d %s", p.Done, p.Count, periodNames[p.Per])
+		switch {
+		case p.met():
+			status += "  ✓ met"
+		case p.atRisk():
+			status += fmt.Sprintf("  ! at risk (%s left)", formatLeft(p.Left))
+		}
+		fmt.Fprintf(w, "%s  %s\n", padWidth(p.Name, width), status)
+	}
+}
+
+// formatLeft renders the time left in a period coarsely.
+func formatLeft(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	}
+	if d >= 2*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
+
+// notifyDesktop shows a desktop notification with the platform's own tool.
+var notifyDesktop = func(title, body string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		return exec.Command("osascript", "-e", script).Run()
+	case "windows":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		script := "Add-Type -AssemblyName System.Windows.Forms; " +
+			"$n = New-Object System.Windows.Forms.NotifyIcon; " +
+			"$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; " +
+			"$n.ShowBalloonTip(10000, " + quote(title) + ", " + quote(body) + ", 'Warning'); " +
+			"Start-Sleep -Seconds 10; $n.Dispose()"
+		return exec.Command("powershell", "-NoProfile", "-Command", script).Run()
+	}
+	return exec.Command("notify-send", title, body).Run()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	at := time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC) // a Saturday
+	restore := time.Local
+	time.Local = time.UTC
+	defer func() { time.Local = restore }()
+
+	for per, want := range map[string][2]string{
+		"day":   {"2024-05-04", "2024-05-05"},
+		"week":  {"2024-04-29", "2024-05-06"},
+		"month": {"2024-05-01", "2024-06-01"},
+	} {
+		start, end, err := periodBounds(per, at)
+		if err != nil || start.Format(time.DateOnly) != want[0] || end.Format(time.DateOnly) != want[1] {
+			t.Errorf("%s: %v–%v, %v", per, start, end, err)
+		}
+	}
+	if _, _, err := periodBounds("fortnight", at); err == nil {
+		t.Error("expected error for unknown period")
+	}
+}
+
+func TestGoalStatus(t *testing.T) {
+	pinClock(t) // Saturday 2024-05-04 09:00, 77% through the week
+	ev := func(typ, repo string, day int) Event {
+		e := Event{Type: typ, CreatedAt: time.Date(2024, 5, day, 8, 0, 0, 0, time.UTC)}
+		e.Repo.Name = repo
+		return e
+	}
+	events := []Event{
+		ev("PullRequestReviewEvent", "acme/api", 4),
+		ev("PullRequestReviewEvent", "acme/web", 2),
+		ev("PushEvent", "torvalds/linux", 4),
+		ev("PullRequestReviewEvent", "acme/api", 1),
+		{Type: "PullRequestReviewEvent", CreatedAt: time.Date(2024, 4, 28, 23, 0, 0, 0, time.UTC)}, // last week
+	}
+	reviews, err := goalStatus(Goal{Name: "Reviews", Types: []string{"review"}, Count: 5, Per: "week"}, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reviews.Done != 3 || reviews.met() || !reviews.atRisk() || reviews.Left != 39*time.Hour {
+		t.Errorf("reviews: %+v", reviews)
+	}
+	oss, err := goalStatus(Goal{Name: "OSS", Filter: `owner != "acme"`, Count: 1, Per: "day"}, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oss.Done != 1 || !oss.met() || oss.atRisk() {
+		t.Errorf("oss: %+v", oss)
+	}
+
+	var b strings.Builder
+	printGoals(&b, []goalProgress{reviews, oss})
+	want := "Reviews  3/5 this week  ! at risk (39 hours left)\nOSS      1/1 today  ✓ met\n"
+	if b.String() != want {
+		t.Errorf("got %q\nwant %q", b.String(), want)
+	}
+}
+
+func TestLoadGoals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goals.json")
+	for content, wantErr := range map[string]string{
+		`[{"name": "PRs", "types": ["pr"], "count": 2, "per": "week"}]`: "",
+		`[{"name": "PRs", "count": 0, "per": "week"}]`:                  `goal "PRs" needs a count of at least 1`,
+		`[{"name": "PRs", "count": 1, "per": "year"}]`:                  `invalid period "year"`,
+		`[{"count": 1, "per": "day"}]`:                                  "goal 1 has no name",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadGoals(path)
+		if wantErr == "" && err != nil || wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)) {
+			t.Errorf("%s: got %v, want %q", content, err, wantErr)
+		}
+	}
+	if _, err := loadGoals(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "no goals file") {
+		t.Errorf("missing file: %v", err)
+	}
+}
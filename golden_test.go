@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// goldenTemplate is used for the template format, which needs a template.
+const goldenTemplate = `{{.ID}} {{.Type}} {{.Repo}} action={{.Action}} number={{.Number}} title={{.Title}}`
+
+func loadFixtureEvents(t *testing.T) []Event {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []Event
+	if err := json.Unmarshal(b, &events); err != nil {
+		t.Fatalf("decode fixtures: %v", err)
+	}
+	return events
+}
+
+// pinClock makes "generated at" stamps and local-time rendering
+// deterministic for the duration of a test.
+func pinClock(t *testing.T) {
+	t.Helper()
+	restoreNow, restoreLocal := now, time.Local
+	now = func() time.Time { return time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC) }
+	time.Local = time.UTC
+	t.Cleanup(func() { now, time.Local = restoreNow, restoreLocal })
+}
+
+// checkGolden compares got with testdata/golden/name, or rewrites the file
+// when the test binary runs with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from golden file (run go test -update to accept)\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+// TestGoldenFormats renders every fixture event in every output format.
+// Adding a format to formats or an event to testdata/events.json extends
+// the coverage automatically; run go test -update and review the diff.
+func TestGoldenFormats(t *testing.T) {
+	pinClock(t)
+	events := loadFixtureEvents(t)
+	entries := selectEntries("alice", events, filters{}, 100)
+
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			var buf strings.Builder
+			out, err := newRenderer(&buf, outputOptions{Format: format, Template: goldenTemplate})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := out.feed("alice", events, entries); err != nil {
+				t.Fatal(err)
+			}
+			if err := out.flush(); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, format+".golden", buf.String())
+		})
+	}
+}
+
+// TestFixturesCoverSupportedTypes guards against fixtures silently dropping
+// out of the golden files: every fixture except the deliberately unknown
+// type must be printable.
+func TestFixturesCoverSupportedTypes(t *testing.T) {
+	for _, ev := range loadFixtureEvents(t) {
+		_, ok := formatEvent(ev)
+		if want := ev.Type != "GollumEvent"; ok != want {
+			t.Errorf("formatEvent(%s) ok=%v want %v", ev.Type, ok, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// htmlRenderer buffers every entry and writes a standalone HTML report with a
+// per-day activity chart and an event table. All CSS and JS is inline so the
+// file can be opened locally or published as-is.
+type htmlRenderer struct {
+	w       io.Writer
+	users   []string
+	entries []entry
+}
+
+func (r *htmlRenderer) feed(user string, _ []Event, entries []entry) error {
+	r.users = append(r.users, user)
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+type htmlDay struct {
+	Date    string
+	Count   int
+	Percent int // bar height relative to the busiest day
+}
+
+type htmlRow struct {
+	Time    string
+	User    string
+	Type    string
+	Repo    string
+	RepoURL string
+	Summary string
+	URL     string
+}
+
+func (r *htmlRenderer) flush() error {
+	data := struct {
+		Users     []string
+		Generated string
+		Days      []htmlDay
+		FirstDay  string
+		LastDay   string
+		Rows      []htmlRow
+	}{
+		Users:     r.users,
+		Generated: now().Format(time.RFC1123),
+		Days:      activityByDay(r.entries),
+	}
+	if n := len(data.Days); n > 0 {
+		data.FirstDay, data.LastDay = data.Days[0].Date, data.Days[n-1].Date
+	}
+	for _, e := range r.entries {
+		data.Rows = append(data.Rows, htmlRow{
+			Time:    e.Event.CreatedAt.Local().Format("2006-01-02 15:04"),
+			User:    e.User,
+			Type:    e.Event.Type,
+			Repo:    e.Event.Repo.Name,
+			RepoURL: repoURL(e.Event.Repo.Name),
+			Summary: actorPrefix(e) + e.Summary,
+			URL:     entityURL(e.Event),
+		})
+	}
+	return htmlReport.Execute(r.w, data)
+}
+
+// activityByDay counts entries per local calendar day, oldest first, filling
+// in quiet days so the chart has a continuous time axis.
+func activityByDay(entries []entry) []htmlDay {
+	if len(entries) == 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	first, last := entries[0].Event.CreatedAt, entries[0].Event.CreatedAt
+	for _, e := range entries {
+		at := e.Event.CreatedAt
+		counts[at.Local().Format(time.DateOnly)]++
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	max := 0
+	for _, c := range counts {
+		if c > max {
+			max = c
+		}
+	}
+
+	var days []htmlDay
+	end := last.Local().Format(time.DateOnly)
+	for d := first.Local(); ; d = d.AddDate(0, 0, 1) {
+		date := d.Format(time.DateOnly)
+		c := counts[date]
+		days = append(days, htmlDay{Date: date, Count: c, Percent: c * 100 / max})
+		if date == end {
+			break
+		}
+	}
+	return days
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>GitHub activity{{range $i, $u := .Users}}{{if $i}},{{end}} {{$u}}{{end}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 960px; color: #1f2328; }
+  h1 { font-size: 1.5rem; }
+  .meta { color: #656d76; font-size: .875rem; }
+  .chart { display: flex; align-items: flex-end; gap: 2px; height: 120px; border-bottom: 1px solid #d0d7de; margin: 1.5rem 0 .25rem; }
+  .bar { flex: 1; background: #2da44e; min-height: 1px; }
+  .bar.empty { background: #ebedf0; }
+  .axis { display: flex; justify-content: space-between; color: #656d76; font-size: .75rem; }
+  input { margin: 1.5rem 0 .5rem; padding: .4rem; width: 100%; box-sizing: border-box; }
+  table { border-collapse: collapse; width: 100%; font-size: .875rem; }
+  th, td { text-align: left; padding: .4rem .5rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+  th { background: #f6f8fa; }
+  td.time { white-space: nowrap; color: #656d76; }
+  a { color: #0969da; text-decoration: none; }
+</style>
+</head>
+<body>
+<h1>GitHub activity{{range $i, $u := .Users}}{{if $i}},{{end}} {{$u}}{{end}}</h1>
+<p class="meta">{{len .Rows}} events · generated {{.Generated}}</p>
+{{if .Days}}
+<div class="chart">
+{{- range .Days}}
+  <div class="bar{{if not .Count}} empty{{end}}" style="height: {{.Percent}}%" title="{{.Date}}: {{.Count}} events"></div>
+{{- end}}
+</div>
+<div class="axis"><span>{{.FirstDay}}</span><span>{{.LastDay}}</span></div>
+{{end}}
+<input id="filter" type="search" placeholder="Filter events…" aria-label="Filter events">
+<table>
+<thead><tr><th>Time</th><th>User</th><th>Type</th><th>Repository</th><th>Summary</th></tr></thead>
+<tbody id="events">
+{{- range .Rows}}
+<tr><td class="time">{{.Time}}</td><td>{{.User}}</td><td>{{.Type}}</td><td><a href="{{.RepoURL}}">{{.Repo}}</a></td><td><a href="{{.URL}}">{{.Summary}}</a></td></tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.getElementById("filter").addEventListener("input", function (e) {
+  var q = e.target.value.toLowerCase();
+  document.querySelectorAll("#events tr").forEach(function (tr) {
+    tr.style.display = tr.textContent.toLowerCase().indexOf(q) === -1 ? "none" : "";
+  });
+});
+</script>
+</body>
+</html>
+`))
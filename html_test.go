@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActivityByDay_FillsGaps(t *testing.T) {
+	at := func(day int) entry {
+		return entry{Event: Event{CreatedAt: time.Date(2024, 5, day, 12, 0, 0, 0, time.Local)}}
+	}
+	days := activityByDay([]entry{at(3), at(1), at(3)})
+	if len(days) != 3 {
+		t.Fatalf("want 3 days, got %+v", days)
+	}
+	if days[0].Date != "2024-05-01" || days[0].Count != 1 || days[0].Percent != 50 {
+		t.Fatalf("unexpected first day: %+v", days[0])
+	}
+	if days[1].Count != 0 || days[2].Count != 2 || days[2].Percent != 100 {
+		t.Fatalf("unexpected days: %+v", days)
+	}
+}
+
+func TestHTMLRenderer_EscapesAndLinks(t *testing.T) {
+	var buf strings.Builder
+	r := &htmlRenderer{w: &buf}
+	e := testEntry("IssuesEvent", "alice/repo", "Opened an issue #1 “<script>” in alice/repo")
+	e.Event.Payload = mustRaw(map[string]any{"issue": map[string]any{"number": 1}})
+	_ = r.feed("alice", []Event{e.Event}, []entry{e})
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if strings.Contains(got, "“<script>”") {
+		t.Fatal("summary was not HTML-escaped")
+	}
+	if !strings.Contains(got, `href="https://github.com/alice/repo/issues/1"`) {
+		t.Fatal("missing issue link")
+	}
+	if !strings.Contains(got, `class="chart"`) {
+		t.Fatal("missing activity chart")
+	}
+}
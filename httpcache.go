@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedResponse is a GET response kept for conditional requests: when
+// GitHub answers If-None-Match with 304 Not Modified, the body and Link
+// header are reused. GitHub doesn't count 304s against the rate limit.
+type cachedResponse struct {
+	ETag    string          `json:"etag"`
+	Link    string          `json:"link,omitempty"`
+	Body    json.RawMessage `json:"body"`
+	Fetched time.Time       `json:"fetched"` // when GitHub last confirmed it
+}
+
+// etagCache stores responses by cacheKey, one file each, in dir. Responses
+// younger than ttl are used without asking GitHub at all.
+type etagCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// responseCache is the cache doGetJSON revalidates against, or nil to make
+// every request unconditional. main sets it up; tests leave it off.
+var responseCache *etagCache
+
+// defaultETagCache keeps responses in the user cache directory ($XDG_CACHE_HOME
+// on Linux), or returns nil when there is none.
+func defaultETagCache() *etagCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return &etagCache{dir: filepath.Join(dir, "github-activity", "http")}
+}
+
+// cacheKey identifies a response by its endpoint and the token it was
+// fetched with, so a run with another token, or none, never gets responses
+// meant for someone else (private events, /user) from the cache.
+func cacheKey(endpoint, token string) string {
+	if token == "" {
+		return endpoint
+	}
+	sum := sha256.Sum256([]byte(token))
+	return endpoint + " " + hex.EncodeToString(sum[:])
+}
+
+// cacheFile is where a cache in dir keeps the entry for key.
+func cacheFile(dir, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *etagCache) path(key string) string { return cacheFile(c.dir, key) }
+
+// get returns the stored response for key. A missing or unreadable
+// entry is just a miss.
+func (c *etagCache) get(key string) (cachedResponse, bool) {
+	var r cachedResponse
+	if c == nil {
+		return r, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil || json.Unmarshal(data, &r) != nil || r.ETag == "" {
+		return cachedResponse{}, false
+	}
+	return r, true
+}
+
+// fresh reports whether r is young enough to use without revalidating.
+func (c *etagCache) fresh(r cachedResponse) bool {
+	return c != nil && c.ttl > 0 && now().Sub(r.Fetched) < c.ttl
+}
+
+// put stores a response, stamped with the current time. Failures only cost
+// a full request next time, so they are ignored.
+func (c *etagCache) put(key string, r cachedResponse) {
+	if c == nil || r.ETag == "" || !json.Valid(r.Body) {
+		return
+	}
+	r.Fetched = now()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	_ = os.MkdirAll(c.dir, 0o700)
+	// Responses fetched with a token can be private, so only the user may read them.
+	_ = os.WriteFile(c.path(key), data, 0o600)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	var statuses []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			statuses = append(statuses, http.StatusNotModified)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		statuses = append(statuses, http.StatusOK)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<`+r.Host+`/next>; rel="next"`)
+		_, _ = w.Write([]byte(`{"login": "alice"}`))
+	}))
+	defer srv.Close()
+	restore := responseCache
+	responseCache = &etagCache{dir: t.TempDir()}
+	defer func() { responseCache = restore }()
+
+	for i := range 2 {
+		var v struct{ Login string }
+		var link string
+		if err := doGetJSON(context.Background(), srv.URL+"/users/alice", &v, &link); err != nil {
+			t.Fatal(err)
+		}
+		if v.Login != "alice" || link == "" {
+			t.Errorf("request %d: got %+v, link %q", i+1, v, link)
+		}
+	}
+	if len(statuses) != 2 || statuses[0] != http.StatusOK || statuses[1] != http.StatusNotModified {
+		t.Errorf("statuses: %v", statuses)
+	}
+}
+
+func TestETagCacheIgnoresBadEntries(t *testing.T) {
+	c := &etagCache{dir: t.TempDir()}
+	c.put("a", cachedResponse{Body: []byte(`{}`)}) // no ETag
+	c.put("b", cachedResponse{ETag: `"x"`, Body: []byte(`{`)})
+	for _, endpoint := range []string{"a", "b", "c"} {
+		if _, ok := c.get(endpoint); ok {
+			t.Errorf("%s: unexpected hit", endpoint)
+		}
+	}
+	var nilCache *etagCache
+	nilCache.put("a", cachedResponse{ETag: `"x"`, Body: []byte(`{}`)})
+	if _, ok := nilCache.get("a"); ok {
+		t.Error("nil cache hit")
+	}
+}
+
+func TestResponseCacheTTL(t *testing.T) {
+	pinClock(t)
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"login": "alice"}`))
+	}))
+	defer srv.Close()
+	restore := responseCache
+	responseCache = &etagCache{dir: t.TempDir(), ttl: 2 * time.Minute}
+	defer func() { responseCache = restore }()
+
+	get := func() {
+		t.Helper()
+		var v struct{ Login string }
+		if err := getJSON(context.Background(), srv.URL+"/users/alice", &v); err != nil || v.Login != "alice" {
+			t.Fatalf("got %+v, %v", v, err)
+		}
+	}
+	get()
+	get()
+	if requests != 1 {
+		t.Errorf("within ttl: %d requests", requests)
+	}
+	base := now()
+	now = func() time.Time { return base.Add(3 * time.Minute) }
+	get()
+	if requests != 2 {
+		t.Errorf("after ttl: %d requests", requests)
+	}
+}
+
+func TestResponseCacheSeparatesTokens(t *testing.T) {
+	pinClock(t)
+	var auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"login": "alice"}`))
+	}))
+	defer srv.Close()
+	restore := responseCache
+	responseCache = &etagCache{dir: t.TempDir(), ttl: 2 * time.Minute}
+	defer func() { responseCache = restore }()
+	restoreURL := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restoreURL }()
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	for _, token := range []string{"alice-token", "alice-token", "bob-token", ""} {
+		t.Setenv("GH_ENTERPRISE_TOKEN", token)
+		var v struct{ Login string }
+		if err := getJSON(context.Background(), apiURL+"/user", &v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The repeat with alice's token is served from the cache; the others aren't.
+	if want := "Bearer alice-token,Bearer bob-token,"; strings.Join(auths, ",") != want {
+		t.Errorf("requests: %q, want %q", strings.Join(auths, ","), want)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// keyringService is the service name tokens are filed under in the OS
+// credential store, with the GitHub host as the account.
+const keyringService = "github-activity"
+
+// keyring is an OS credential store: the macOS Keychain, Windows Credential
+// Manager, or the Secret Service (GNOME Keyring, KWallet) elsewhere.
+type keyring interface {
+	name() string
+	get(host string) (string, error)
+	set(host, secret string) error
+	delete(host string) error
+}
+
+// tokenKeyring is where `auth login` keeps tokens, set by main from
+// newKeyring unless $GITHUB_ACTIVITY_NO_KEYRING is set. Nil means tokens are
+// saved in the token store file.
+var tokenKeyring keyring
+
+// keyringTimeout bounds a keyring tool, which may otherwise wait forever on
+// an unlock prompt nobody sees.
+const keyringTimeout = 10 * time.Second
+
+// runKeyringTool runs a credential helper with stdin as its input and returns
+// its output without the trailing newline.
+func runKeyringTool(stdin string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyringTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%s: no response within %s (is the keyring locked?)", name, keyringTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", name, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
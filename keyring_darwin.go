@@ -0,0 +1,43 @@
+//go:build darwin
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func init() { features = append(features, "keyring-macos") }
+
+// macKeychain keeps tokens in the login keychain with the security tool.
+type macKeychain struct{}
+
+func newKeyring() keyring {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil
+	}
+	return macKeychain{}
+}
+
+func (macKeychain) name() string { return "macOS Keychain" }
+
+func (macKeychain) get(host string) (string, error) {
+	return runKeyringTool("", "security", "find-generic-password", "-s", keyringService, "-a", host, "-w")
+}
+
+// set passes the secret on security's interactive stdin rather than as an
+// argument, where other users' ps could see it.
+func (macKeychain) set(host, secret string) error {
+	if strings.ContainsAny(secret, "\"\\\n") || strings.ContainsAny(host, "\"\\\n") {
+		return errors.New("security: unsupported characters in the token or host")
+	}
+	_, err := runKeyringTool(fmt.Sprintf("add-generic-password -U -s %q -a %q -l %q -w %q\n", keyringService, host, keyringService+" ("+host+")", secret), "security", "-i")
+	return err
+}
+
+func (macKeychain) delete(host string) error {
+	_, err := runKeyringTool("", "security", "delete-generic-password", "-s", keyringService, "-a", host)
+	return err
+}
@@ -0,0 +1,42 @@
+//go:build linux || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func init() { features = append(features, "keyring-secret-service") }
+
+// secretService keeps tokens in the desktop's Secret Service (GNOME Keyring,
+// KWallet, KeePassXC) with libsecret's secret-tool.
+type secretService struct{}
+
+func newKeyring() keyring {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil
+	}
+	return secretService{}
+}
+
+func (secretService) name() string { return "Secret Service" }
+
+func (secretService) get(host string) (string, error) {
+	secret, err := runKeyringTool("", "secret-tool", "lookup", "service", keyringService, "host", host)
+	if err == nil && secret == "" {
+		err = errors.New("secret-tool: no token for " + host)
+	}
+	return secret, err
+}
+
+// set passes the secret on stdin, where other users' ps can't see it.
+func (secretService) set(host, secret string) error {
+	_, err := runKeyringTool(secret, "secret-tool", "store", "--label", keyringService+" ("+host+")", "service", keyringService, "host", host)
+	return err
+}
+
+func (secretService) delete(host string) error {
+	_, err := runKeyringTool("", "secret-tool", "clear", "service", keyringService, "host", host)
+	return err
+}
@@ -0,0 +1,102 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func init() { features = append(features, "keyring-windows") }
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+)
+
+// credentialW is the Win32 CREDENTIALW structure.
+type credentialW struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialManager keeps tokens as generic credentials in Windows
+// Credential Manager, named "github-activity:<host>".
+type credentialManager struct{}
+
+func newKeyring() keyring { return credentialManager{} }
+
+func (credentialManager) name() string { return "Windows Credential Manager" }
+
+func credTarget(host string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(keyringService + ":" + host)
+}
+
+func (credentialManager) get(host string) (string, error) {
+	target, err := credTarget(host)
+	if err != nil {
+		return "", err
+	}
+	var cred *credentialW
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func (credentialManager) set(host, secret string) error {
+	target, err := credTarget(host)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(host)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credentialW{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return err
+	}
+	return nil
+}
+
+func (credentialManager) delete(host string) error {
+	target, err := credTarget(host)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oauthClientID identifies this tool's OAuth app for `auth login`. Release
+// builds set it with -ldflags "-X main.oauthClientID=Iv1.0123456789abcdef";
+// --client-id and $GITHUB_ACTIVITY_CLIENT_ID override it, e.g. for an
+// Enterprise Server, which needs its own app.
+var oauthClientID = ""
+
+// tokenStorePath is the file `auth login` saves tokens in, set by main so
+// tests never read a real one. Empty means no saved tokens.
+var tokenStorePath string
+
+// savedToken is a login from `auth login`, kept in the token store by host.
+// The token itself is in the OS keyring when Keyring is set, e.g.
+//
+//	{"github.com": {"keyring": true, "scopes": "read:org", "created": "2024-05-04T09:00:00Z"}}
+//
+// and in the file only when no keyring was available.
+type savedToken struct {
+	Token   string    `json:"token,omitempty"`
+	Keyring bool      `json:"keyring,omitempty"`
+	Scopes  string    `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+// tokensPath is where saved tokens are kept: $GITHUB_ACTIVITY_TOKENS, or
+// hosts.json next to the config file.
+func tokensPath() string {
+	if p := os.Getenv("GITHUB_ACTIVITY_TOKENS"); p != "" {
+		return p
+	}
+	if c := configPath(); c != "" {
+		return filepath.Join(filepath.Dir(c), "hosts.json")
+	}
+	return ""
+}
+
+// tokenHost is the key a host's token is saved under: "github.com" for
+// github.com and its API, else the Enterprise Server's host.
+func tokenHost(host string) string {
+	if isDotCom(host) {
+		return "github.com"
+	}
+	return strings.ToLower(host)
+}
+
+// loadTokens reads the token store; a missing file has no tokens.
+func loadTokens(path string) (map[string]savedToken, error) {
+	tokens := map[string]savedToken{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+// saveTokens writes the token store readable by the owner only.
+func saveTokens(path string, tokens map[string]savedToken) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file that already exists.
+	return os.Chmod(path, 0o600)
+}
+
+// keyringSecrets caches tokens read from the keyring by host, so its helper
+// runs once per process rather than once per request.
+var keyringSecrets sync.Map
+
+// storedCredential is the token `auth login` saved for host, if any.
+func storedCredential(host string) credential {
+	if tokenStorePath == "" {
+		return credential{}
+	}
+	tokens, err := loadTokens(tokenStorePath)
+	if err != nil {
+		debugf("saved tokens: %v", err)
+		return credential{}
+	}
+	t, ok := tokens[tokenHost(host)]
+	switch {
+	case !ok:
+	case !t.Keyring:
+		return credential{Token: t.Token, Source: "auth login"}
+	case tokenKeyring == nil:
+		debugf("the token for %s is in the keyring, which is unavailable or disabled", tokenHost(host))
+	default:
+		source := "auth login (" + tokenKeyring.name() + ")"
+		if secret, ok := keyringSecrets.Load(tokenHost(host)); ok {
+			return credential{Token: secret.(string), Source: source}
+		}
+		secret, err := tokenKeyring.get(tokenHost(host))
+		if err != nil {
+			debugf("reading the token for %s: %v", tokenHost(host), err)
+			return credential{}
+		}
+		keyringSecrets.Store(tokenHost(host), secret)
+		return credential{Token: secret, Source: source}
+	}
+	return credential{}
+}
+
+// storeToken saves a login for host: its token in kr if there is one that
+// works, else in the token store file at path. It returns where the token
+// went, for telling the user.
+func storeToken(path string, kr keyring, host string, t savedToken) (string, error) {
+	tokens, err := loadTokens(path)
+	if err != nil {
+		return "", err
+	}
+	where := path
+	if kr != nil {
+		if err := kr.set(host, t.Token); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: couldn't save the token in the %s (%v); saving it in %s instead\n", kr.name(), err, path)
+		} else {
+			t.Token, t.Keyring, where = "", true, "the "+kr.name()
+		}
+	}
+	tokens[host] = t
+	return where, saveTokens(path, tokens)
+}
+
+// forgetToken removes host's login from the token store at path and kr.
+func forgetToken(path string, kr keyring, host string) error {
+	tokens, err := loadTokens(path)
+	if err != nil {
+		return err
+	}
+	t, ok := tokens[host]
+	if !ok {
+		return fmt.Errorf("not logged in to %s", host)
+	}
+	if t.Keyring {
+		if kr == nil {
+			return fmt.Errorf("the token for %s is in the keyring, which is unavailable or disabled", host)
+		}
+		if err := kr.delete(host); err != nil {
+			return err
+		}
+	}
+	delete(tokens, host)
+	return saveTokens(path, tokens)
+}
+
+// deviceCode is GitHub's answer to the first step of the device flow.
+type deviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// tokenResponse is a poll of the access token endpoint: a token, or an
+// error such as "authorization_pending".
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Scope       string `json:"scope"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+	Interval    int    `json:"interval"`
+}
+
+// postForm posts form to endpoint on the web host and decodes the JSON
+// reply into v.
+func postForm(ctx context.Context, endpoint string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webURL+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// defaultPollInterval is how long to wait between device flow polls when
+// GitHub doesn't say, and how much to add on slow_down (RFC 8628, 3.5).
+const defaultPollInterval = 5 * time.Second
+
+// pollAfter waits between device flow polls; tests replace it.
+var pollAfter = time.After
+
+// deviceLogin runs GitHub's OAuth device flow: it shows the user a code to
+// enter at the verification page, then polls until they approve (or deny)
+// the request, and returns the token.
+func deviceLogin(ctx context.Context, w io.Writer, clientID, scopes string) (tokenResponse, error) {
+	var code deviceCode
+	err := postForm(ctx, "/login/device/code", url.Values{"client_id": {clientID}, "scope": {scopes}}, &code)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("requesting a device code: %w", err)
+	}
+	if code.DeviceCode == "" {
+		return tokenResponse{}, errors.New("requesting a device code: no code in the response (is the OAuth app's device flow enabled?)")
+	}
+	fmt.Fprintf(w, "First copy your one-time code: %s\n", code.UserCode)
+	fmt.Fprintf(w, "Then open %s in your browser and enter it.\n", code.VerificationURI)
+	if err := openBrowser(code.VerificationURI); err != nil {
+		debugf("opening the browser: %v", err)
+	}
+	fmt.Fprintln(w, "Waiting for authorization...")
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	deadline := now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	form := url.Values{
+		"client_id":   {clientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return tokenResponse{}, ctx.Err()
+		case <-pollAfter(interval):
+		}
+		var tok tokenResponse
+		if err := postForm(ctx, "/login/oauth/access_token", form, &tok); err != nil {
+			return tokenResponse{}, err
+		}
+		switch tok.Error {
+		case "":
+			return tok, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval = max(interval+defaultPollInterval, time.Duration(tok.Interval)*time.Second)
+		case "expired_token":
+			return tokenResponse{}, errors.New("the code expired before it was entered; run auth login again")
+		case "access_denied":
+			return tokenResponse{}, errors.New("authorization was denied")
+		default:
+			return tokenResponse{}, fmt.Errorf("%s: %s", tok.Error, tok.Description)
+		}
+		if code.ExpiresIn > 0 && now().After(deadline) {
+			return tokenResponse{}, errors.New("the code expired before it was entered; run auth login again")
+		}
+	}
+}
+
+func runAuth(args []string) int {
+	fs := flag.NewFlagSet("auth", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s auth login [options]\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s auth logout [options]\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s auth status [options]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Signs in to GitHub in the browser and saves the token, which every command then")
+		fmt.Fprintln(fs.Output(), "uses when $GH_TOKEN/$GITHUB_TOKEN are unset; logout forgets it. status shows which")
+		fmt.Fprintln(fs.Output(), "token is in use, its scopes, and the rate limit left. See auth login -h.")
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	switch fs.Arg(0) {
+	case "login":
+		return runAuthLogin(fs.Args()[1:])
+	case "logout":
+		return runAuthLogout(fs.Args()[1:])
+	case "status":
+		return runAuthStatus(fs.Args()[1:])
+	}
+	fs.Usage()
+	return 2
+}
+
+func runAuthLogin(args []string) int {
+	fs := flag.NewFlagSet("auth login", flag.ExitOnError)
+	clientID := fs.String("client-id", "", "OAuth app client ID (default: $GITHUB_ACTIVITY_CLIENT_ID, or the one built in).")
+	scopes := fs.String("scopes", "", "OAuth scopes to request, space- or comma-separated (default: none, which is enough for public activity).")
+	noKeyring := fs.Bool("no-keyring", false, "Save the token in the token store file even if an OS keyring is available.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s auth login [options]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Signs in with GitHub's device flow: enter the code shown at the page that opens.")
+		fmt.Fprintln(fs.Output(), "The token is saved in the OS keyring (macOS Keychain, Windows Credential Manager,")
+		fmt.Fprintln(fs.Output(), "or the Secret Service via secret-tool) and recorded in hosts.json next to the")
+		fmt.Fprintln(fs.Output(), "config file, or $GITHUB_ACTIVITY_TOKENS; without a keyring the token goes in that")
+		fmt.Fprintln(fs.Output(), "file, readable only by you.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	id := cmp.Or(*clientID, os.Getenv("GITHUB_ACTIVITY_CLIENT_ID"), oauthClientID)
+	if id == "" {
+		fmt.Fprintln(os.Stderr, "Error: this build has no OAuth client ID; pass --client-id or set $GITHUB_ACTIVITY_CLIENT_ID")
+		return 2
+	}
+	if tokenStorePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: no config directory to save the token in; set $GITHUB_ACTIVITY_TOKENS")
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	tok, err := deviceLogin(ctx, os.Stderr, id, strings.ReplaceAll(*scopes, ",", " "))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	kr := tokenKeyring
+	if *noKeyring {
+		kr = nil
+	}
+	u, _ := url.Parse(apiURL)
+	host := tokenHost(u.Host)
+	where, err := storeToken(tokenStorePath, kr, host, savedToken{Token: tok.AccessToken, Scopes: tok.Scope, Created: now().UTC()})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s; the token is saved in %s.\n", host, where)
+	if c := hostCredential(ctx, u.Host); !strings.HasPrefix(c.Source, "auth login") {
+		fmt.Fprintf(os.Stderr, "Note: %s is set and takes precedence over the saved token.\n", c.Source)
+	}
+	return 0
+}
+
+func runAuthLogout(args []string) int {
+	fs := flag.NewFlagSet("auth logout", flag.ExitOnError)
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	u, _ := url.Parse(apiURL)
+	host := tokenHost(u.Host)
+	if err := forgetToken(tokenStorePath, tokenKeyring, host); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Logged out of %s.\n", host)
+	return 0
+}
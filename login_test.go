@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePollAfter makes device flow polls immediate, recording the waits.
+func fakePollAfter(t *testing.T) *[]time.Duration {
+	var waits []time.Duration
+	restore := pollAfter
+	pollAfter = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		c := make(chan time.Time, 1)
+		c <- time.Time{}
+		return c
+	}
+	t.Cleanup(func() { pollAfter = restore })
+	return &waits
+}
+
+func TestDeviceLogin(t *testing.T) {
+	waits := fakePollAfter(t)
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "client-1" || r.Header.Get("Accept") != "application/json" {
+			t.Errorf("%s: form %v, Accept %q", r.URL.Path, r.Form, r.Header.Get("Accept"))
+		}
+		switch r.URL.Path {
+		case "/login/device/code":
+			if r.Form.Get("scope") != "read:org" {
+				t.Errorf("scope: %q", r.Form.Get("scope"))
+			}
+			json.NewEncoder(w).Encode(deviceCode{DeviceCode: "dev-1", UserCode: "ABCD-1234", VerificationURI: "https://github.com/login/device"})
+		case "/login/oauth/access_token":
+			if r.Form.Get("device_code") != "dev-1" {
+				t.Errorf("device_code: %q", r.Form.Get("device_code"))
+			}
+			polls++
+			switch polls {
+			case 1:
+				json.NewEncoder(w).Encode(tokenResponse{Error: "authorization_pending"})
+				return
+			case 2:
+				json.NewEncoder(w).Encode(tokenResponse{Error: "slow_down"})
+				return
+			}
+			json.NewEncoder(w).Encode(tokenResponse{AccessToken: "gho_secret", Scope: "read:org"})
+		}
+	}))
+	defer srv.Close()
+	restoreWeb, restoreOpen := webURL, openBrowser
+	webURL = srv.URL
+	var opened string
+	openBrowser = func(url string) error { opened = url; return nil }
+	defer func() { webURL, openBrowser = restoreWeb, restoreOpen }()
+
+	var out strings.Builder
+	tok, err := deviceLogin(context.Background(), &out, "client-1", "read:org")
+	if err != nil || tok.AccessToken != "gho_secret" || polls != 3 {
+		t.Fatalf("got %+v, %v after %d polls", tok, err, polls)
+	}
+	if !strings.Contains(out.String(), "ABCD-1234") || opened != "https://github.com/login/device" {
+		t.Errorf("output %q, opened %q", out.String(), opened)
+	}
+	// No interval in the response means 5s; slow_down without one adds 5s.
+	if want := []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second}; !slices.Equal(*waits, want) {
+		t.Errorf("waits %v, want %v", *waits, want)
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	fakePollAfter(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login/device/code" {
+			json.NewEncoder(w).Encode(deviceCode{DeviceCode: "dev-1"})
+			return
+		}
+		json.NewEncoder(w).Encode(tokenResponse{Error: "access_denied"})
+	}))
+	defer srv.Close()
+	restoreWeb, restoreOpen := webURL, openBrowser
+	webURL = srv.URL
+	openBrowser = func(string) error { return nil }
+	defer func() { webURL, openBrowser = restoreWeb, restoreOpen }()
+
+	if _, err := deviceLogin(context.Background(), io.Discard, "client-1", ""); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestStoredCredential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "github-activity", "hosts.json")
+	restore := tokenStorePath
+	tokenStorePath = path
+	defer func() { tokenStorePath = restore }()
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	if got := hostCredential(context.Background(), "api.github.com"); got.Token != "" {
+		t.Errorf("no store: got %+v", got)
+	}
+	err := saveTokens(path, map[string]savedToken{
+		"github.com":         {Token: "gho_dotcom"},
+		"github.example.com": {Token: "gho_ghe"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+			t.Errorf("mode: %v, %v", fi.Mode(), err)
+		}
+	}
+	tests := []struct {
+		host string
+		want credential
+	}{
+		{"api.github.com", credential{"gho_dotcom", "auth login"}},
+		{"GitHub.Example.com", credential{"gho_ghe", "auth login"}},
+		{"other.example.com", credential{}},
+	}
+	for _, tt := range tests {
+		if got := hostCredential(context.Background(), tt.host); got != tt.want {
+			t.Errorf("%s: got %+v", tt.host, got)
+		}
+	}
+	t.Setenv("GITHUB_TOKEN", "env-wins")
+	if got := hostCredential(context.Background(), "api.github.com"); got.Source != "$GITHUB_TOKEN" {
+		t.Errorf("environment first: got %+v", got)
+	}
+}
+
+// fakeKeyring is an in-memory keyring; set fails when broken.
+type fakeKeyring struct {
+	secrets map[string]string
+	broken  bool
+}
+
+func (k *fakeKeyring) name() string { return "test keyring" }
+
+func (k *fakeKeyring) get(host string) (string, error) {
+	if s, ok := k.secrets[host]; ok {
+		return s, nil
+	}
+	return "", errors.New("not found")
+}
+
+func (k *fakeKeyring) set(host, secret string) error {
+	if k.broken {
+		return errors.New("locked")
+	}
+	k.secrets[host] = secret
+	return nil
+}
+
+func (k *fakeKeyring) delete(host string) error {
+	delete(k.secrets, host)
+	return nil
+}
+
+func TestTokenInKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.json")
+	kr := &fakeKeyring{secrets: map[string]string{}}
+	restorePath, restoreKeyring := tokenStorePath, tokenKeyring
+	tokenStorePath, tokenKeyring = path, kr
+	defer func() { tokenStorePath, tokenKeyring = restorePath, restoreKeyring }()
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	where, err := storeToken(path, kr, "ghe.test", savedToken{Token: "gho_secret"})
+	if err != nil || where != "the test keyring" {
+		t.Fatalf("storeToken: %q, %v", where, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "gho_secret") || !strings.Contains(string(data), `"keyring": true`) {
+		t.Errorf("token store:\n%s", data)
+	}
+	if got := hostCredential(context.Background(), "ghe.test"); got != (credential{"gho_secret", "auth login (test keyring)"}) {
+		t.Errorf("got %+v", got)
+	}
+
+	if err := forgetToken(path, kr, "ghe.test"); err != nil || len(kr.secrets) != 0 {
+		t.Errorf("forgetToken: %v, %v", err, kr.secrets)
+	}
+	if err := forgetToken(path, kr, "ghe.test"); err == nil {
+		t.Error("forgetToken twice: no error")
+	}
+}
+
+func TestTokenKeyringFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.json")
+	where, err := storeToken(path, &fakeKeyring{broken: true}, "github.com", savedToken{Token: "gho_secret"})
+	if err != nil || where != path {
+		t.Fatalf("storeToken: %q, %v", where, err)
+	}
+	tokens, err := loadTokens(path)
+	if err != nil || tokens["github.com"].Token != "gho_secret" || tokens["github.com"].Keyring {
+		t.Errorf("got %+v, %v", tokens, err)
+	}
+}
@@ -1,28 +1,55 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata" // --tz works on systems without a zoneinfo database (Windows)
 )
 
-var eventsURL = "https://api.github.com/users/%s/events"
+// apiURL is the base of the GitHub REST API. Tests point it at a fake server.
+var apiURL = "https://api.github.com"
+
+// webURL is the base for links to repositories, issues, and pull requests.
+var webURL = "https://github.com"
 
 const userAgent = "github-activity-cli/1.0"
 
+// apiVersion is the REST API version every request pins with
+// X-GitHub-Api-Version, so responses keep their shape as GitHub evolves the
+// API. --api-version overrides it.
+var apiVersion = defaultAPIVersion
+
+const defaultAPIVersion = "2022-11-28"
+
+// now is the clock used for "generated at" stamps; tests pin it.
+var now = time.Now
+
 type Event struct {
+	ID        string    `json:"id"`
 	Type      string    `json:"type"`
 	CreatedAt time.Time `json:"created_at"`
-	Repo      struct {
+	Actor     struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"actor"`
+	Repo struct {
 		Name string `json:"name"`
 	} `json:"repo"`
+	Public *bool `json:"public,omitempty"` // nil when unknown, e.g. from a source plugin
 	// payload is dynamic per event type; we only decode fields we need
 	Payload json.RawMessage `json:"payload"`
 }
@@ -57,25 +84,456 @@ type ForkPayload struct {
 	} `json:"forkee"`
 }
 
+type DeploymentPayload struct {
+	Deployment struct {
+		Ref         string `json:"ref"`
+		Environment string `json:"environment"`
+	} `json:"deployment"`
+}
+
+type DeploymentStatusPayload struct {
+	DeploymentStatus struct {
+		State       string `json:"state"`
+		Environment string `json:"environment"`
+	} `json:"deployment_status"`
+	Deployment struct {
+		Environment string `json:"environment"`
+	} `json:"deployment"`
+}
+
+// payloadFields is a superset of the payload fields shared across event
+// types. Decoding into it gives renderers the action, number, and title of an
+// event without switching on every type.
+type payloadFields struct {
+	Action  string `json:"action"`
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
+	Head    string `json:"head"`
+	Issue   *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	} `json:"issue"`
+	PullRequest *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Base   struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Release *struct {
+		Name    string `json:"name"`
+		TagName string `json:"tag_name"`
+	} `json:"release"`
+}
+
+// eventDetails is the normalized action/number/title of an event. Fields the
+// event type doesn't carry are left empty.
+type eventDetails struct {
+	Action string
+	Number int
+	Title  string
+}
+
+// private reports whether ev is a private event, which GitHub only returns
+// to the user it belongs to.
+func (ev Event) private() bool { return ev.Public != nil && !*ev.Public }
+
+func detailsOf(ev Event) eventDetails {
+	var p payloadFields
+	if len(ev.Payload) == 0 || json.Unmarshal(ev.Payload, &p) != nil {
+		return eventDetails{}
+	}
+	d := eventDetails{Action: strings.ToLower(p.Action)}
+	switch {
+	case p.PullRequest != nil:
+		d.Number, d.Title = p.PullRequest.Number, p.PullRequest.Title
+	case p.Issue != nil:
+		d.Number, d.Title = p.Issue.Number, p.Issue.Title
+	case p.Release != nil:
+		d.Title = p.Release.Name
+		if d.Title == "" {
+			d.Title = p.Release.TagName
+		}
+	case p.RefType != "":
+		// Create/DeleteEvent: report what kind of ref and its name.
+		d.Action, d.Title = p.RefType, p.Ref
+	}
+	return d
+}
+
+// branchOf returns the branch a PushEvent pushed to or a PullRequestEvent
+// targets. ok is false for other event types.
+func branchOf(ev Event) (branch string, ok bool) {
+	if ev.Type != "PushEvent" && ev.Type != "PullRequestEvent" {
+		return "", false
+	}
+	var p payloadFields
+	if json.Unmarshal(ev.Payload, &p) != nil {
+		return "", false
+	}
+	if ev.Type == "PushEvent" {
+		branch, ok = strings.CutPrefix(p.Ref, "refs/heads/")
+		return branch, ok // tag pushes don't count
+	}
+	if p.PullRequest == nil || p.PullRequest.Base.Ref == "" {
+		return "", false
+	}
+	return p.PullRequest.Base.Ref, true
+}
+
+func repoURL(repo string) string {
+	return webURL + "/" + repo
+}
+
+// entityURL links to the most specific thing an event is about: the issue or
+// pull request when there is one, otherwise the repository.
+func entityURL(ev Event) string {
+	d := detailsOf(ev)
+	if d.Number != 0 {
+		kind := "issues"
+		if ev.Type == "PullRequestEvent" || ev.Type == "PullRequestReviewCommentEvent" {
+			kind = "pull"
+		}
+		return fmt.Sprintf("%s/%s/%d", repoURL(ev.Repo.Name), kind, d.Number)
+	}
+	return repoURL(ev.Repo.Name)
+}
+
+// command is a subcommand such as "tags". Anything that isn't a command name
+// is treated as a username for the default activity listing.
+type command struct {
+	name    string
+	args    string // argument synopsis for usage lines
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{name: "annotate", args: "<event-id> <text>", summary: "Keep a personal note on an event, shown with it in later listings.", run: runAnnotate},
+	{name: "auth", args: "login | logout | status", summary: "Sign in to GitHub in the browser, or check which token is used and its rate limit.", run: runAuth},
+	{name: "commits", args: "<github-username>", summary: "List commits from a user's recent pushes, optionally filtered with --grep.", run: runCommits},
+	{name: "daemon", args: "install | uninstall | status", summary: "Run watch in the background as a systemd unit, launchd agent, or Windows scheduled task.", run: runDaemon},
+	{name: "export", args: "--timesheet <github-username>", summary: "Export estimated time per day and repository as a timesheet CSV.", run: runExport},
+	{name: "goals", args: "<github-username>", summary: "Report progress toward activity goals (e.g., 5 PR reviews a week).", run: runGoals},
+	{name: "mentor", args: "<github-username>", summary: "Print an encouraging progress report of a mentee's firsts and growing range.", run: runMentor},
+	{name: "owners-feed", summary: "Show repository activity touching a CODEOWNERS team's files.", run: runOwnersFeed},
+	{name: "pin", args: "--user <login> <event-id>", summary: "Bookmark an event for later follow-up.", run: runPin},
+	{name: "pins", args: "list | remove <event-id>", summary: "List or remove pinned events.", run: runPins},
+	{name: "prompt-data", args: "<github-username>", summary: "Print cached activity as JSON for shell prompts, without waiting on the network.", run: runPromptData},
+	{name: "release-notes", args: "<owner>/<repo>", summary: "Draft categorized Markdown release notes from pull requests merged since the last tag.", run: runReleaseNotes},
+	{name: "repo-health", args: "<owner>/<repo>", summary: "Report a repository's responsiveness, contributor trend, and bus-factor hints.", run: runRepoHealth},
+	{name: "screen", args: "<github-username>", summary: "Write a recruiter-friendly Markdown or PDF summary of a user's public work.", run: runScreen},
+	{name: "serve", summary: "Serve activity over HTTP (Slack slash commands).", run: runServe},
+	{name: "standup", args: "<github-username>", summary: "Print yesterday's and today's activity, ready to paste into a standup thread.", run: runStandup},
+	{name: "stats", args: "<github-username> | <owner>/<repo>", summary: "Summarize recent activity (event counts, starred owners, stargazers).", run: runStats},
+	{name: "status", args: "<github-username>", summary: "Print a one-line activity summary for status bars and prompts.", run: runStatus},
+	{name: "tags", args: "<owner>/<repo>", summary: "Summarize recent tag and release activity in a repository.", run: runTags},
+	{name: "update", summary: "Replace this binary with the latest verified release.", run: runUpdate},
+	{name: "watch", args: "<github-username>...", summary: "Poll users' activity and alert on new events (terminal, chats, webhooks).", run: runWatch},
+	{name: "version", summary: "Print version and build information.", run: runVersion},
+}
+
+func lookupCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// describeFlags, when set, receives each command's FlagSet in place of
+// parsing it, so docs can read flag definitions without running anything.
+var describeFlags func(fs *flag.FlagSet)
+
+// parseFlags adds the connection flags to a command, applies the config
+// file's defaults, parses its arguments, and configures the connection.
+// It reports whether the command should go on to run.
+func parseFlags(fs *flag.FlagSet, args []string) bool {
+	addConnectionFlags(fs)
+	if describeFlags != nil {
+		describeFlags(fs)
+		return false
+	}
+	if err := applyConfigFile(fs); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: config:", err)
+		os.Exit(2)
+	}
+	_ = fs.Parse(args)
+	if err := applyConnectionFlags(fs); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+	return true
+}
+
 func main() {
-	eventType := flag.String("type", "", "Filter by event type (e.g., PushEvent, IssuesEvent). Leave blank for all.")
-	limit := flag.Int("n", 30, "Max number of events to show (1-100).")
-	flag.Usage = func() {
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] <github-username>\n\n", os.Args[0])
-		fmt.Fprintln(flag.CommandLine.Output(), "Options:")
-		flag.PrintDefaults()
-		fmt.Fprintln(flag.CommandLine.Output(), `
+	responseCache = defaultETagCache()
+	metaCache = defaultMetadataCache()
+	retries = defaultRetryPolicy
+	requestTimeout = defaultRequestTimeout
+	tokenStorePath = tokensPath()
+	netrcPath = defaultNetrcPath()
+	if os.Getenv("GITHUB_ACTIVITY_NO_KEYRING") == "" {
+		tokenKeyring = newKeyring()
+	}
+	app, err := appFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+	githubApp = app
+	if path := os.Getenv("GITHUB_TOKEN_FILE"); path != "" {
+		if fileCredential, err = readTokenFile(path, os.Stdin); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: $GITHUB_TOKEN_FILE:", err)
+			os.Exit(2)
+		}
+		fileCredential.Source = "$GITHUB_TOKEN_FILE (" + fileCredential.Source + ")"
+	}
+	if poolConfig, err = loadPoolConfig(configPath()); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: config:", err)
+		os.Exit(2)
+	}
+	if os.Getenv("GITHUB_ACTIVITY_DEBUG") != "" {
+		debugLog = os.Stderr
+	}
+	if u := os.Getenv("GITHUB_API_URL"); u != "" {
+		if err := setAPIURL(u); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: $GITHUB_API_URL:", err)
+			os.Exit(2)
+		}
+	}
+	if len(os.Args) > 1 {
+		if cmd := lookupCommand(os.Args[1]); cmd != nil {
+			os.Exit(cmd.run(os.Args[2:]))
+		}
+	}
+	os.Exit(runActivity(os.Args[1:]))
+}
+
+// runActivity is the default command: list recent activity for each user.
+func runActivity(args []string) int {
+	fs := flag.NewFlagSet(programName, flag.ExitOnError)
+	var typeNames stringList
+	fs.Var(&typeNames, "type", "Only show these event types (repeatable or comma-separated): PushEvent, IssuesEvent, ... or aliases like push, pr, issue, star.")
+	var excludeTypeNames stringList
+	fs.Var(&excludeTypeNames, "exclude-type", "Hide these event types (repeatable or comma-separated; aliases as for --type).")
+	var actors, excludeActors stringList
+	fs.Var(&actors, "actor", "Only show events performed by this login (repeatable or comma-separated).")
+	fs.Var(&excludeActors, "exclude-actor", "Hide events performed by this login (repeatable or comma-separated).")
+	sinceID := fs.String("since-id", "", "Only show events newer than the event with this ID (the id field of JSON, NDJSON, and CSV output), for incremental polling.")
+	botsRollup := fs.Bool("bots-rollup", false, "Instead of hiding bot activity, show one summary line per bot and repository, e.g. \"dependabot in acme/app: 7 PRs opened, 5 merged\".")
+	includePrivate := fs.Bool("include-private", false, "Include private events, marked (private). GitHub only returns them when you're authenticated as the user; without this flag they're left out.")
+	noBots := fs.Bool("no-bots", false, "Hide events by bot accounts (e.g. dependabot[bot]) and activity on issues and pull requests opened by bots.")
+	grep := fs.String("grep", "", "Only show events whose repository name, issue/PR/release title, or pushed commit messages match this regular expression (use (?i) to ignore case).")
+	filterSrc := fs.String("filter", "", `Filter expression, e.g. 'type==PushEvent && repo =~ "^acme/" && commits > 2'.`)
+	var repos stringList
+	fs.Var(&repos, "repo", "Only show events in repositories matching this glob, e.g. 'myorg/*' (repeatable or comma-separated).")
+	var owners stringList
+	fs.Var(&owners, "owner", "Only show events in repositories owned by this user or organization (repeatable or comma-separated).")
+	sinceArg := fs.String("since", "", "Only show events from this time on: a date (2024-05-01), an RFC 3339 timestamp, or a duration ago (72h). Fetches further back as needed.")
+	untilArg := fs.String("until", "", "Only show events before this time (same forms as --since).")
+	today := fs.Bool("today", false, "Only show events from today (in the --tz time zone). Shorthand for --since/--until.")
+	yesterday := fs.Bool("yesterday", false, "Only show events from yesterday.")
+	thisWeek := fs.Bool("this-week", false, "Only show events from this week, starting Monday.")
+	var paths stringList
+	fs.Var(&paths, "path", "Only show pushes and pull requests that changed files matching this glob, e.g. 'services/auth/**' (repeatable or comma-separated). Looks up each change's files; best combined with --repo.")
+	var filterCmds repeatedFlag
+	fs.Var(&filterCmds, "filter-exec", `Shell command that reads candidate events as NDJSON (GitHub's event format) and writes back the ones to keep, e.g. "jq -c 'select(.payload.size > 3)'" (repeatable; each filters the previous one's output).`)
+	onlyForks := fs.Bool("only-forks", false, "Only show events on repositories that are forks (looks up each repository once).")
+	noForks := fs.Bool("no-forks", false, "Hide events on repositories that are forks (looks up each repository once).")
+	branch := fs.String("branch", "", "Only show pushes to, and pull requests targeting, branches matching this glob (e.g., main, release/*).")
+	limit := fs.Int("n", 30, "Max number of events to show per user (1-100). Defaults to what fits the terminal height, or 30 when not on a terminal.")
+	perPage := fs.Int("per-page", 0, "Events to request per API call, 1-100 (default: GitHub's 30, or 100 when paging back for filters).")
+	maxPages := fs.Int("pages", 0, "Fetch this many pages of events (default: 1, or as many as the filters and -n need).")
+	allPages := fs.Bool("all", false, "Fetch every page the events API serves (at most 300 events, 90 days).")
+	failFast := fs.Bool("fail-fast", false, "Stop at the first user that fails instead of reporting failures at the end.")
+	merge := fs.Bool("merge", false, "Show all users' events as one timeline, newest first, instead of a section per user.")
+	format := fs.String("format", "text", "Output format: "+strings.Join(formats, ", ")+".")
+	jsonOut := fs.Bool("json", false, "Shorthand for --format=json.")
+	ndjsonOut := fs.Bool("ndjson", false, "Shorthand for --format=ndjson.")
+	delimiter := fs.String("delimiter", ",", `Field delimiter for --format=csv (a single character, or "tab").`)
+	tmplFile := fs.String("template-file", "", `Load per-event-type templates ({{define "PushEvent"}}...{{end}}, plus an optional "default") from a file.`)
+	tmpl := fs.String("template", "", "Format each event with a Go text/template, e.g. '{{.CreatedAt.Format \"Jan 02\"}} {{.Type}} {{.Repo}}'.")
+	fs.BoolVar(&lenient, "lenient", false, "Skip malformed events with a warning instead of failing the run.")
+	emoji := fs.Bool("emoji", false, "Prefix text lines with an emoji for the event type (ignored on non-UTF-8 terminals).")
+	reverse := fs.Bool("reverse", false, "List events oldest first.")
+	numbered := fs.Bool("numbered", false, "Number the events in text output, for use with --open.")
+	openN := fs.Int("open", 0, "Open the Nth event (as numbered by --numbered) in the browser.")
+	pinN := fs.Int("pin", 0, "Pin the Nth event (as numbered by --numbered) for later; see the pins command.")
+	ciStatus := fs.Bool("ci-status", false, "Note GitHub Actions workflows failing on the default branch of each repository shown, e.g. \"CI failing on main since yesterday\" (text and plaintext-digest formats). Looks up each repository's recent runs.")
+	checkBadges := fs.Bool("checks", false, "Mark push and pull request lines with the combined CI status of their head commit: ✓ passed, ✗ failed, • pending. Looks up each commit's statuses and check runs, two API calls per event.")
+	sizes := fs.Bool("sizes", false, "Tag pull request and push lines with a size label (XS, S, M, L, XL) by lines changed. Looks up each change's diffstat, one API call per event, and shows pushes separately.")
+	noCollapse := fs.Bool("no-collapse", false, "Show consecutive pushes to the same branch separately instead of as one line (human-readable formats only).")
+	maxWidth := fs.Int("max-width", 0, "Truncate text and table lines to this many columns. 0 uses the terminal width (no limit when not a terminal); -1 never truncates.")
+	groupBy := fs.String("group-by", "", "Group text output under headers with counts: "+strings.Join(groupByModes, ", ")+".")
+	colorMode := fs.String("color", "auto", "Colorize output: auto (only on a terminal, honoring NO_COLOR), always, or never.")
+	hyperlinkMode := fs.String("hyperlinks", "auto", "Make repositories and issue/PR numbers clickable (OSC 8): auto (terminals known to support it), always, or never.")
+	target := fs.String("target", "user", "What the arguments name: user, or org to show an organization's public activity, each event with its actor (\"alice: Pushed ...\").")
+	sourceSpec := fs.String("source", "github", "Where events come from: github, or exec:<command> to run a JSON-RPC source plugin.")
+	cacheTTL := fs.Duration("cache-ttl", 2*time.Minute, "Reuse API responses younger than this from the on-disk cache without asking GitHub. 0 always revalidates.")
+	metadataTTL := fs.Duration("metadata-ttl", defaultMetadataTTL, "Reuse looked-up repository metadata (fork status, default branch) younger than this from the on-disk cache. 0 always looks it up.")
+	noCache := fs.Bool("no-cache", false, "Don't read or write the on-disk response and metadata caches.")
+	refresh := fs.Bool("refresh", false, "Revalidate every cached response with GitHub, ignoring --cache-ttl, and look up metadata again.")
+	retryCount := fs.Int("retries", defaultRetryPolicy.Retries, "Retry requests that fail with a 5xx error or a secondary rate limit this many times, backing off exponentially.")
+	retryMaxWait := fs.Duration("retry-max-wait", defaultRetryPolicy.MaxWait, "Longest single wait before a retry; a Retry-After asking for longer fails instead.")
+	fs.StringVar(&apiVersion, "api-version", defaultAPIVersion, "REST API version to request (X-GitHub-Api-Version), as a date like 2022-11-28.")
+	tokenFile := fs.String("token-file", "", "Read the GitHub token from this file, e.g. a Docker or Kubernetes secret, or from stdin with -, keeping it out of process arguments and the environment (default: $GITHUB_TOKEN_FILE).")
+	debug := fs.Bool("debug", false, "Log each API request to stderr with its status, whether it was authenticated (and with which token source), and the rate limit left. $GITHUB_ACTIVITY_DEBUG does the same for every command.")
+	fs.DurationVar(&requestTimeout, "timeout", defaultRequestTimeout, "Give up on an API request that gets no complete response within this long (each retry gets its own). 0 means no limit.")
+	deadline := fs.Duration("deadline", 0, "Overall time limit for all API calls in this run (e.g., 2m). 0 means no limit.")
+	tz := fs.String("tz", "", "Show times in this IANA time zone (e.g., Europe/Berlin) instead of the local zone.")
+	utc := fs.Bool("utc", false, "Show times in UTC. Shorthand for --tz=UTC.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [options] <github-username>...\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s <command> [options] [args]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Commands:")
+		for _, c := range commands {
+			fmt.Fprintf(fs.Output(), "  %-12s %s\n", c.name, c.summary)
+		}
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+		fmt.Fprintln(fs.Output(), `
 Examples:
   github-activity torvalds
-  github-activity --type=PushEvent --n=10 kamranahmedse`)
+  github-activity --type=PushEvent --n=10 kamranahmedse
+  github-activity --fail-fast alice bob carol
+  github-activity --target=org golang
+  gh api orgs/acme/members --jq '.[].login' | github-activity --merge -
+  github-activity --json torvalds | jq '.[].repo'
+  github-activity --format=csv --delimiter=';' torvalds > activity.csv
+  github-activity tags golang/go`)
+	}
+	if !parseFlags(fs, args) {
+		return 0
 	}
-	flag.Parse()
 
-	if flag.NArg() != 1 {
-		flag.Usage()
-		os.Exit(2)
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+	if *tokenFile != "" {
+		if *tokenFile == "-" && slices.Contains(fs.Args(), "-") {
+			fmt.Fprintln(os.Stderr, "Error: --token-file - and a - username argument can't both read stdin")
+			return 2
+		}
+		cred, err := readTokenFile(*tokenFile, os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --token-file:", err)
+			return 2
+		}
+		cred.Source = "--token-file (" + cred.Source + ")"
+		fileCredential = cred
+	}
+	usernames, err := expandStdinUsers(fs.Args(), os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	var shorthand []string
+	if *jsonOut {
+		shorthand = append(shorthand, "json")
+	}
+	if *ndjsonOut {
+		shorthand = append(shorthand, "ndjson")
+	}
+	if *tmpl != "" || *tmplFile != "" {
+		shorthand = append(shorthand, "template")
+	}
+	if len(shorthand) > 1 || (len(shorthand) == 1 && *format != "text") {
+		fmt.Fprintln(os.Stderr, "Error: --json, --ndjson, --template/--template-file, and --format are mutually exclusive")
+		return 2
+	}
+	if len(shorthand) == 1 {
+		*format = shorthand[0]
+	}
+	types, err := resolveEventTypes(typeNames)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	excludeTypes, err := resolveEventTypes(excludeTypeNames)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	if strings.Trim(*sinceID, "0123456789") != "" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --since-id %q: event IDs are numbers\n", *sinceID)
+		return 2
+	}
+	var grepRE *regexp.Regexp
+	if *grep != "" {
+		if grepRE, err = regexp.Compile(*grep); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --grep: %v\n", err)
+			return 2
+		}
+	}
+	var expr exprNode
+	if *filterSrc != "" {
+		var err error
+		if expr, err = parseFilterExpr(*filterSrc); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+	}
+	if *groupBy != "" {
+		if !slices.Contains(groupByModes, *groupBy) {
+			fmt.Fprintf(os.Stderr, "Error: invalid --group-by %q (want %s)\n", *groupBy, strings.Join(groupByModes, ", "))
+			return 2
+		}
+		if *format != "text" {
+			fmt.Fprintln(os.Stderr, "Error: --group-by only applies to text output")
+			return 2
+		}
+	}
+	for _, r := range repos {
+		if _, err := path.Match(r, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --repo pattern %q: %v\n", r, err)
+			return 2
+		}
+	}
+	for _, p := range paths {
+		if _, err := path.Match(strings.ReplaceAll(p, "**", "*"), ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --path pattern %q: %v\n", p, err)
+			return 2
+		}
+	}
+	if *botsRollup && *noBots {
+		fmt.Fprintln(os.Stderr, "Error: --bots-rollup and --no-bots are mutually exclusive")
+		return 2
+	}
+	if *onlyForks && *noForks {
+		fmt.Fprintln(os.Stderr, "Error: --only-forks and --no-forks are mutually exclusive")
+		return 2
+	}
+	var checks []eventCheck
+	if *onlyForks || *noForks {
+		checks = append(checks, forkCheck(*onlyForks))
+	}
+	if len(paths) > 0 {
+		checks = append(checks, pathCheck(paths))
+	}
+	if _, err := path.Match(*branch, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --branch pattern %q: %v\n", *branch, err)
+		return 2
+	}
+	limitSet := false
+	fs.Visit(func(f *flag.Flag) { limitSet = limitSet || f.Name == "n" })
+	if *perPage < 0 || *perPage > 100 || *maxPages < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --per-page must be 1-100 and --pages positive")
+		return 2
+	}
+	if *allPages && *maxPages > 0 {
+		fmt.Fprintln(os.Stderr, "Error: --all and --pages are mutually exclusive")
+		return 2
+	}
+	if !limitSet && isTerminal(os.Stdout) {
+		sections := len(usernames)
+		if *merge {
+			sections = 1
+		}
+		*limit = fitLimit(terminalHeight(os.Stdout), sections, *format)
 	}
-	username := flag.Arg(0)
 	if *limit < 1 {
 		*limit = 1
 	}
@@ -83,84 +541,670 @@ Examples:
 		*limit = 100
 	}
 
-	events, err := fetchEvents(username)
+	if err := setTimezone(*tz, *utc); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	// Dates are local, so parse them once the time zone is set.
+	var shortcuts []string
+	for name, on := range map[string]bool{"today": *today, "yesterday": *yesterday, "this-week": *thisWeek} {
+		if on {
+			shortcuts = append(shortcuts, name)
+		}
+	}
+	if len(shortcuts) > 1 || (len(shortcuts) == 1 && (*sinceArg != "" || *untilArg != "")) {
+		fmt.Fprintln(os.Stderr, "Error: --today, --yesterday, --this-week, and --since/--until are mutually exclusive")
+		return 2
+	}
+	var since, until time.Time
+	if len(shortcuts) == 1 {
+		since, until = shortcutRange(shortcuts[0], now())
+	} else {
+		if since, err = parseTimeArg(*sinceArg); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --since:", err)
+			return 2
+		}
+		if until, err = parseTimeArg(*untilArg); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --until:", err)
+			return 2
+		}
+	}
+	if *target != "user" && *target != "org" {
+		fmt.Fprintf(os.Stderr, "Error: --target %q: want user or org\n", *target)
+		return 2
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		fmt.Fprintln(os.Stderr, "Error: --since must be before --until")
+		return 2
+	}
+	color, err := resolveColor(*colorMode, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	hyperlinks, err := resolveHyperlinks(*hyperlinkMode, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	if *debug {
+		debugLog = os.Stderr
+	}
+	if _, err := time.Parse("2006-01-02", apiVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --api-version %q: want a date like %s\n", apiVersion, defaultAPIVersion)
+		return 2
+	}
+	if *retryCount < 0 || *retryMaxWait < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --retries and --retry-max-wait can't be negative")
+		return 2
+	}
+	retries = retryPolicy{Retries: *retryCount, MaxWait: *retryMaxWait, Base: defaultRetryPolicy.Base}
+	switch {
+	case *noCache:
+		responseCache, metaCache = nil, nil
+	case *refresh:
+		if metaCache != nil {
+			metaCache.ttl = 0
+		}
+	default:
+		if responseCache != nil {
+			responseCache.ttl = *cacheTTL
+		}
+		if metaCache != nil {
+			metaCache.ttl = *metadataTTL
+		}
+	}
+
+	// Ctrl+C cancels requests in flight instead of killing the process
+	// mid-write.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *deadline)
+		defer cancel()
+	}
+
+	width := *maxWidth
+	if width == 0 {
+		width = terminalWidth(os.Stdout)
+	} else if width < 0 {
+		width = 0
+	}
+
+	filtered := len(excludeTypes) > 0 || len(actors) > 0 || len(excludeActors) > 0 ||
+		len(repos) > 0 || len(owners) > 0 || len(checks) > 0 || len(filterCmds) > 0 || *branch != "" || *sinceID != "" || *noBots ||
+		!since.IsZero() || !until.IsZero() || grepRE != nil || expr != nil
+	out, err := newRenderer(os.Stdout, outputOptions{
+		Format:       *format,
+		EventTypes:   types,
+		Filtered:     filtered,
+		Multi:        len(usernames) > 1 && !*merge,
+		Delimiter:    *delimiter,
+		Template:     *tmpl,
+		TemplateFile: *tmplFile,
+		Width:        width,
+		Color:        color,
+		Hyperlinks:   hyperlinks,
+		GroupBy:      *groupBy,
+		Numbered:     *numbered,
+		Emoji:        *emoji && (!isTerminal(os.Stdout) || utf8Terminal()),
+	})
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		return 2
 	}
-	if len(events) == 0 {
-		fmt.Println("No recent public activity.")
-		return
+	if _, ok := out.(ciAware); *ciStatus && !ok {
+		fmt.Fprintf(os.Stderr, "Error: --ci-status works with the text and plaintext-digest formats, not %s\n", *format)
+		return 2
 	}
 
-	count := 0
+	f := filters{
+		Types:         types,
+		ExcludeTypes:  excludeTypes,
+		Actors:        actors,
+		ExcludeActors: excludeActors,
+		Repos:         repos,
+		Owners:        owners,
+		Branch:        *branch,
+		Since:         since,
+		Until:         until,
+		SinceID:       *sinceID,
+		NoBots:        *noBots,
+		Private:       *includePrivate,
+		Grep:          grepRE,
+		Expr:          expr,
+	}
+	pages := pageOptions{PerPage: *perPage, Pages: max(*maxPages, 1), Since: since}
+	switch {
+	case *allPages:
+		pages.Pages = 0
+	case *maxPages > 0:
+	case filtered || len(types) > 0 || *limit > eventsPageSize:
+		// Page back until the filters have matched enough events.
+		pages.Pages = 0
+		if pages.PerPage == 0 {
+			pages.PerPage = 100
+		}
+		pages.Enough = func(events []Event) bool {
+			n := 0
+			for _, ev := range events {
+				if f.match(ev) {
+					n++
+				}
+			}
+			return n >= *limit
+		}
+	}
+	source, err := newDataSource(*sourceSpec, pages, *target == "org")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	defer source.Close()
+	if *includePrivate && *sourceSpec == "github" && *target == "user" {
+		warnPrivateAccess(ctx, usernames)
+	}
+
+	notes, err := loadNotes(notesPath())
+	if err != nil {
+		fmt.Fprintln(warnings, "Warning: notes:", err)
+	}
+	var failures []userError
+	var shown []entry // in display order, for --open and --pin
+	var merged []Event
+	for _, username := range usernames {
+		events, err := source.Events(ctx, username)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// Every remaining request would fail the same way.
+				fmt.Fprintf(os.Stderr, "Error: deadline of %s exceeded\n", *deadline)
+				return 1
+			}
+			if errors.Is(ctx.Err(), context.Canceled) {
+				fmt.Fprintln(os.Stderr, "Interrupted")
+				return 130
+			}
+			if len(usernames) == 1 {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				return 1
+			}
+			if *failFast {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", username, err)
+				return 1
+			}
+			failures = append(failures, userError{User: username, Err: err})
+			continue
+		}
+		candidates, err := selectCandidates(ctx, events, f, filterCmds, checks, *limit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		entries := selectEntries(username, candidates, f, *limit)
+		if *sizes {
+			entries = annotateSizes(ctx, entries)
+		}
+		if *botsRollup {
+			entries = rollupBots(entries)
+		}
+		entries = arrangeEntries(entries, !*noCollapse && !*sizes && collapsible(*format), *reverse)
+		if *checkBadges {
+			entries = annotateChecks(ctx, entries) // after collapsing, so a run of pushes shows the branch's latest state
+		}
+		attachNotes(entries, notes)
+		// Events older than --since mean the whole range was fetched.
+		covered := !since.IsZero() && len(events) > 0 && events[len(events)-1].CreatedAt.Before(since)
+		if note := windowNote(events, entries, *limit, (len(types) > 0 || filtered) && !covered); note != "" {
+			fmt.Fprintf(warnings, "Note: %s: %s\n", username, note)
+		}
+		if *merge {
+			shown = append(shown, entries...)
+			merged = append(merged, events...)
+			continue
+		}
+		shown = append(shown, displayOrder(entries, *groupBy)...)
+		if ci, ok := out.(ciAware); ok && *ciStatus {
+			ci.setCI(username, fetchCIStatuses(ctx, entries))
+		}
+		if err := out.feed(username, events, entries); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+	}
+	if *merge {
+		shown = mergeEntries(shown, *limit, *reverse, collapsible(*format))
+		if ci, ok := out.(ciAware); ok && *ciStatus {
+			ci.setCI(strings.Join(usernames, ", "), fetchCIStatuses(ctx, shown))
+		}
+		if err := out.feed(strings.Join(usernames, ", "), merged, shown); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		shown = displayOrder(shown, *groupBy)
+	}
+	if err := out.flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if *pinN != 0 {
+		if *pinN < 1 || *pinN > len(shown) {
+			fmt.Fprintf(os.Stderr, "Error: --pin %d: only %d event(s) shown\n", *pinN, len(shown))
+			return 1
+		}
+		if err := addPin(pinsPath(), shown[*pinN-1]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: pinning:", err)
+			return 1
+		}
+	}
+	if *openN != 0 {
+		if *openN < 1 || *openN > len(shown) {
+			fmt.Fprintf(os.Stderr, "Error: --open %d: only %d event(s) shown\n", *openN, len(shown))
+			return 1
+		}
+		if err := openBrowser(eventURL(shown[*openN-1].Event)); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: opening browser:", err)
+			return 1
+		}
+	}
+
+	if len(failures) > 0 {
+		printFailures(os.Stderr, failures, len(usernames))
+		return 1
+	}
+	return 0
+}
+
+// userError records why fetching a single user's events failed during a
+// multi-user run.
+type userError struct {
+	User string
+	Err  error
+}
+
+func printFailures(w io.Writer, failures []userError, total int) {
+	fmt.Fprintf(w, "\nErrors (%d of %d users failed):\n", len(failures), total)
+	for _, f := range failures {
+		fmt.Fprintf(w, "  %s: %v\n", f.User, f.Err)
+	}
+}
+
+// entry is an event that survived filtering, paired with its one-line summary.
+type entry struct {
+	User    string
+	Event   Event
+	Summary string
+	Note    string // the user's annotation, if any; see annotate
+}
+
+// selectEntries applies the filters and limit to a user's events, dropping
+// event types formatEvent doesn't know how to describe.
+func selectEntries(user string, events []Event, f filters, limit int) []entry {
+	var entries []entry
 	for _, ev := range events {
-		if *eventType != "" && ev.Type != *eventType {
+		if !f.match(ev) {
 			continue
 		}
 		line, ok := formatEvent(ev)
 		if !ok {
 			continue // skip unknown/boring events
 		}
-		fmt.Println("- " + line)
-		count++
-		if count >= *limit {
+		if ev.private() {
+			line += " (private)"
+		}
+		entries = append(entries, entry{User: user, Event: ev, Summary: line})
+		if len(entries) >= limit {
 			break
 		}
 	}
+	return entries
+}
+
+// fitLimit is the per-user event count that keeps output for users within a
+// terminal of the given height: one line per event, less the column headings
+// (table), per-user headers and blank separators (text), and a line for the
+// next shell prompt. Formats that aren't one line per event get the usual 30.
+func fitLimit(height, users int, format string) int {
+	if height <= 0 || users < 1 || (format != "text" && format != "table") {
+		return 30
+	}
+	avail := height - 1
+	if format == "table" {
+		avail--
+	} else if users > 1 {
+		avail -= 2*users - 1
+	}
+	return max(avail/users, 1)
+}
+
+// eventsPageSize is how many events one request to the events API returns.
+const eventsPageSize = 30
+
+// windowNote explains a short filtered result that may be due to the events
+// API's limited window rather than a lack of matching activity: when every
+// fetched event was scanned and a full page came back, older matches may
+// exist that the API won't serve. It returns "" when no note is needed.
+func windowNote(events []Event, entries []entry, limit int, filtered bool) string {
+	if !filtered || len(entries) >= limit || len(events) < eventsPageSize {
+		return ""
+	}
+	oldest := events[len(events)-1].CreatedAt.Local().Format("Jan 2, 2006")
+	return fmt.Sprintf("only the %d most recent events (back to %s) were searched; "+
+		"GitHub's events API covers at most the last 90 days and 300 events, so older matches are not shown",
+		len(events), oldest)
+}
+
+// arrangeEntries applies the display passes to selected entries: collapsing
+// runs of pushes, then reversing into oldest-first order. Reversing comes
+// after selection, so the newest events are still the ones shown.
+func arrangeEntries(entries []entry, collapse, reverse bool) []entry {
+	if collapse {
+		entries = collapsePushes(entries)
+	}
+	if reverse {
+		entries = slices.Clone(entries)
+		slices.Reverse(entries)
+	}
+	return entries
+}
+
+// mergeEntries turns several users' entries into one timeline of at most
+// limit entries, newest first (oldest first with reverse). For formats read
+// by people, summaries name the user so events can be told apart.
+func mergeEntries(entries []entry, limit int, reverse, label bool) []entry {
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return b.Event.CreatedAt.Compare(a.Event.CreatedAt)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if reverse {
+		slices.Reverse(entries)
+	}
+	if label {
+		for i, e := range entries {
+			if actorPrefix(e) == "" {
+				entries[i].Summary = e.User + ": " + e.Summary
+			}
+		}
+	}
+	return entries
+}
 
-	if count == 0 {
-		if *eventType != "" {
-			fmt.Printf("No events of type %q found.\n", *eventType)
+// collapsible reports whether a format is read by people, and so gets runs
+// of pushes collapsed; machine formats keep one record per event.
+func collapsible(format string) bool {
+	switch format {
+	case "text", "table", "markdown", "md", "html", "plaintext-digest":
+		return true
+	}
+	return false
+}
+
+// collapsePushes merges each run of consecutive pushes by the same actor to
+// the same repository and branch into one entry, e.g. "Pushed 14 commit(s)
+// to alice/repo (5 pushes)". The merged entry keeps the newest push's event.
+func collapsePushes(entries []entry) []entry {
+	var out []entry
+	runLen, commits := 0, 0
+	for i, e := range entries {
+		if i > 0 && samePushTarget(entries[i-1], e) {
+			runLen++
+			commits += pushSize(e.Event)
 		} else {
-			fmt.Println("No printable events found.")
+			runLen, commits = 1, pushSize(e.Event)
+			out = append(out, e)
+		}
+		if runLen > 1 {
+			out[len(out)-1].Summary = fmt.Sprintf("Pushed %d commit(s) to %s (%d pushes)", commits, e.Event.Repo.Name, runLen)
+		}
+	}
+	return out
+}
+
+func samePushTarget(a, b entry) bool {
+	if a.Event.Type != "PushEvent" || b.Event.Type != "PushEvent" {
+		return false
+	}
+	ba, _ := branchOf(a.Event)
+	bb, _ := branchOf(b.Event)
+	return a.Event.Actor.Login == b.Event.Actor.Login && a.Event.Repo.Name == b.Event.Repo.Name && ba == bb
+}
+
+func fetchEvents(ctx context.Context, username string) ([]Event, error) {
+	return fetchEventList(ctx, apiURL+"/users/"+url.PathEscape(username)+"/events", "user not found")
+}
+
+// pageOptions controls how far back fetchEventPages goes. The events API
+// serves at most 300 events, however they are paged.
+type pageOptions struct {
+	PerPage int                // events per request, 1-100; 0 for the API default of 30
+	Pages   int                // max requests; 0 follows pages until the window ends
+	Since   time.Time          // stop once events are older than this
+	Enough  func([]Event) bool // stop once this reports the events so far suffice
+}
+
+// done reports whether paging can stop after page pages that returned events.
+func (o pageOptions) done(page int, events []Event) bool {
+	switch {
+	case o.Pages > 0 && page >= o.Pages:
+		return true
+	case len(events) == 0:
+		return true
+	case !o.Since.IsZero() && events[len(events)-1].CreatedAt.Before(o.Since):
+		return true
+	case o.Enough != nil && o.Enough(events):
+		return true
+	}
+	return false
+}
+
+// fetchEventPages fetches a user's events, following the Link header's next
+// page until opts says to stop or there are no more pages.
+func fetchEventPages(ctx context.Context, username string, opts pageOptions) ([]Event, error) {
+	return fetchPagedEvents(ctx, apiURL+"/users/"+url.PathEscape(username)+"/events", "user not found", opts)
+}
+
+// fetchRepoEventPages is fetchEventPages for a repository's events.
+func fetchRepoEventPages(ctx context.Context, repo string, opts pageOptions) ([]Event, error) {
+	return fetchPagedEvents(ctx, apiURL+"/repos/"+repo+"/events", "repository not found", opts)
+}
+
+// fetchOrgEventPages is fetchEventPages for an organization's public events.
+func fetchOrgEventPages(ctx context.Context, org string, opts pageOptions) ([]Event, error) {
+	return fetchPagedEvents(ctx, apiURL+"/orgs/"+url.PathEscape(org)+"/events", "organization not found", opts)
+}
+
+func fetchPagedEvents(ctx context.Context, endpoint, notFound string, opts pageOptions) ([]Event, error) {
+	if opts.PerPage > 0 {
+		endpoint += "?per_page=" + strconv.Itoa(opts.PerPage)
+	}
+	var all []Event
+	for page := 1; endpoint != ""; page++ {
+		events, next, err := fetchEventPage(ctx, endpoint, notFound)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, events...)
+		if opts.done(page, all) {
+			break
 		}
+		endpoint = next
 	}
+	return all, nil
+}
+
+func fetchRepoEvents(ctx context.Context, repo string) ([]Event, error) {
+	return fetchEventList(ctx, apiURL+"/repos/"+repo+"/events", "repository not found")
 }
 
-func fetchEvents(username string) ([]Event, error) {
-	url := fmt.Sprintf(eventsURL, username)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// fetchEventList fetches an events endpoint and decodes each event on its
+// own, so one malformed event can be skipped under --lenient.
+func fetchEventList(ctx context.Context, endpoint, notFound string) ([]Event, error) {
+	events, _, err := fetchEventPage(ctx, endpoint, notFound)
+	return events, err
+}
+
+// fetchEventPage is fetchEventList for one page of a paginated endpoint,
+// also returning the next page's URL, or "" on the last page.
+func fetchEventPage(ctx context.Context, endpoint, notFound string) (events []Event, next string, err error) {
+	var raw []json.RawMessage
+	next, err = getJSONPage(ctx, endpoint, &raw)
+	if errors.Is(err, errNotFound) {
+		return nil, "", errors.New(notFound)
+	}
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	events, err = decodeEvents(raw)
+	return events, next, err
+}
+
+// parseRepo validates an owner/repo argument.
+func parseRepo(s string) (string, error) {
+	owner, name, ok := strings.Cut(s, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid repository %q: expected <owner>/<repo>", s)
+	}
+	return url.PathEscape(owner) + "/" + url.PathEscape(name), nil
+}
+
+// errNotFound is returned by getJSON for 404 responses so callers can say
+// what it was that couldn't be found.
+var errNotFound = errors.New("not found")
+
+// getJSON GETs a GitHub API endpoint and decodes the JSON response into v,
+// turning rate limiting and other API failures into readable errors.
+func getJSON(ctx context.Context, endpoint string, v any) error {
+	var link string
+	return doGetJSON(ctx, endpoint, v, &link)
+}
+
+// linkNext matches the next-page URL in a Link header, e.g.
+// <https://api.github.com/user/1/events?page=2>; rel="next".
+var linkNext = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="next"`)
+
+// getJSONPage is getJSON for paginated endpoints: it also returns the URL of
+// the next page from the Link header, or "" when there is none.
+func getJSONPage(ctx context.Context, endpoint string, v any) (next string, err error) {
+	var link string
+	err = doGetJSON(ctx, endpoint, v, &link)
+	if m := linkNext.FindStringSubmatch(link); m != nil {
+		next = m[1]
+	}
+	return next, err
+}
+
+// doGetJSON does the work of getJSON, also reporting the Link header of a
+// successful response.
+func doGetJSON(ctx context.Context, endpoint string, v any, link *string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
 	}
 	req.Header.Set("User-Agent", userAgent)
-	// If you have a token, uncomment to raise your rate limit:
-	// req.Header.Set("Authorization", "Bearer "+os.Getenv("GITHUB_TOKEN"))
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	cred := requestCredential(ctx, req.URL)
+	key := cacheKey(endpoint, cred.Token)
+	cached, haveCached := responseCache.get(key)
+	if haveCached {
+		if responseCache.fresh(cached) {
+			debugf("GET %s: from cache", req.URL.Redacted())
+			*link = cached.Link
+			if err := json.Unmarshal(cached.Body, v); err != nil {
+				return fmt.Errorf("decode failed: %w", err)
+			}
+			return nil
+		}
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cred.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cred.Token)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doWithRetry(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	debugResponse(req, resp, cred)
+	observeQuota(req.URL.Host, cred, resp.Header)
 
+	if resp.StatusCode == http.StatusNotModified && haveCached {
+		responseCache.put(key, cached) // still current as of now
+		*link = cached.Link
+		if err := json.Unmarshal(cached.Body, v); err != nil {
+			return fmt.Errorf("decode failed: %w", err)
+		}
+		return nil
+	}
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.New("user not found")
+		// GitHub hides resources a token can't see behind 404s.
+		if err := scopeError(resp.Header); err != nil {
+			return err
+		}
+		return errNotFound
 	}
 	if resp.StatusCode == http.StatusForbidden {
 		// likely rate limited
 		if rl := resp.Header.Get("X-RateLimit-Remaining"); rl == "0" {
 			reset := resp.Header.Get("X-RateLimit-Reset")
-			msg := "rate limit exceeded; set GITHUB_TOKEN to increase limits"
+			msg := "rate limit exceeded for unauthenticated requests; run auth login or set GITHUB_TOKEN to raise the limit"
+			if cred.Token != "" {
+				msg = "rate limit exceeded for the token from " + cred.Source
+			}
 			if reset != "" {
 				if ts, _ := parseUnix(reset); !ts.IsZero() {
 					msg += fmt.Sprintf(" (resets at %s)", ts.Local().Format(time.RFC1123))
 				}
 			}
-			return nil, errors.New(msg)
+			return errors.New(msg)
+		}
+		if err := scopeError(resp.Header); err != nil {
+			return err
 		}
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return nil, fmt.Errorf("github api error: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		return fmt.Errorf("github api error: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	*link = resp.Header.Get("Link")
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return fmt.Errorf("read failed: %w", err)
+	}
+	if len(body) > maxResponseBytes {
+		return fmt.Errorf("response exceeds %d bytes", maxResponseBytes)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode failed: %w", err)
 	}
+	responseCache.put(key, cachedResponse{ETag: resp.Header.Get("ETag"), Link: *link, Body: body})
+	return nil
+}
 
-	var events []Event
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&events); err != nil {
-		return nil, fmt.Errorf("decode failed: %w", err)
+// setTimezone makes tz (or UTC) the zone every human-readable timestamp is
+// rendered in. Renderers convert with Local(), so this is just time.Local.
+func setTimezone(tz string, utc bool) error {
+	if utc {
+		if tz != "" && tz != "UTC" {
+			return errors.New("--tz and --utc are mutually exclusive")
+		}
+		tz = "UTC"
+	}
+	if tz == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid --tz %q: unknown time zone", tz)
 	}
-	return events, nil
+	time.Local = loc
+	return nil
 }
 
 func parseUnix(s string) (time.Time, error) {
@@ -226,6 +1270,33 @@ func formatEvent(ev Event) (string, bool) {
 		return fmt.Sprintf("Deleted something in %s", repo), true
 	case "ReleaseEvent":
 		return fmt.Sprintf("Published or edited a release in %s", repo), true
+	case "DeploymentEvent":
+		var p DeploymentPayload
+		if err := json.Unmarshal(ev.Payload, &p); err != nil {
+			return "", false
+		}
+		what := "Created a deployment"
+		if p.Deployment.Ref != "" {
+			what = "Deployed " + p.Deployment.Ref
+		}
+		if env := p.Deployment.Environment; env != "" {
+			what += " to " + env
+		}
+		return fmt.Sprintf("%s in %s", what, repo), true
+	case "DeploymentStatusEvent":
+		var p DeploymentStatusPayload
+		if err := json.Unmarshal(ev.Payload, &p); err != nil {
+			return "", false
+		}
+		env := p.DeploymentStatus.Environment
+		if env == "" {
+			env = p.Deployment.Environment
+		}
+		what := "Deployment"
+		if env != "" {
+			what += " to " + env
+		}
+		return fmt.Sprintf("%s %s in %s", what, deploymentOutcome(p.DeploymentStatus.State), repo), true
 	case "PullRequestReviewCommentEvent":
 		return fmt.Sprintf("Commented on a PR review in %s", repo), true
 	case "IssueCommentEvent":
@@ -236,6 +1307,25 @@ func formatEvent(ev Event) (string, bool) {
 	}
 }
 
+// deploymentOutcome phrases a deployment status state, e.g. "failed".
+func deploymentOutcome(state string) string {
+	switch state = strings.ToLower(state); state {
+	case "success":
+		return "succeeded"
+	case "failure":
+		return "failed"
+	case "error":
+		return "errored"
+	case "inactive":
+		return "went inactive"
+	case "pending", "queued", "in_progress":
+		return "is " + strings.ReplaceAll(state, "_", " ")
+	case "":
+		return "changed state"
+	}
+	return "is " + state
+}
+
 func titleCase(s string) string {
 	if s == "" {
 		return s
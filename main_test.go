@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -12,9 +15,10 @@ import (
 
 func TestFetchEvents_OK(t *testing.T) {
 	// Fake GitHub endpoint
-	var gotUA string
+	var gotUA, gotAccept, gotVersion string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		gotUA = r.Header.Get("User-Agent")
+		gotAccept, gotVersion = r.Header.Get("Accept"), r.Header.Get("X-GitHub-Api-Version")
 		if !strings.HasPrefix(r.URL.Path, "/users/torvalds/events") {
 			t.Fatalf("unexpected path: %s", r.URL.Path)
 		}
@@ -32,12 +36,12 @@ func TestFetchEvents_OK(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	// Override the global eventsURL
-	restore := eventsURL
-	eventsURL = srv.URL + "/users/%s/events"
-	defer func() { eventsURL = restore }()
+	// Override the global apiURL
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
 
-	evs, err := fetchEvents("torvalds")
+	evs, err := fetchEvents(context.Background(), "torvalds")
 	if err != nil {
 		t.Fatalf("fetchEvents error: %v", err)
 	}
@@ -47,6 +51,9 @@ func TestFetchEvents_OK(t *testing.T) {
 	if gotUA == "" {
 		t.Fatal("expected User-Agent header to be set")
 	}
+	if gotAccept != "application/vnd.github+json" || gotVersion != defaultAPIVersion {
+		t.Fatalf("Accept %q, X-GitHub-Api-Version %q", gotAccept, gotVersion)
+	}
 	line, ok := formatEvent(evs[0])
 	if !ok || !strings.Contains(line, "Pushed 2 commit(s) to alice/repo") {
 		t.Fatalf("unexpected formatted line: %q ok=%v", line, ok)
@@ -58,11 +65,11 @@ func TestFetchEvents_UserNotFound(t *testing.T) {
 		http.NotFound(w, r)
 	}))
 	defer srv.Close()
-	restore := eventsURL
-	eventsURL = srv.URL + "/users/%s/events"
-	defer func() { eventsURL = restore }()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
 
-	_, err := fetchEvents("nope")
+	_, err := fetchEvents(context.Background(), "nope")
 	if err == nil || !strings.Contains(err.Error(), "user not found") {
 		t.Fatalf("expected user not found error, got %v", err)
 	}
@@ -76,11 +83,11 @@ func TestFetchEvents_RateLimited(t *testing.T) {
 		http.Error(w, "forbidden", http.StatusForbidden)
 	}))
 	defer srv.Close()
-	restore := eventsURL
-	eventsURL = srv.URL + "/users/%s/events"
-	defer func() { eventsURL = restore }()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
 
-	_, err := fetchEvents("someone")
+	_, err := fetchEvents(context.Background(), "someone")
 	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
 		t.Fatalf("expected rate limit error, got %v", err)
 	}
@@ -91,11 +98,11 @@ func TestFetchEvents_GenericAPIError(t *testing.T) {
 		http.Error(w, "bad gateway", http.StatusBadGateway)
 	}))
 	defer srv.Close()
-	restore := eventsURL
-	eventsURL = srv.URL + "/users/%s/events"
-	defer func() { eventsURL = restore }()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
 
-	_, err := fetchEvents("anyone")
+	_, err := fetchEvents(context.Background(), "anyone")
 	if err == nil || !strings.Contains(err.Error(), "github api error") {
 		t.Fatalf("expected generic api error, got %v", err)
 	}
@@ -261,6 +268,26 @@ func TestFormatEvent_Fork(t *testing.T) {
 	}
 }
 
+func TestFormatEvent_Deployments(t *testing.T) {
+	tests := []struct {
+		typ     string
+		payload any
+		want    string
+	}{
+		{"DeploymentEvent", map[string]any{"deployment": map[string]any{"ref": "main", "environment": "production"}}, "Deployed main to production in alice/repo"},
+		{"DeploymentEvent", map[string]any{"deployment": map[string]any{}}, "Created a deployment in alice/repo"},
+		{"DeploymentStatusEvent", map[string]any{"deployment_status": map[string]any{"state": "failure", "environment": "staging"}}, "Deployment to staging failed in alice/repo"},
+		{"DeploymentStatusEvent", map[string]any{"deployment_status": map[string]any{"state": "in_progress"}, "deployment": map[string]any{"environment": "production"}}, "Deployment to production is in progress in alice/repo"},
+	}
+	for _, tc := range tests {
+		ev := Event{Type: tc.typ, Payload: mustRaw(tc.payload)}
+		ev.Repo.Name = "alice/repo"
+		if got, ok := formatEvent(ev); !ok || got != tc.want {
+			t.Errorf("%s: got %q, %v; want %q", tc.typ, got, ok, tc.want)
+		}
+	}
+}
+
 func TestFormatEvent_GenericTypes(t *testing.T) {
 	tests := []struct {
 		typ  string
@@ -300,3 +327,289 @@ func TestFormatEvent_UnknownType(t *testing.T) {
 		t.Fatalf("expected skip for unknown type, got ok=%v line=%q", ok, got)
 	}
 }
+
+func TestPrintFailures(t *testing.T) {
+	var buf strings.Builder
+	printFailures(&buf, []userError{
+		{User: "bob", Err: fmt.Errorf("user not found")},
+	}, 3)
+	got := buf.String()
+	if !strings.Contains(got, "1 of 3 users failed") {
+		t.Fatalf("missing failure count: %q", got)
+	}
+	if !strings.Contains(got, "bob: user not found") {
+		t.Fatalf("missing per-user error: %q", got)
+	}
+}
+
+func TestSelectEntries(t *testing.T) {
+	events := []Event{
+		{Type: "WatchEvent", Payload: mustRaw(map[string]string{"action": "started"})},
+		{Type: "UnknownEvent"},
+		{Type: "CreateEvent"},
+		{Type: "DeleteEvent"},
+	}
+	got := selectEntries("alice", events, filters{}, 2)
+	if len(got) != 2 || got[0].Event.Type != "WatchEvent" || got[1].Event.Type != "CreateEvent" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+	got = selectEntries("alice", events, filters{Types: []string{"DeleteEvent"}}, 10)
+	if len(got) != 1 || got[0].User != "alice" {
+		t.Fatalf("type filter failed: %+v", got)
+	}
+}
+
+func TestCollapsePushes(t *testing.T) {
+	push := func(repo, ref string, size int) entry {
+		ev := Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"ref": ref, "size": size})}
+		ev.Repo.Name = repo
+		ev.Actor.Login = "alice"
+		s, _ := formatEvent(ev)
+		return entry{User: "alice", Event: ev, Summary: s}
+	}
+	star := entry{User: "alice", Event: Event{Type: "WatchEvent"}, Summary: "Starred x/y"}
+	entries := []entry{
+		push("alice/a", "refs/heads/main", 3),
+		push("alice/a", "refs/heads/main", 2),
+		push("alice/a", "refs/heads/main", 1),
+		push("alice/a", "refs/heads/dev", 4),
+		star,
+		push("alice/a", "refs/heads/dev", 5),
+	}
+	var got []string
+	for _, e := range collapsePushes(entries) {
+		got = append(got, e.Summary)
+	}
+	want := []string{
+		"Pushed 6 commit(s) to alice/a (3 pushes)",
+		"Pushed 4 commit(s) to alice/a",
+		"Starred x/y",
+		"Pushed 5 commit(s) to alice/a",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	if entries[0].Summary != "Pushed 3 commit(s) to alice/a" {
+		t.Errorf("input entries modified: %q", entries[0].Summary)
+	}
+}
+
+func TestFetchEvents_DeadlineExceeded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := fetchEvents(ctx, "slow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestDetailsOf(t *testing.T) {
+	tests := []struct {
+		typ     string
+		payload any
+		want    eventDetails
+	}{
+		{"PullRequestEvent", map[string]any{"action": "Closed", "pull_request": map[string]any{"number": 7, "title": "Feature"}}, eventDetails{"closed", 7, "Feature"}},
+		{"IssueCommentEvent", map[string]any{"action": "created", "issue": map[string]any{"number": 3, "title": "Bug"}}, eventDetails{"created", 3, "Bug"}},
+		{"ReleaseEvent", map[string]any{"action": "published", "release": map[string]any{"tag_name": "v1.0"}}, eventDetails{"published", 0, "v1.0"}},
+		{"CreateEvent", map[string]any{"ref_type": "branch", "ref": "main"}, eventDetails{"branch", 0, "main"}},
+		{"PushEvent", map[string]any{"size": 1}, eventDetails{}},
+	}
+	for _, tc := range tests {
+		got := detailsOf(Event{Type: tc.typ, Payload: mustRaw(tc.payload)})
+		if got != tc.want {
+			t.Fatalf("%s: got %+v want %+v", tc.typ, got, tc.want)
+		}
+	}
+}
+
+func TestParseRepo(t *testing.T) {
+	if got, err := parseRepo("golang/go"); err != nil || got != "golang/go" {
+		t.Fatalf("parseRepo(golang/go) = %q, %v", got, err)
+	}
+	for _, bad := range []string{"golang", "/go", "golang/", "a/b/c"} {
+		if _, err := parseRepo(bad); err == nil {
+			t.Fatalf("parseRepo(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSetTimezone(t *testing.T) {
+	restore := time.Local
+	defer func() { time.Local = restore }()
+
+	if err := setTimezone("Asia/Tokyo", false); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	if got := at.Local().Format("2006-01-02 15:04"); got != "2024-05-02 05:00" {
+		t.Errorf("Tokyo time = %s", got)
+	}
+	if err := setTimezone("", true); err != nil || time.Local.String() != "UTC" {
+		t.Errorf("--utc: zone %s, err %v", time.Local, err)
+	}
+	if err := setTimezone("Mars/Olympus", false); err == nil {
+		t.Error("expected error for unknown zone")
+	}
+	if err := setTimezone("Asia/Tokyo", true); err == nil {
+		t.Error("expected error for --tz with --utc")
+	}
+}
+
+func TestFitLimit(t *testing.T) {
+	tests := []struct {
+		height, users int
+		format        string
+		want          int
+	}{
+		{24, 1, "text", 23},
+		{24, 1, "table", 22},
+		{24, 2, "text", 10}, // 24 - prompt - 2 headers - 1 blank = 20
+		{0, 1, "text", 30},
+		{24, 1, "json", 30},
+		{3, 5, "text", 1},
+	}
+	for _, tt := range tests {
+		if got := fitLimit(tt.height, tt.users, tt.format); got != tt.want {
+			t.Errorf("fitLimit(%d, %d, %q) = %d, want %d", tt.height, tt.users, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestWindowNote(t *testing.T) {
+	pinClock(t)
+	events := make([]Event, eventsPageSize)
+	events[len(events)-1].CreatedAt = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	one := []entry{{}}
+
+	if got := windowNote(events, one, 10, true); !strings.Contains(got, "only the 30 most recent events (back to Mar 5, 2024)") {
+		t.Errorf("got %q", got)
+	}
+	if got := windowNote(events, one, 10, false); got != "" {
+		t.Errorf("unfiltered: %q", got)
+	}
+	if got := windowNote(events, one, 1, true); got != "" {
+		t.Errorf("limit reached: %q", got)
+	}
+	if got := windowNote(events[:5], one, 10, true); got != "" {
+		t.Errorf("short feed: %q", got)
+	}
+}
+
+func TestArrangeEntries(t *testing.T) {
+	push := Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"ref": "refs/heads/main", "size": 1})}
+	push.Repo.Name = "a/b"
+	entries := []entry{
+		{Event: Event{Type: "WatchEvent"}, Summary: "newest"},
+		{Event: push, Summary: "push 2"},
+		{Event: push, Summary: "push 1"},
+		{Event: Event{Type: "ForkEvent"}, Summary: "oldest"},
+	}
+	summaries := func(es []entry) string {
+		var s []string
+		for _, e := range es {
+			s = append(s, e.Summary)
+		}
+		return strings.Join(s, "|")
+	}
+	if got := summaries(arrangeEntries(entries, false, true)); got != "oldest|push 1|push 2|newest" {
+		t.Errorf("reverse: %s", got)
+	}
+	if got := summaries(arrangeEntries(entries, true, true)); got != "oldest|Pushed 2 commit(s) to a/b (2 pushes)|newest" {
+		t.Errorf("collapse+reverse: %s", got)
+	}
+	if got := summaries(entries); got != "newest|push 2|push 1|oldest" {
+		t.Errorf("input reordered: %s", got)
+	}
+}
+
+func TestMergeEntries(t *testing.T) {
+	at := func(user string, hour int, actor string) entry {
+		e := entry{User: user, Summary: fmt.Sprintf("%s@%d", user, hour)}
+		e.Event.CreatedAt = time.Date(2024, 5, 4, hour, 0, 0, 0, time.UTC)
+		e.Event.Actor.Login = actor
+		return e
+	}
+	entries := []entry{at("alice", 9, "alice"), at("alice", 5, "alice"), at("bob", 7, "bob"), at("bob", 3, "carol")}
+	summaries := func(es []entry) string {
+		var s []string
+		for _, e := range es {
+			s = append(s, e.Summary)
+		}
+		return strings.Join(s, "|")
+	}
+	if got := summaries(mergeEntries(entries, 3, false, false)); got != "alice@9|bob@7|alice@5" {
+		t.Errorf("merged: %s", got)
+	}
+	if got := summaries(mergeEntries(entries, 3, true, false)); got != "alice@5|bob@7|alice@9" {
+		t.Errorf("reverse: %s", got)
+	}
+	if got := summaries(mergeEntries(entries, 10, false, true)); got != "alice: alice@9|bob: bob@7|alice: alice@5|bob@3" {
+		t.Errorf("labeled: %s", got)
+	}
+	if entries[0].Summary != "alice@9" {
+		t.Errorf("input modified: %s", entries[0].Summary)
+	}
+}
+
+func TestFetchEventPages(t *testing.T) {
+	// Three full pages of hourly events, newest first, ending 300 hours back,
+	// linked with Link headers like GitHub's.
+	base := time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC)
+	var pages []string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		_, _ = fmt.Sscan(r.URL.Query().Get("page"), &page)
+		pages = append(pages, strconv.Itoa(page))
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("per_page = %q", r.URL.Query().Get("per_page"))
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/alice/events?per_page=100&page=%d>; rel="next", <%s/users/alice/events?per_page=100&page=3>; rel="last"`, srv.URL, page+1, srv.URL))
+		}
+		var resp []map[string]any
+		for i := (page - 1) * 100; i < page*100; i++ {
+			resp = append(resp, map[string]any{
+				"type":       "WatchEvent",
+				"created_at": base.Add(-time.Duration(i) * time.Hour).Format(time.RFC3339),
+				"repo":       map[string]any{"name": "alice/repo"},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	tests := []struct {
+		name      string
+		opts      pageOptions
+		wantPages string
+	}{
+		{"one page", pageOptions{PerPage: 100, Pages: 1}, "1"},
+		{"two pages", pageOptions{PerPage: 100, Pages: 2}, "1,2"},
+		{"all", pageOptions{PerPage: 100}, "1,2,3"},
+		{"since 150h back", pageOptions{PerPage: 100, Since: base.Add(-150 * time.Hour)}, "1,2"},
+		{"enough", pageOptions{PerPage: 100, Enough: func(evs []Event) bool { return len(evs) >= 100 }}, "1"},
+	}
+	for _, tt := range tests {
+		pages = nil
+		evs, err := fetchEventPages(context.Background(), "alice", tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := strings.Join(pages, ","); got != tt.wantPages || len(evs) != 100*len(pages) {
+			t.Errorf("%s: fetched pages %s (%d events), want %s", tt.name, got, len(evs), tt.wantPages)
+		}
+	}
+}
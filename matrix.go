@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// matrixSink posts watch alerts to a Matrix room as notices, the message
+// type meant for bots.
+type matrixSink struct {
+	homeserver string // e.g. https://matrix.example.org
+	token      string
+	room       string // room ID, e.g. !abc123:example.org
+}
+
+// matrixTxn makes transaction IDs unique within a run; the start time makes
+// them unique across runs with the same access token.
+var matrixTxn atomic.Int64
+
+func (s matrixSink) alert(ctx context.Context, user string, entries []entry) error {
+	body, err := json.Marshal(map[string]string{"msgtype": "m.notice", "body": alertText(user, entries)})
+	if err != nil {
+		return err
+	}
+	txn := fmt.Sprintf("gha-%d-%d", time.Now().UnixNano(), matrixTxn.Add(1))
+	endpoint := strings.TrimSuffix(s.homeserver, "/") + "/_matrix/client/v3/rooms/" +
+		url.PathEscape(s.room) + "/send/m.room.message/" + txn
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := sinkClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("matrix: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if json.Unmarshal(msg, &e) == nil && e.Error != "" {
+			return fmt.Errorf("matrix: %s: %s", resp.Status, e.Error)
+		}
+		return fmt.Errorf("matrix: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatrixSink(t *testing.T) {
+	var paths []string
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token"}`))
+			return
+		}
+		if r.Method != http.MethodPut {
+			t.Errorf("method %s", r.Method)
+		}
+		paths = append(paths, r.URL.EscapedPath())
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	defer srv.Close()
+
+	sink := matrixSink{homeserver: srv.URL + "/", token: "tok", room: "!room:example.org"}
+	entries := []entry{{Summary: "Starred a/one"}}
+	for range 2 {
+		if err := sink.alert(context.Background(), "alice", entries); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got["msgtype"] != "m.notice" || got["body"] != "New GitHub activity by alice:\n- Starred a/one" {
+		t.Errorf("body: %v", got)
+	}
+	prefix := "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/"
+	if len(paths) != 2 || !strings.HasPrefix(paths[0], prefix) || paths[0] == paths[1] {
+		t.Errorf("paths: %v", paths)
+	}
+
+	sink.token = "bad"
+	if err := sink.alert(context.Background(), "alice", entries); err == nil || !strings.Contains(err.Error(), "Invalid access token") {
+		t.Errorf("bad token: %v", err)
+	}
+}
+
+func TestMatrixSinkTimeout(t *testing.T) {
+	restore := sinkClient
+	sinkClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { sinkClient = restore }()
+
+	sink := matrixSink{homeserver: stalledServer(t).URL, token: "tok", room: "!room:example.org"}
+	if err := sink.alert(context.Background(), "alice", []entry{testEntry("PushEvent", "alice/repo", "Pushed")}); err == nil {
+		t.Error("stalled homeserver: want a timeout error")
+	}
+}
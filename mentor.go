@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// learningKinds names the kinds of activity a mentee's range is measured
+// in. Stars and follows aren't contributions, so they don't count.
+var learningKinds = map[string]string{
+	"PushEvent":                     "commits",
+	"PullRequestEvent":              "pull requests",
+	"PullRequestReviewEvent":        "reviews",
+	"PullRequestReviewCommentEvent": "reviews",
+	"IssuesEvent":                   "issues",
+	"IssueCommentEvent":             "discussions",
+	"ReleaseEvent":                  "releases",
+	"CreateEvent":                   "new repositories and branches",
+	"ForkEvent":                     "forks",
+}
+
+// milestone is a first worth celebrating, e.g. a first pull request to a
+// repository.
+type milestone struct {
+	At   time.Time
+	Text string
+}
+
+// milestones finds the firsts in events (newest first), oldest first: the
+// first pull request and issue in each repository, and the first review,
+// release, and new repository overall.
+func milestones(events []Event) []milestone {
+	var out []milestone
+	seen := map[string]bool{}
+	first := func(key string, ev Event, text string) {
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, milestone{At: ev.CreatedAt, Text: text})
+		}
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		repo := ev.Repo.Name
+		d := detailsOf(ev)
+		switch ev.Type {
+		case "PullRequestEvent":
+			if d.Action == "opened" {
+				first("pr "+repo, ev, fmt.Sprintf("First pull request to %s: #%d “%s”", repo, d.Number, d.Title))
+			}
+		case "IssuesEvent":
+			if d.Action == "opened" {
+				first("issue "+repo, ev, fmt.Sprintf("First issue in %s: #%d “%s”", repo, d.Number, d.Title))
+			}
+		case "PullRequestReviewEvent":
+			first("review", ev, fmt.Sprintf("First code review: %s #%d", repo, d.Number))
+		case "ReleaseEvent":
+			first("release", ev, fmt.Sprintf("First release: %s %s", repo, d.Title))
+		case "CreateEvent":
+			var p payloadFields
+			if json.Unmarshal(ev.Payload, &p) == nil && p.RefType == "repository" {
+				first("repository", ev, "First new repository: "+repo)
+			}
+		}
+	}
+	return out
+}
+
+// learningWeek is one week's range of activity.
+type learningWeek struct {
+	Start time.Time
+	Repos map[string]bool
+	Kinds map[string]bool
+	Count int
+}
+
+// learningWeeks groups events (newest first) by week, oldest first, counting
+// the distinct repositories and kinds of activity in each. Quiet weeks up to
+// the current one are included, so gaps show.
+func learningWeeks(events []Event) []learningWeek {
+	var weeks []learningWeek
+	// addWeeks appends empty weeks until the one starting at start.
+	addWeeks := func(start time.Time) {
+		for len(weeks) == 0 || weeks[len(weeks)-1].Start.Before(start) {
+			next := start
+			if len(weeks) > 0 {
+				next = weeks[len(weeks)-1].Start.AddDate(0, 0, 7)
+			}
+			weeks = append(weeks, learningWeek{Start: next, Repos: map[string]bool{}, Kinds: map[string]bool{}})
+		}
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		kind, ok := learningKinds[ev.Type]
+		if !ok {
+			continue
+		}
+		start, _, _ := periodBounds("week", ev.CreatedAt)
+		addWeeks(start)
+		w := &weeks[len(weeks)-1]
+		w.Repos[ev.Repo.Name] = true
+		w.Kinds[kind] = true
+		w.Count++
+	}
+	if len(weeks) > 0 {
+		current, _, _ := periodBounds("week", now())
+		addWeeks(current)
+	}
+	return weeks
+}
+
+// breadth is how much ground a week covered: its repositories plus its kinds
+// of work.
+func (w learningWeek) breadth() int { return len(w.Repos) + len(w.Kinds) }
+
+// plural is "1 thing" or "n things".
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+func printMentorReport(w io.Writer, user string, events []Event) {
+	var contributions []Event
+	for _, ev := range events {
+		if _, ok := learningKinds[ev.Type]; ok {
+			contributions = append(contributions, ev)
+		}
+	}
+	if len(contributions) == 0 {
+		fmt.Fprintf(w, "%s has no recent public contributions yet. Everyone starts somewhere!\n", user)
+		return
+	}
+	fmt.Fprintf(w, "Progress report for %s%s\n", user, sinceOldest(contributions))
+
+	fmt.Fprintln(w, "\nMilestones:")
+	for _, m := range milestones(contributions) {
+		fmt.Fprintf(w, "  ★ %s  %s\n", m.At.Local().Format("Jan 02"), m.Text)
+	}
+
+	fmt.Fprintln(w, "\nRange, week by week:")
+	weeks := learningWeeks(contributions)
+	for _, wk := range weeks {
+		if wk.Count == 0 {
+			fmt.Fprintf(w, "  %s  quiet\n", wk.Start.Format("Jan 02"))
+			continue
+		}
+		fmt.Fprintf(w, "  %s  %-15s %-18s %s\n", wk.Start.Format("Jan 02"), plural(len(wk.Repos), "repository", "repositories"),
+			plural(len(wk.Kinds), "kind of work", "kinds of work"), strings.Repeat("█", wk.breadth()))
+	}
+
+	repos, kinds := map[string]bool{}, map[string]bool{}
+	var repoNames []string
+	for _, ev := range contributions {
+		repos[ev.Repo.Name] = true
+		kinds[learningKinds[ev.Type]] = true
+		repoNames = append(repoNames, ev.Repo.Name)
+	}
+	kindNames := make([]string, 0, len(kinds))
+	for k := range kinds {
+		kindNames = append(kindNames, k)
+	}
+	slices.Sort(kindNames)
+	fmt.Fprintf(w, "\n%s across %s, most in %s.\n", plural(len(contributions), "contribution", "contributions"),
+		plural(len(repos), "repository", "repositories"), rank(repoNames)[0].Key)
+	fmt.Fprintf(w, "Kinds of work so far: %s.\n", strings.Join(kindNames, ", "))
+	active := slices.DeleteFunc(slices.Clone(weeks), func(wk learningWeek) bool { return wk.Count == 0 })
+	if n := len(active); n >= 2 && active[n-1].breadth() > active[0].breadth() {
+		fmt.Fprintln(w, "Their range is growing: their latest active week covered more ground than their first. Keep it up!")
+		return
+	}
+	for _, next := range []string{"pull requests", "reviews", "issues", "releases"} {
+		if !kinds[next] {
+			fmt.Fprintf(w, "Keep it up! A good next step: try some %s.\n", next)
+			return
+		}
+	}
+	fmt.Fprintln(w, "Keep it up!")
+}
+
+func runMentor(args []string) int {
+	fs := flag.NewFlagSet("mentor", flag.ExitOnError)
+	tz := fs.String("tz", "", "Time zone that weeks start in (default: local).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s mentor [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints an encouraging progress report for a mentee: their firsts (first pull")
+		fmt.Fprintln(fs.Output(), "request to a repository, first review, first release, ...) and how the range of")
+		fmt.Fprintln(fs.Output(), "their work grows week by week. Firsts are among the activity GitHub still keeps,")
+		fmt.Fprintln(fs.Output(), "the last 300 events or 90 days.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	events, err := fetchEventPages(context.Background(), fs.Arg(0), pageOptions{PerPage: 100})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	printMentorReport(os.Stdout, fs.Arg(0), events)
+	return 0
+}
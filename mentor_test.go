@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// menteeEvents is a mentee's feed, newest first: an issue and a pull request
+// in acme/app in the first week, then a review and a pull request to a
+// second repository in the next.
+func menteeEvents() []Event {
+	ev := func(typ, repo string, day int, payload any) Event {
+		e := Event{Type: typ, CreatedAt: time.Date(2024, 4, day, 12, 0, 0, 0, time.UTC), Payload: mustRaw(payload)}
+		e.Repo.Name = repo
+		return e
+	}
+	pr := func(n int, title string) map[string]any {
+		return map[string]any{"action": "opened", "pull_request": map[string]any{"number": n, "title": title}}
+	}
+	return []Event{
+		ev("PullRequestEvent", "bob/lib", 25, pr(3, "Add example")),
+		ev("PullRequestReviewEvent", "acme/app", 24, map[string]any{"action": "created", "pull_request": map[string]any{"number": 9}}),
+		ev("WatchEvent", "bob/lib", 23, map[string]any{"action": "started"}),
+		ev("PullRequestEvent", "acme/app", 17, pr(8, "Second fix")),
+		ev("PullRequestEvent", "acme/app", 16, pr(7, "Fix typo")),
+		ev("IssuesEvent", "acme/app", 15, map[string]any{"action": "opened", "issue": map[string]any{"number": 6, "title": "Docs unclear"}}),
+	}
+}
+
+func TestMilestones(t *testing.T) {
+	var got []string
+	for _, m := range milestones(menteeEvents()) {
+		got = append(got, m.Text)
+	}
+	want := []string{
+		"First issue in acme/app: #6 “Docs unclear”",
+		"First pull request to acme/app: #7 “Fix typo”",
+		"First code review: acme/app #9",
+		"First pull request to bob/lib: #3 “Add example”",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestLearningWeeks(t *testing.T) {
+	pinClock(t)
+	weeks := learningWeeks(menteeEvents())
+	if len(weeks) != 3 {
+		t.Fatalf("got %d weeks", len(weeks))
+	}
+	counts := [][2]int{}
+	for _, w := range weeks {
+		counts = append(counts, [2]int{len(w.Repos), len(w.Kinds)})
+	}
+	// Apr 15: acme/app issues + pull requests; Apr 22: two repos, reviews and
+	// pull requests (the star doesn't count); Apr 29: quiet so far.
+	if !weeks[0].Start.Equal(time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)) || counts[0] != [2]int{1, 2} || counts[1] != [2]int{2, 2} || counts[2] != [2]int{0, 0} {
+		t.Errorf("got %v from %v", counts, weeks[0].Start)
+	}
+}
+
+func TestPrintMentorReport(t *testing.T) {
+	pinClock(t)
+	var b strings.Builder
+	printMentorReport(&b, "alice", menteeEvents())
+	out := b.String()
+	for _, want := range []string{
+		"Progress report for alice since Apr 15, 2024",
+		"★ Apr 16  First pull request to acme/app",
+		"Apr 22  2 repositories  2 kinds of work    ████",
+		"5 contributions across 2 repositories, most in acme/app.",
+		"Kinds of work so far: issues, pull requests, reviews.",
+		"Apr 29  quiet\n",
+		"Their range is growing",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	b.Reset()
+	printMentorReport(&b, "carol", menteeEvents()[3:])
+	if !strings.Contains(b.String(), "A good next step: try some reviews.") {
+		t.Errorf("suggestion: %q", b.String())
+	}
+	b.Reset()
+	printMentorReport(&b, "bob", nil)
+	if !strings.Contains(b.String(), "Everyone starts somewhere") {
+		t.Errorf("empty: %q", b.String())
+	}
+}
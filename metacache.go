@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultMetadataTTL is how long looked-up metadata, such as whether a
+// repository is a fork and its default branch, is reused. It rarely
+// changes, so it is kept far longer than event pages.
+const defaultMetadataTTL = 24 * time.Hour
+
+// cachedMetadata is a metadata lookup's decoded result, as kept on disk.
+type cachedMetadata struct {
+	Value   json.RawMessage `json:"value"`
+	Fetched time.Time       `json:"fetched"`
+}
+
+// metadataCache stores metadata lookups by cacheKey, one file each, in dir,
+// apart from the response cache so event pages can expire much sooner.
+// Entries older than ttl are looked up again.
+type metadataCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// metaCache is the cache metadata lookups use, or nil to always ask GitHub.
+// main sets it up; tests leave it off.
+var metaCache *metadataCache
+
+// defaultMetadataCache keeps metadata in the user cache directory next to
+// the response cache, or returns nil when there is none.
+func defaultMetadataCache() *metadataCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return &metadataCache{dir: filepath.Join(dir, "github-activity", "metadata"), ttl: defaultMetadataTTL}
+}
+
+// get decodes the entry for key into v, reporting whether there was one
+// younger than the TTL.
+func (c *metadataCache) get(key string, v any) bool {
+	if c == nil || c.ttl <= 0 {
+		return false
+	}
+	var m cachedMetadata
+	data, err := os.ReadFile(cacheFile(c.dir, key))
+	if err != nil || json.Unmarshal(data, &m) != nil || now().Sub(m.Fetched) >= c.ttl {
+		return false
+	}
+	return json.Unmarshal(m.Value, v) == nil
+}
+
+// put stores v for key, stamped with the current time. Failures only cost a
+// lookup next time, so they are ignored.
+func (c *metadataCache) put(key string, v any) {
+	if c == nil {
+		return
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cachedMetadata{Value: value, Fetched: now()})
+	if err != nil {
+		return
+	}
+	_ = os.MkdirAll(c.dir, 0o700)
+	// Metadata looked up with a token can describe private repositories.
+	_ = os.WriteFile(cacheFile(c.dir, key), data, 0o600)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLookupRepoMetadataCache(t *testing.T) {
+	pinClock(t)
+	lookups := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		fmt.Fprint(w, `{"fork": true, "default_branch": "trunk"}`)
+	}))
+	defer srv.Close()
+	restoreURL, restoreCache := apiURL, metaCache
+	apiURL, metaCache = srv.URL, &metadataCache{dir: t.TempDir(), ttl: time.Hour}
+	defer func() { apiURL, metaCache = restoreURL, restoreCache }()
+
+	lookup := func() repoInfo {
+		t.Helper()
+		repoInfoCache.m = map[string]repoInfo{} // as in a new run
+		info, err := lookupRepo(context.Background(), "alice/fork")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+	if info := lookup(); !info.Fork || info.DefaultBranch != "trunk" || lookups != 1 {
+		t.Fatalf("got %+v after %d lookups", info, lookups)
+	}
+	if info := lookup(); info.DefaultBranch != "trunk" || lookups != 1 {
+		t.Errorf("second run: got %+v after %d lookups, want it from disk", info, lookups)
+	}
+	files, _ := filepath.Glob(filepath.Join(metaCache.dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("cache files: %v", files)
+	}
+	if fi, err := os.Stat(files[0]); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("cache file mode: %v, %v", fi.Mode(), err)
+	}
+
+	// Another token doesn't see the entry.
+	t.Setenv("GH_ENTERPRISE_TOKEN", "other")
+	if lookup(); lookups != 2 {
+		t.Errorf("another token: %d lookups, want 2", lookups)
+	}
+
+	later := now().Add(time.Hour)
+	now = func() time.Time { return later }
+	if lookup(); lookups != 3 {
+		t.Errorf("after the TTL: %d lookups, want 3", lookups)
+	}
+}
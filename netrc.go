@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// netrcPath is the .netrc file tokens may come from, set by main; "" reads
+// none.
+var netrcPath string
+
+// defaultNetrcPath is $NETRC, or .netrc in the home directory (_netrc on
+// Windows), where curl and git look.
+func defaultNetrcPath() string {
+	if p := os.Getenv("NETRC"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name)
+}
+
+// netrcPassword finds the password of the first machine entry in a .netrc
+// file's contents for host, where github.com and api.github.com are the same
+// machine. Unlike curl, it ignores the default entry, whose password is
+// meant for some other server. macdef bodies are skipped.
+func netrcPassword(data, host string) (string, bool) {
+	lines := strings.Split(data, "\n")
+	var fields []string
+	for i := 0; i < len(lines); i++ {
+		f := strings.Fields(lines[i])
+		if len(f) > 0 && strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		if idx := slices.Index(f, "macdef"); idx >= 0 {
+			fields = append(fields, f[:idx]...)
+			// The macro runs to the next blank line.
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+				i++
+			}
+			continue
+		}
+		fields = append(fields, f...)
+	}
+
+	matched, inMachine := false, false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if matched {
+				return "", false // the matching entry had no password
+			}
+			inMachine = i+1 < len(fields)
+			if inMachine {
+				i++
+				matched = tokenHost(fields[i]) == tokenHost(host)
+			}
+		case "default":
+			if matched {
+				return "", false
+			}
+			inMachine = false
+		case "login", "account":
+			i++
+		case "password":
+			if i+1 < len(fields) && inMachine && matched {
+				return fields[i+1], true
+			}
+			i++
+		}
+	}
+	return "", false
+}
+
+// netrcCredential is host's password in the .netrc file, used as a token.
+func netrcCredential(host string) credential {
+	if netrcPath == "" {
+		return credential{}
+	}
+	data, err := os.ReadFile(netrcPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			debugf(".netrc: %v", err)
+		}
+		return credential{}
+	}
+	token, ok := netrcPassword(string(data), host)
+	if !ok {
+		return credential{}
+	}
+	return credential{Token: token, Source: netrcPath}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetrcPassword(t *testing.T) {
+	data := `# work
+machine gitlab.com login bob password gl-secret
+machine github.com
+  login alice
+  password gh-secret
+
+macdef init
+machine api.github.com password not-this
+
+machine github.example.com login alice password ghe-secret
+default login anonymous password default-secret
+`
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"api.github.com", "gh-secret"},
+		{"github.com", "gh-secret"},
+		{"GitHub.Example.com", "ghe-secret"},
+		{"other.example.com", ""}, // default is ignored
+	}
+	for _, tt := range tests {
+		if got, _ := netrcPassword(data, tt.host); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.host, got, tt.want)
+		}
+	}
+	if _, ok := netrcPassword("machine api.github.com login alice\nmachine github.com password later", "api.github.com"); ok {
+		t.Error("took a later entry's password for an entry without one")
+	}
+}
+
+func TestNetrcCredentialComesLast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(path, []byte("machine api.github.com login alice password netrc-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	restore := netrcPath
+	netrcPath = path
+	defer func() { netrcPath = restore }()
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	if got := hostCredential(context.Background(), "api.github.com"); got != (credential{"netrc-secret", path}) {
+		t.Errorf("got %+v", got)
+	}
+	t.Setenv("GITHUB_TOKEN", "env-secret")
+	if got := hostCredential(context.Background(), "api.github.com"); got.Source != "$GITHUB_TOKEN" {
+		t.Errorf("netrc took precedence: %+v", got)
+	}
+	if got := hostCredentials(context.Background(), "api.github.com"); len(got) != 2 || got[1].Source != path {
+		t.Errorf("credentials: %+v", got)
+	}
+}
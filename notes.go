@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Note is a personal annotation on an event, kept in the notes file by
+// event ID, e.g.
+//
+//	{"41234567890": {"text": "follow up after the release", "created": "2024-05-04T09:00:00Z"}}
+type Note struct {
+	Text    string    `json:"text"`
+	Created time.Time `json:"created"`
+}
+
+// notesPath is where notes are kept: $GITHUB_ACTIVITY_NOTES, or notes.json
+// next to the config file.
+func notesPath() string {
+	if p := os.Getenv("GITHUB_ACTIVITY_NOTES"); p != "" {
+		return p
+	}
+	if c := configPath(); c != "" {
+		return filepath.Join(filepath.Dir(c), "notes.json")
+	}
+	return ""
+}
+
+// loadNotes reads the notes file; a missing file has no notes.
+func loadNotes(path string) (map[string]Note, error) {
+	notes := map[string]Note{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return notes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return notes, nil
+}
+
+func saveNotes(path string, notes map[string]Note) error {
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// attachNotes copies each entry's note, if it has one, onto the entry.
+func attachNotes(entries []entry, notes map[string]Note) {
+	for i, e := range entries {
+		if n, ok := notes[e.Event.ID]; ok && e.Event.ID != "" {
+			entries[i].Note = n.Text
+		}
+	}
+}
+
+func printNotes(w io.Writer, notes map[string]Note) {
+	if len(notes) == 0 {
+		fmt.Fprintln(w, "No notes yet.")
+		return
+	}
+	ids := make([]string, 0, len(notes))
+	for id := range notes {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int { return notes[b].Created.Compare(notes[a].Created) })
+	for _, id := range ids {
+		n := notes[id]
+		fmt.Fprintf(w, "%s  %s  %s\n", n.Created.Local().Format("2006-01-02"), id, n.Text)
+	}
+}
+
+func runAnnotate(args []string) int {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	file := fs.String("file", "", "Notes file (default: $GITHUB_ACTIVITY_NOTES, or notes.json next to the config file).")
+	list := fs.Bool("list", false, "List all notes, newest first.")
+	del := fs.Bool("delete", false, "Remove the note on the event instead of setting it.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s annotate [options] <event-id> <text>...\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s annotate --delete <event-id>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s annotate --list\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Keeps a personal note on an event, shown under it in the activity list and as")
+		fmt.Fprintln(fs.Output(), "\"note\" in JSON. Event IDs are the id field of JSON, NDJSON, and CSV output.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	switch {
+	case *list && fs.NArg() == 0 && !*del:
+	case *del && fs.NArg() == 1 && !*list:
+	case !*list && !*del && fs.NArg() >= 2:
+	default:
+		fs.Usage()
+		return 2
+	}
+	if *file == "" {
+		*file = notesPath()
+	}
+	notes, err := loadNotes(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if *list {
+		printNotes(os.Stdout, notes)
+		return 0
+	}
+
+	id := fs.Arg(0)
+	if *del {
+		if _, ok := notes[id]; !ok {
+			fmt.Fprintf(os.Stderr, "Error: no note on event %s\n", id)
+			return 1
+		}
+		delete(notes, id)
+	} else {
+		text := strings.Join(fs.Args()[1:], " ")
+		if strings.TrimSpace(text) == "" {
+			fmt.Fprintln(os.Stderr, "Error: the note is empty")
+			return 2
+		}
+		notes[id] = Note{Text: text, Created: now()}
+	}
+	if err := saveNotes(*file, notes); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
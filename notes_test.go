@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "notes.json")
+	notes, err := loadNotes(path)
+	if err != nil || len(notes) != 0 {
+		t.Fatalf("missing file: got %v, %v", notes, err)
+	}
+	created := time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC)
+	notes["42"] = Note{Text: "follow up", Created: created}
+	if err := saveNotes(path, notes); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadNotes(path)
+	if err != nil || got["42"].Text != "follow up" || !got["42"].Created.Equal(created) {
+		t.Errorf("got %v, %v", got, err)
+	}
+}
+
+func TestAttachNotes(t *testing.T) {
+	entries := []entry{{Event: Event{ID: "1"}}, {Event: Event{ID: "2"}}, {}}
+	attachNotes(entries, map[string]Note{"2": {Text: "check CI"}, "": {Text: "stray"}})
+	if entries[0].Note != "" || entries[1].Note != "check CI" || entries[2].Note != "" {
+		t.Errorf("got %+v", entries)
+	}
+}
+
+func TestPrintNotes(t *testing.T) {
+	pinClock(t)
+	var b strings.Builder
+	printNotes(&b, map[string]Note{
+		"1": {Text: "older", Created: now().Add(-48 * time.Hour)},
+		"2": {Text: "newer", Created: now()},
+	})
+	if got, want := b.String(), "2024-05-04  2  newer\n2024-05-02  1  older\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTextRendererShowsNotes(t *testing.T) {
+	var b strings.Builder
+	r := &textRenderer{w: &b}
+	e := testEntry("PushEvent", "alice/repo", "Pushed 1 commit(s) to alice/repo")
+	e.Note = "revert if CI breaks"
+	if err := r.feed("alice", []Event{e.Event}, []entry{e}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.String(), "- Pushed 1 commit(s) to alice/repo\n  ✎ revert if CI breaks\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
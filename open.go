@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"os/exec"
+	"runtime"
+)
+
+// openBrowser opens url with the platform's default handler. Tests replace it.
+var openBrowser = func(url string) error {
+	name, args := browserCommand(runtime.GOOS, url)
+	return exec.Command(name, args...).Start()
+}
+
+// browserCommand is the opener for goos. On Windows, rundll32 is used rather
+// than "cmd /c start", which would interpret & and ^ in query strings.
+func browserCommand(goos, url string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	}
+	return "xdg-open", []string{url}
+}
+
+// eventURL is where --open takes an event: the pushed commit for pushes,
+// otherwise the issue, pull request, or repository.
+func eventURL(ev Event) string {
+	if ev.Type == "PushEvent" {
+		var p payloadFields
+		if json.Unmarshal(ev.Payload, &p) == nil && p.Head != "" {
+			return repoURL(ev.Repo.Name) + "/commit/" + p.Head
+		}
+	}
+	return entityURL(ev)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBrowserCommand(t *testing.T) {
+	url := "https://github.com/a/b?x=1&y=2"
+	tests := map[string]string{
+		"linux":   "xdg-open " + url,
+		"darwin":  "open " + url,
+		"windows": "rundll32 url.dll,FileProtocolHandler " + url,
+	}
+	for goos, want := range tests {
+		name, args := browserCommand(goos, url)
+		if got := name + " " + strings.Join(args, " "); got != want {
+			t.Errorf("%s: got %q want %q", goos, got, want)
+		}
+	}
+}
+
+func TestEventURL(t *testing.T) {
+	push := Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"ref": "refs/heads/main", "head": "abc123"})}
+	push.Repo.Name = "alice/repo"
+	if got := eventURL(push); got != "https://github.com/alice/repo/commit/abc123" {
+		t.Errorf("push: %s", got)
+	}
+	issue := Event{Type: "IssuesEvent", Payload: mustRaw(map[string]any{"issue": map[string]any{"number": 3}})}
+	issue.Repo.Name = "alice/repo"
+	if got := eventURL(issue); got != "https://github.com/alice/repo/issues/3" {
+		t.Errorf("issue: %s", got)
+	}
+}
@@ -0,0 +1,518 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// renderer writes selected events in one output format. feed is called once
+// per user, in order; flush is called once after the last user so formats
+// that need the whole result (like a JSON array) can write it out.
+type renderer interface {
+	feed(user string, events []Event, entries []entry) error
+	flush() error
+}
+
+// outputOptions selects and configures a renderer.
+type outputOptions struct {
+	Format       string
+	EventTypes   []string // active --type filter, for "nothing found" messages
+	Filtered     bool     // other filters are active, for "nothing found" messages
+	Multi        bool     // more than one user is being rendered
+	Delimiter    string   // csv only
+	Template     string   // template only
+	TemplateFile string   // template only
+	Width        int      // text and table only; max line width, 0 for no truncation
+	Color        bool     // text and table only
+	Hyperlinks   bool     // text and table only; OSC 8 links
+	GroupBy      string   // text only; "", "repo", "day", or "type"
+	Numbered     bool     // text only; number events across all users
+	Emoji        bool     // text only; prefix lines with an emoji per event type
+}
+
+// formats lists every --format value newRenderer accepts.
+var formats = []string{"text", "json", "ndjson", "csv", "table", "markdown", "html", "atom", "plaintext-digest", "waybar", "xbar", "alfred", "template"}
+
+func newRenderer(w io.Writer, opts outputOptions) (renderer, error) {
+	switch opts.Format {
+	case "", "text":
+		return &textRenderer{w: w, eventTypes: opts.EventTypes, filtered: opts.Filtered, multi: opts.Multi, groupBy: opts.GroupBy, numbered: opts.Numbered, emoji: opts.Emoji, width: opts.Width, pal: palette{enabled: opts.Color, links: opts.Hyperlinks}}, nil
+	case "json":
+		return &jsonRenderer{w: w}, nil
+	case "ndjson":
+		return &ndjsonRenderer{enc: json.NewEncoder(w)}, nil
+	case "csv":
+		comma, err := parseDelimiter(opts.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		cw := csv.NewWriter(w)
+		cw.Comma = comma
+		return &csvRenderer{w: cw}, nil
+	case "table":
+		return &tableRenderer{w: w, multi: opts.Multi, width: opts.Width, pal: palette{enabled: opts.Color, links: opts.Hyperlinks}}, nil
+	case "markdown", "md":
+		return &markdownRenderer{w: w, multi: opts.Multi}, nil
+	case "html":
+		return &htmlRenderer{w: w}, nil
+	case "atom":
+		return &atomRenderer{w: w}, nil
+	case "plaintext-digest":
+		return &digestRenderer{w: w}, nil
+	case "waybar":
+		return &waybarRenderer{w: w}, nil
+	case "xbar":
+		return &xbarRenderer{w: w}, nil
+	case "alfred":
+		return &alfredRenderer{w: w}, nil
+	case "template":
+		t, err := parseEventTemplate(opts.Template, opts.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		return &templateRenderer{w: w, t: t}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", opts.Format)
+	}
+}
+
+// textRenderer prints the human-readable bullet list.
+type textRenderer struct {
+	w          io.Writer
+	eventTypes []string
+	filtered   bool
+	multi      bool   // print a header per user
+	groupBy    string // bucket entries under headers; see groupEntries
+	numbered   bool   // "1. " bullets, counting on across users
+	emoji      bool
+	width      int // truncate lines to this many columns; 0 for no limit
+	pal        palette
+	fed        int
+	n          int        // events printed so far
+	ci         []ciStatus // set by setCI for the next feed
+}
+
+func (r *textRenderer) setCI(_ string, statuses []ciStatus) { r.ci = statuses }
+
+func (r *textRenderer) feed(user string, events []Event, entries []entry) error {
+	if r.multi {
+		if r.fed > 0 {
+			fmt.Fprintln(r.w)
+		}
+		fmt.Fprintf(r.w, "%s:\n", r.pal.wrap(ansiBold, user))
+	}
+	r.fed++
+	defer r.printCI()
+
+	if len(events) == 0 {
+		fmt.Fprintln(r.w, "No recent public activity.")
+		return nil
+	}
+	if r.groupBy != "" {
+		for i, g := range groupEntries(entries, r.groupBy) {
+			if i > 0 {
+				fmt.Fprintln(r.w)
+			}
+			fmt.Fprintf(r.w, "%s (%d)\n", r.groupHeader(g), len(g.Entries))
+			for _, e := range g.Entries {
+				fmt.Fprintln(r.w, r.line("  ", e))
+				r.note("  ", e)
+			}
+		}
+	} else {
+		for _, e := range entries {
+			fmt.Fprintln(r.w, r.line("", e))
+			r.note("", e)
+		}
+	}
+	if len(entries) == 0 {
+		if len(r.eventTypes) == 1 {
+			fmt.Fprintf(r.w, "No events of type %q found.\n", r.eventTypes[0])
+		} else if len(r.eventTypes) > 1 {
+			fmt.Fprintf(r.w, "No events of types %s found.\n", strings.Join(r.eventTypes, ", "))
+		} else if r.filtered {
+			fmt.Fprintln(r.w, "No matching events found.")
+		} else {
+			fmt.Fprintln(r.w, "No printable events found.")
+		}
+	}
+	return nil
+}
+
+func (r *textRenderer) flush() error { return nil }
+
+// printCI prints the CI failures setCI gave for the user just fed.
+func (r *textRenderer) printCI() {
+	for _, st := range r.ci {
+		fmt.Fprintln(r.w, r.pal.wrap(ansiRed, "! "+st.String()))
+	}
+	r.ci = nil
+}
+
+// line renders one event, truncating the summary so the whole line fits
+// the width. Truncation happens before coloring so escape codes don't count.
+func (r *textRenderer) line(indent string, e entry) string {
+	prefix := indent + r.bullet()
+	if r.emoji {
+		prefix += eventEmoji(e.Event.Type) + " "
+	}
+	prefix += actorPrefix(e)
+	if r.width > 0 {
+		e.Summary = truncateWidth(e.Summary, max(r.width-displayWidth(prefix), 10))
+	}
+	return prefix + r.pal.summary(e)
+}
+
+// note prints the entry's annotation, if any, indented under its line.
+func (r *textRenderer) note(indent string, e entry) {
+	if e.Note != "" {
+		fmt.Fprintln(r.w, indent+"  "+r.pal.dim("✎ "+e.Note))
+	}
+}
+
+func (r *textRenderer) bullet() string {
+	r.n++
+	if r.numbered {
+		return strconv.Itoa(r.n) + ". "
+	}
+	return "- "
+}
+
+func (r *textRenderer) groupHeader(g entryGroup) string {
+	switch r.groupBy {
+	case "repo":
+		return r.pal.repo(g.Key)
+	case "type":
+		return r.pal.eventType(g.Entries[0].Event.Type, g.Key)
+	}
+	return r.pal.wrap(ansiBold, g.Key)
+}
+
+// groupByModes lists the values --group-by accepts.
+var groupByModes = []string{"repo", "day", "type"}
+
+// entryGroup is a bucket of entries sharing a --group-by key.
+type entryGroup struct {
+	Key     string
+	Entries []entry
+}
+
+// groupEntries buckets entries by repository, local calendar day, or event
+// type. Groups appear in the order their first entry does, so with the
+// newest-first feed the most recently active group comes first; entries keep
+// their order within a group.
+func groupEntries(entries []entry, by string) []entryGroup {
+	var groups []entryGroup
+	index := map[string]int{}
+	for _, e := range entries {
+		var key string
+		switch by {
+		case "repo":
+			key = e.Event.Repo.Name
+		case "day":
+			key = e.Event.CreatedAt.Local().Format("2006-01-02 (Mon)")
+		case "type":
+			key = strings.TrimSuffix(e.Event.Type, "Event")
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, entryGroup{Key: key})
+		}
+		groups[i].Entries = append(groups[i].Entries, e)
+	}
+	return groups
+}
+
+// displayOrder lists entries in the order text output prints them, grouped
+// when by is set, so --open and --pin count the way --numbered does.
+func displayOrder(entries []entry, by string) []entry {
+	if by == "" {
+		return entries
+	}
+	var out []entry
+	for _, g := range groupEntries(entries, by) {
+		out = append(out, g.Entries...)
+	}
+	return out
+}
+
+// actorPrefix names who performed the event when that isn't simply the owner
+// of the feed being shown, e.g. for events in shared org or repo feeds.
+func actorPrefix(e entry) string {
+	login := e.Event.Actor.Login
+	if login == "" || strings.EqualFold(login, e.User) {
+		return ""
+	}
+	return login + ": "
+}
+
+// jsonEvent is the structured form of an entry used by --json.
+type jsonEvent struct {
+	ID        string    `json:"id,omitempty"`
+	User      string    `json:"user"`
+	Actor     string    `json:"actor,omitempty"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Repo      string    `json:"repo"`
+	Summary   string    `json:"summary"`
+	Note      string    `json:"note,omitempty"`
+	Private   bool      `json:"private,omitempty"`
+}
+
+func toJSONEvent(e entry) jsonEvent {
+	return jsonEvent{
+		ID:        e.Event.ID,
+		User:      e.User,
+		Actor:     e.Event.Actor.Login,
+		AvatarURL: e.Event.Actor.AvatarURL,
+		Type:      e.Event.Type,
+		CreatedAt: e.Event.CreatedAt,
+		Repo:      e.Event.Repo.Name,
+		Summary:   e.Summary,
+		Note:      e.Note,
+		Private:   e.Event.private(),
+	}
+}
+
+// jsonRenderer buffers every entry and writes a single JSON array on flush.
+type jsonRenderer struct {
+	w      io.Writer
+	events []jsonEvent
+}
+
+func (r *jsonRenderer) feed(_ string, _ []Event, entries []entry) error {
+	for _, e := range entries {
+		r.events = append(r.events, toJSONEvent(e))
+	}
+	return nil
+}
+
+func (r *jsonRenderer) flush() error {
+	if r.events == nil {
+		r.events = []jsonEvent{} // encode as [] rather than null
+	}
+	enc := json.NewEncoder(r.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.events)
+}
+
+// ndjsonRenderer writes one JSON object per line as soon as each user's
+// entries arrive, so output can be piped into jq without waiting for the run.
+type ndjsonRenderer struct {
+	enc *json.Encoder
+}
+
+func (r *ndjsonRenderer) feed(_ string, _ []Event, entries []entry) error {
+	for _, e := range entries {
+		if err := r.enc.Encode(toJSONEvent(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ndjsonRenderer) flush() error { return nil }
+
+var csvHeader = []string{"timestamp", "user", "type", "repo", "action", "number", "title", "id"}
+
+// csvRenderer writes one row per entry. encoding/csv takes care of quoting
+// titles that contain the delimiter, quotes, or newlines.
+type csvRenderer struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (r *csvRenderer) header() {
+	if !r.wroteHeader {
+		_ = r.w.Write(csvHeader)
+		r.wroteHeader = true
+	}
+}
+
+func (r *csvRenderer) feed(_ string, _ []Event, entries []entry) error {
+	r.header()
+	for _, e := range entries {
+		d := detailsOf(e.Event)
+		number := ""
+		if d.Number != 0 {
+			number = strconv.Itoa(d.Number)
+		}
+		_ = r.w.Write([]string{
+			e.Event.CreatedAt.Format(time.RFC3339),
+			e.User,
+			e.Event.Type,
+			e.Event.Repo.Name,
+			d.Action,
+			number,
+			d.Title,
+			e.Event.ID,
+		})
+	}
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func (r *csvRenderer) flush() error {
+	r.header()
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid delimiter %q: must be a single character other than a quote or newline", s)
+	}
+	return r, nil
+}
+
+// markdownRenderer writes a bullet list with repositories, issues, and pull
+// requests linked, for pasting into a profile README or blog post.
+type markdownRenderer struct {
+	w     io.Writer
+	multi bool
+	fed   int
+}
+
+func (r *markdownRenderer) feed(user string, _ []Event, entries []entry) error {
+	if r.multi {
+		if r.fed > 0 {
+			fmt.Fprintln(r.w)
+		}
+		fmt.Fprintf(r.w, "### %s\n\n", markdownEscape(user))
+	}
+	r.fed++
+	for _, e := range entries {
+		fmt.Fprintln(r.w, "- "+markdownEscape(actorPrefix(e))+markdownLine(e))
+	}
+	return nil
+}
+
+func (r *markdownRenderer) flush() error { return nil }
+
+// markdownLine escapes an entry's summary and turns the repository name and
+// issue/PR number in it into links.
+func markdownLine(e entry) string {
+	line := markdownEscape(e.Summary)
+	// Link the repository first so its name isn't also matched inside the
+	// issue/PR URL.
+	if repo := e.Event.Repo.Name; repo != "" {
+		esc := markdownEscape(repo)
+		line = strings.ReplaceAll(line, esc, fmt.Sprintf("[%s](%s)", esc, repoURL(repo)))
+	}
+	if d := detailsOf(e.Event); d.Number != 0 {
+		num := fmt.Sprintf("#%d", d.Number)
+		line = strings.Replace(line, num, fmt.Sprintf("[%s](%s)", num, entityURL(e.Event)), 1)
+	}
+	return line
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// tableRenderer buffers every entry and prints aligned columns. The summary
+// column is truncated so rows fit the terminal when its width is known.
+type tableRenderer struct {
+	w       io.Writer
+	multi   bool // add a USER column
+	width   int
+	pal     palette
+	entries []entry
+}
+
+func (r *tableRenderer) feed(_ string, _ []Event, entries []entry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *tableRenderer) flush() error {
+	header := []string{"TIME", "TYPE", "REPO", "SUMMARY"}
+	if r.multi {
+		header = append([]string{"USER"}, header...)
+	}
+	rows := [][]string{header}
+	for _, e := range r.entries {
+		row := []string{
+			e.Event.CreatedAt.Local().Format("2006-01-02 15:04"),
+			strings.TrimSuffix(e.Event.Type, "Event"),
+			e.Event.Repo.Name,
+			actorPrefix(e) + e.Summary,
+		}
+		if r.multi {
+			row = append([]string{e.User}, row...)
+		}
+		rows = append(rows, row)
+	}
+
+	const gap = "  "
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+	last := len(header) - 1
+	if r.width > 0 {
+		used := 0
+		for _, w := range widths[:last] {
+			used += w + len(gap)
+		}
+		// Never squeeze the summary below a readable minimum; let the row
+		// wrap instead.
+		widths[last] = min(widths[last], max(r.width-used, 10))
+	}
+
+	// Cells are padded and truncated as plain text, then colored, so escape
+	// codes never count toward column widths.
+	offset := len(header) - 4 // USER column shifts the rest right
+	for n, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			if i == last {
+				cell = truncateWidth(cell, widths[i])
+			} else {
+				cell = padWidth(cell, widths[i])
+			}
+			switch {
+			case n == 0:
+				cell = r.pal.wrap(ansiBold, cell)
+			case i == offset:
+				cell = r.pal.dim(cell)
+			case i == offset+1:
+				cell = r.pal.eventType(r.entries[n-1].Event.Type, cell)
+			case i == offset+2:
+				name := strings.TrimRight(cell, " ") // don't link the padding
+				cell = r.pal.link(repoURL(name), r.pal.repo(name)) + cell[len(name):]
+			}
+			b.WriteString(cell)
+			if i != last {
+				b.WriteString(gap)
+			}
+		}
+		if _, err := fmt.Fprintln(r.w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntry(typ, repo, summary string) entry {
+	ev := Event{Type: typ, CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	ev.Repo.Name = repo
+	return entry{User: "alice", Event: ev, Summary: summary}
+}
+
+func TestTextRenderer(t *testing.T) {
+	var buf strings.Builder
+	r := &textRenderer{w: &buf}
+	e := testEntry("PushEvent", "alice/repo", "Pushed 1 commit(s) to alice/repo")
+	if err := r.feed("alice", []Event{e.Event}, []entry{e}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "- Pushed 1 commit(s) to alice/repo\n"; got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestTextRenderer_MultiUserHeadersAndEmpty(t *testing.T) {
+	var buf strings.Builder
+	r := &textRenderer{w: &buf, eventTypes: []string{"PushEvent"}, multi: true}
+	_ = r.feed("alice", nil, nil)
+	_ = r.feed("bob", []Event{{Type: "WatchEvent"}}, nil)
+	want := "alice:\nNo recent public activity.\n\nbob:\nNo events of type \"PushEvent\" found.\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestTextRenderer_Numbered(t *testing.T) {
+	var buf strings.Builder
+	r := &textRenderer{w: &buf, multi: true, numbered: true}
+	a := testEntry("WatchEvent", "x/a", "Starred x/a")
+	b := testEntry("WatchEvent", "x/b", "Starred x/b")
+	_ = r.feed("alice", []Event{a.Event}, []entry{a})
+	_ = r.feed("bob", []Event{b.Event}, []entry{b})
+	want := "alice:\n1. Starred x/a\n\nbob:\n2. Starred x/b\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestTextRenderer_Emoji(t *testing.T) {
+	var buf strings.Builder
+	r := &textRenderer{w: &buf, emoji: true}
+	e := testEntry("WatchEvent", "a/b", "Starred a/b")
+	_ = r.feed("alice", []Event{e.Event}, []entry{e})
+	if got, want := buf.String(), "- ⭐ Starred a/b\n"; got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestTextRenderer_Width(t *testing.T) {
+	var buf strings.Builder
+	r := &textRenderer{w: &buf, width: 20, pal: palette{enabled: true}}
+	e := testEntry("IssuesEvent", "a/b", "Opened issue “日本語のタイトル” in a/b")
+	_ = r.feed("alice", []Event{e.Event}, []entry{e})
+	got := strings.TrimSuffix(stripANSI(buf.String()), "\n")
+	if got != "- Opened issue “日…" { // 本 would need column 19 and 20, leaving no room for …
+		t.Errorf("got %q", got)
+	}
+	if w := displayWidth(got); w > 20 {
+		t.Errorf("line is %d columns wide", w)
+	}
+}
+
+func TestTextRenderer_GroupBy(t *testing.T) {
+	a1 := testEntry("PushEvent", "alice/a", "Pushed 1 commit(s) to alice/a")
+	b := testEntry("WatchEvent", "bob/b", "Starred bob/b")
+	b.Event.CreatedAt = b.Event.CreatedAt.Add(-24 * time.Hour)
+	a2 := testEntry("IssuesEvent", "alice/a", "Opened issue #1 in alice/a")
+	a2.Event.CreatedAt = b.Event.CreatedAt
+	entries := []entry{a1, b, a2}
+	events := []Event{a1.Event, b.Event, a2.Event}
+
+	tests := map[string]string{
+		"repo": "alice/a (2)\n  - Pushed 1 commit(s) to alice/a\n  - Opened issue #1 in alice/a\n\nbob/b (1)\n  - Starred bob/b\n",
+		"day":  "2024-05-01 (Wed) (1)\n  - Pushed 1 commit(s) to alice/a\n\n2024-04-30 (Tue) (2)\n  - Starred bob/b\n  - Opened issue #1 in alice/a\n",
+		"type": "Push (1)\n  - Pushed 1 commit(s) to alice/a\n\nWatch (1)\n  - Starred bob/b\n\nIssues (1)\n  - Opened issue #1 in alice/a\n",
+	}
+	pinClock(t)
+	for by, want := range tests {
+		var buf strings.Builder
+		r := &textRenderer{w: &buf, groupBy: by}
+		if err := r.feed("alice", events, entries); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("--group-by=%s:\ngot  %q\nwant %q", by, got, want)
+		}
+	}
+}
+
+func TestDisplayOrderMatchesNumbers(t *testing.T) {
+	pinClock(t)
+	a1 := testEntry("WatchEvent", "a/x", "Starred a/x")
+	b := testEntry("WatchEvent", "b/y", "Starred b/y")
+	a2 := testEntry("WatchEvent", "a/x", "Starred a/x again")
+	entries := []entry{a1, b, a2}
+
+	var buf strings.Builder
+	r := &textRenderer{w: &buf, groupBy: "repo", numbered: true}
+	_ = r.feed("alice", []Event{a1.Event, b.Event, a2.Event}, entries)
+	order := displayOrder(entries, "repo")
+	for n, e := range order {
+		line := fmt.Sprintf("  %d. %s\n", n+1, e.Summary)
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("--open %d would open %q, but the output is:\n%s", n+1, e.Summary, buf.String())
+		}
+	}
+	if got := displayOrder(entries, ""); got[1].Summary != "Starred b/y" {
+		t.Errorf("ungrouped order changed: %v", got)
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var buf strings.Builder
+	r := &jsonRenderer{w: &buf}
+	e := testEntry("PushEvent", "alice/repo", "Pushed 1 commit(s) to alice/repo")
+	_ = r.feed("alice", []Event{e.Event}, []entry{e})
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	var got []jsonEvent
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	if len(got) != 1 || got[0].Repo != "alice/repo" || got[0].Type != "PushEvent" || got[0].Summary == "" {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+}
+
+func TestJSONRenderer_EmptyIsArray(t *testing.T) {
+	var buf strings.Builder
+	r := &jsonRenderer{w: &buf}
+	_ = r.flush()
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Fatalf("got %q want []", got)
+	}
+}
+
+func TestTextRenderer_ShowsForeignActor(t *testing.T) {
+	var buf strings.Builder
+	r := &textRenderer{w: &buf}
+	own := testEntry("CreateEvent", "alice/repo", "Created something in alice/repo")
+	own.Event.Actor.Login = "Alice"
+	other := testEntry("CreateEvent", "alice/repo", "Created something in alice/repo")
+	other.Event.Actor.Login = "bob"
+	_ = r.feed("alice", []Event{own.Event, other.Event}, []entry{own, other})
+	want := "- Created something in alice/repo\n- bob: Created something in alice/repo\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestNDJSONRenderer(t *testing.T) {
+	var buf strings.Builder
+	r := &ndjsonRenderer{enc: json.NewEncoder(&buf)}
+	a := testEntry("PushEvent", "alice/a", "Pushed 1 commit(s) to alice/a")
+	b := testEntry("WatchEvent", "alice/b", "Starred alice/b")
+	_ = r.feed("alice", []Event{a.Event}, []entry{a})
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Fatalf("expected first entry to be written immediately, got %d lines", n)
+	}
+	_ = r.feed("alice", []Event{b.Event}, []entry{b})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	var got jsonEvent
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil || got.Repo != "alice/b" {
+		t.Fatalf("bad line %q: %v", lines[1], err)
+	}
+}
+
+func TestCSVRenderer_QuotesTitles(t *testing.T) {
+	var buf strings.Builder
+	out, err := newRenderer(&buf, outputOptions{Format: "csv", Delimiter: ";"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := testEntry("IssuesEvent", "alice/repo", "")
+	e.Event.Payload = mustRaw(map[string]any{
+		"action": "opened",
+		"issue":  map[string]any{"number": 42, "title": `Crash; "boom"`},
+	})
+	_ = out.feed("alice", []Event{e.Event}, []entry{e})
+	_ = out.flush()
+	want := "timestamp;user;type;repo;action;number;title;id\n" +
+		`2024-05-01T12:00:00Z;alice;IssuesEvent;alice/repo;opened;42;"Crash; ""boom""";` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestCSVRenderer_HeaderOnlyWhenEmpty(t *testing.T) {
+	var buf strings.Builder
+	out, _ := newRenderer(&buf, outputOptions{Format: "csv"})
+	_ = out.flush()
+	if got := buf.String(); got != strings.Join(csvHeader, ",")+"\n" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    rune
+		wantErr bool
+	}{
+		{"", ',', false},
+		{";", ';', false},
+		{"tab", '\t', false},
+		{"|", '|', false},
+		{"::", 0, true},
+		{`"`, 0, true},
+	}
+	for _, tc := range tests {
+		got, err := parseDelimiter(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Fatalf("parseDelimiter(%q) = %q, %v", tc.in, got, err)
+		}
+	}
+}
+
+func TestNewRenderer_UnknownFormat(t *testing.T) {
+	if _, err := newRenderer(io.Discard, outputOptions{Format: "yaml"}); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestMarkdownRenderer_Links(t *testing.T) {
+	var buf strings.Builder
+	r := &markdownRenderer{w: &buf}
+	e := testEntry("PullRequestEvent", "alice/repo", "")
+	e.Event.Payload = mustRaw(map[string]any{
+		"action":       "opened",
+		"pull_request": map[string]any{"number": 7, "title": "Add *bold* [feature]"},
+	})
+	e.Summary, _ = formatEvent(e.Event)
+	_ = r.feed("alice", []Event{e.Event}, []entry{e})
+	want := "- Opened a pull request [#7](https://github.com/alice/repo/pull/7) " +
+		"“Add \\*bold\\* \\[feature\\]” in [alice/repo](https://github.com/alice/repo)\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestMarkdownLine_EscapedRepoName(t *testing.T) {
+	e := testEntry("WatchEvent", "alice/my_repo", "Starred alice/my_repo")
+	want := "Starred [alice/my\\_repo](https://github.com/alice/my_repo)"
+	if got := markdownLine(e); got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestTableRenderer_AlignsAndTruncates(t *testing.T) {
+	var buf strings.Builder
+	r := &tableRenderer{w: &buf, width: 60}
+	a := testEntry("PushEvent", "alice/a", "Pushed 1 commit(s) to alice/a")
+	b := testEntry("WatchEvent", "bob/longer-name", "Starred bob/longer-name, a repository with a very long description")
+	_ = r.feed("alice", nil, []entry{a, b})
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want header + 2 rows, got %q", buf.String())
+	}
+	col := strings.Index(lines[0], "SUMMARY")
+	for _, l := range lines[1:] {
+		if !strings.HasPrefix(l[col:], "Pushed") && !strings.HasPrefix(l[col:], "Starred") {
+			t.Fatalf("summary column misaligned in %q", l)
+		}
+		if displayWidth(l) > 60 {
+			t.Fatalf("row wider than terminal: %q", l)
+		}
+	}
+	if !strings.HasSuffix(lines[2], "…") || !strings.Contains(lines[2], "Watch ") {
+		t.Fatalf("expected truncated watch row, got %q", lines[2])
+	}
+}
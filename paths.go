@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// matchPath reports whether a repository file path matches a glob in which
+// "**" stands for any number of directories, e.g. "services/auth/**" or
+// "**/*.proto". Other segments are matched with path.Match.
+func matchPath(glob, name string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(glob, name []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(glob[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(glob[0], name[0]); !ok {
+			return false
+		}
+		glob, name = glob[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// changedFiles lists the paths a push or pull request touched, from the
+// compare, commit, or pull request files API. ok is false for other events.
+func changedFiles(ctx context.Context, ev Event) (files []string, ok bool, err error) {
+	var p sizePayload
+	if json.Unmarshal(ev.Payload, &p) != nil {
+		return nil, false, nil
+	}
+	var list []struct {
+		Filename string `json:"filename"`
+	}
+	switch {
+	case ev.Type == "PullRequestEvent" && p.PullRequest != nil:
+		err = getJSON(ctx, apiURL+"/repos/"+ev.Repo.Name+"/pulls/"+strconv.Itoa(p.PullRequest.Number)+"/files?per_page=100", &list)
+	case ev.Type == "PushEvent" && p.Head != "":
+		// A branch creation has nothing to compare against; use its head commit.
+		endpoint := apiURL + "/repos/" + ev.Repo.Name + "/commits/" + p.Head
+		if strings.Trim(p.Before, "0") != "" {
+			endpoint = apiURL + "/repos/" + ev.Repo.Name + "/compare/" + p.Before + "..." + p.Head
+		}
+		var resp struct {
+			Files []struct {
+				Filename string `json:"filename"`
+			} `json:"files"`
+		}
+		err = getJSON(ctx, endpoint, &resp)
+		list = resp.Files
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	for _, f := range list {
+		files = append(files, f.Filename)
+	}
+	return files, true, nil
+}
+
+// pathCheck keeps pushes and pull requests that changed a file matching any
+// of the globs.
+func pathCheck(globs []string) eventCheck {
+	return func(ctx context.Context, ev Event) (bool, error) {
+		files, ok, err := changedFiles(ctx, ev)
+		if !ok || err != nil {
+			return false, err
+		}
+		for _, f := range files {
+			for _, g := range globs {
+				if matchPath(g, f) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+}
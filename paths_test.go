@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		glob, name string
+		want       bool
+	}{
+		{"services/auth/**", "services/auth/main.go", true},
+		{"services/auth/**", "services/auth/internal/token.go", true},
+		{"services/auth/**", "services/authz/main.go", false},
+		{"**/*.proto", "api/v1/user.proto", true},
+		{"**/*.proto", "user.proto", true},
+		{"services/*/go.mod", "services/auth/go.mod", true},
+		{"services/*/go.mod", "services/auth/sub/go.mod", false},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"README.md", "docs/README.md", false},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.glob, tt.name); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.glob, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPathCheck(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/repos/acme/mono/compare/a1...a2":
+			_, _ = w.Write([]byte(`{"files":[{"filename":"services/auth/main.go"}]}`))
+		case "/repos/acme/mono/compare/b1...b2":
+			_, _ = w.Write([]byte(`{"files":[{"filename":"services/billing/main.go"}]}`))
+		case "/repos/acme/mono/pulls/9/files":
+			_, _ = w.Write([]byte(`[{"filename":"README.md"},{"filename":"services/auth/token.go"}]`))
+		case "/repos/acme/mono/commits/c2":
+			_, _ = w.Write([]byte(`{"files":[{"filename":"services/auth/new.go"}]}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	ev := func(typ string, payload any) Event {
+		e := Event{Type: typ, Payload: mustRaw(payload)}
+		e.Repo.Name = "acme/mono"
+		return e
+	}
+	events := []Event{
+		ev("PushEvent", map[string]any{"before": "a1", "head": "a2"}),
+		ev("WatchEvent", map[string]any{"action": "started"}),
+		ev("PushEvent", map[string]any{"before": "b1", "head": "b2"}),
+		ev("PullRequestEvent", map[string]any{"action": "opened", "pull_request": map[string]any{"number": 9}}),
+		ev("PushEvent", map[string]any{"before": "0000000000", "head": "c2"}),
+	}
+	got := selectChecked(context.Background(), events, filters{}, []eventCheck{pathCheck([]string{"services/auth/**"})}, 10)
+	if len(got) != 3 || got[0].Type != "PushEvent" || got[1].Type != "PullRequestEvent" || got[2].Type != "PushEvent" {
+		t.Errorf("got %+v", got)
+	}
+
+	// Stops looking up changes once the limit is reached.
+	calls = 0
+	if got := selectChecked(context.Background(), events, filters{}, []eventCheck{pathCheck([]string{"services/auth/**"})}, 1); len(got) != 1 || calls != 1 {
+		t.Errorf("limit 1: got %d events after %d calls", len(got), calls)
+	}
+}
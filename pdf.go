@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page geometry for writePDF, in points.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 56
+)
+
+// pdfLine is a line of text laid out on a page.
+type pdfLine struct {
+	Font string // F1 Helvetica, F2 Helvetica-Bold, F3 Courier
+	Size float64
+	Text string
+}
+
+// writePDF renders a small subset of Markdown (# and ## headings, bullets,
+// tables, and paragraphs, with ** and _ emphasis dropped) as a PDF. It uses
+// the standard Helvetica and Courier fonts every viewer has, so nothing is
+// embedded, and characters outside their Latin-1 set print as "?".
+func writePDF(w io.Writer, markdown string) error {
+	var lines []pdfLine
+	var table [][]string
+	flushTable := func() {
+		if len(table) == 0 {
+			return
+		}
+		widths := make([]int, len(table[0]))
+		for _, row := range table {
+			for i, cell := range row {
+				if i < len(widths) {
+					widths[i] = max(widths[i], displayWidth(cell))
+				}
+			}
+		}
+		for _, row := range table {
+			var b strings.Builder
+			for i, cell := range row {
+				if i < len(widths) {
+					b.WriteString(padWidth(cell, widths[i]+2))
+				}
+			}
+			lines = append(lines, pdfLine{"F3", 9, strings.TrimRight(b.String(), " ")})
+		}
+		table = nil
+	}
+	for _, raw := range strings.Split(markdown, "\n") {
+		text := strings.NewReplacer("**", "", "`", "").Replace(raw)
+		if strings.HasPrefix(text, "|") {
+			cells := strings.Split(strings.Trim(text, "|"), "|")
+			if strings.Trim(strings.Join(cells, ""), "-: ") == "" {
+				continue // the header separator row
+			}
+			for i := range cells {
+				cells[i] = strings.TrimSpace(cells[i])
+			}
+			table = append(table, cells)
+			continue
+		}
+		flushTable()
+		switch {
+		case strings.HasPrefix(text, "# "):
+			lines = append(lines, pdfLine{"F2", 18, text[2:]})
+		case strings.HasPrefix(text, "## "):
+			lines = append(lines, pdfLine{"F1", 6, ""}, pdfLine{"F2", 13, text[3:]})
+		case strings.HasPrefix(text, "- "):
+			for i, l := range wrapText(text[2:], pdfChars(10)-2, "") {
+				prefix := "•  "
+				if i > 0 {
+					prefix = "   "
+				}
+				lines = append(lines, pdfLine{"F1", 10, prefix + l})
+			}
+		default:
+			text = strings.Trim(text, "_")
+			for _, l := range wrapText(text, pdfChars(10), "") {
+				lines = append(lines, pdfLine{"F1", 10, l})
+			}
+			if text == "" {
+				lines = append(lines, pdfLine{"F1", 10, ""})
+			}
+		}
+	}
+	flushTable()
+	return writePDFPages(w, paginate(lines))
+}
+
+// pdfChars is roughly how many Helvetica characters of size fit on a line.
+func pdfChars(size float64) int {
+	return int((pdfPageWidth - 2*pdfMargin) / (size * 0.5))
+}
+
+// paginate splits lines into pages' content streams.
+func paginate(lines []pdfLine) []string {
+	var pages []string
+	var b strings.Builder
+	y := float64(pdfPageHeight - pdfMargin)
+	for _, l := range lines {
+		step := l.Size * 1.4
+		if y-step < pdfMargin {
+			pages = append(pages, b.String())
+			b.Reset()
+			y = pdfPageHeight - pdfMargin
+		}
+		y -= step
+		if l.Text != "" {
+			fmt.Fprintf(&b, "BT /%s %g Tf %d %.1f Td (%s) Tj ET\n", l.Font, l.Size, pdfMargin, y, pdfString(l.Text))
+		}
+	}
+	return append(pages, b.String())
+}
+
+// pdfString encodes s for a PDF string literal in WinAnsiEncoding.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			c, ok := winAnsi[r]
+			if !ok {
+				c = '?'
+			}
+			fmt.Fprintf(&b, "\\%03o", c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsi maps the punctuation WinAnsiEncoding has outside Latin-1.
+var winAnsi = map[rune]byte{
+	'…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '€': 0x80,
+}
+
+// writePDFPages writes a PDF document with one page per content stream.
+func writePDFPages(w io.Writer, pages []string) error {
+	var objs []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	objs = append(objs,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+	for i, content := range pages {
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 7+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWritePDF(t *testing.T) {
+	var b bytes.Buffer
+	md := "# Report (draft)\n\n## Summary\n- **Languages:** Go, “C”\n\n| Repo | Count |\n|---|---:|\n| acme/app | 4 |\n"
+	if err := writePDF(&b, md); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"%PDF-1.4\n",
+		"/F2 18 Tf 56 760.8 Td (Report \\(draft\\)) Tj",
+		"(\\225  Languages: Go, \\223C\\224) Tj",
+		"/F3 9 Tf",
+		"(acme/app  4) Tj",
+		"/Count 1 >>",
+		"%%EOF\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "---") {
+		t.Error("table separator row was rendered")
+	}
+
+	// Every xref entry must point at its object.
+	xref := out[strings.Index(out, "xref\n"):]
+	for i, line := range strings.Split(xref, "\n")[3:8] {
+		off, err := strconv.Atoi(line[:10])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(out[off:], want) {
+			t.Errorf("xref entry %d points at %q", i+1, out[off:off+10])
+		}
+	}
+	start := out[strings.LastIndex(out, "startxref\n")+10:]
+	if off, _ := strconv.Atoi(strings.Fields(start)[0]); !strings.HasPrefix(out[off:], "xref\n") {
+		t.Errorf("startxref %d doesn't point at the xref table", off)
+	}
+}
+
+func TestWritePDFPaginates(t *testing.T) {
+	var b bytes.Buffer
+	if err := writePDF(&b, strings.Repeat("line\n", 100)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "/Count 2 >>") {
+		t.Error("100 lines didn't spill onto a second page")
+	}
+}
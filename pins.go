@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Pin is a bookmarked event, saved with enough of it to be listed after it
+// drops off the feed.
+type Pin struct {
+	ID      string `json:"id"`
+	User    string `json:"user"`
+	Summary string `json:"summary"`
+	URL     string `json:"url"`
+	Created string `json:"created_at"`
+	Pinned  string `json:"pinned_at"`
+}
+
+// pinsPath is where pins are kept: $GITHUB_ACTIVITY_PINS, or pins.json next
+// to the config file.
+func pinsPath() string {
+	if p := os.Getenv("GITHUB_ACTIVITY_PINS"); p != "" {
+		return p
+	}
+	if c := configPath(); c != "" {
+		return filepath.Join(filepath.Dir(c), "pins.json")
+	}
+	return ""
+}
+
+// loadPins reads the pins file, oldest pin first; a missing file has none.
+func loadPins(path string) ([]Pin, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pins []Pin
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pins, nil
+}
+
+func savePins(path string, pins []Pin) error {
+	if pins == nil {
+		pins = []Pin{}
+	}
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func pinOf(e entry) Pin {
+	return Pin{
+		ID:      e.Event.ID,
+		User:    e.User,
+		Summary: e.Summary,
+		URL:     eventURL(e.Event),
+		Created: e.Event.CreatedAt.Format(time.RFC3339),
+		Pinned:  now().UTC().Format(time.RFC3339),
+	}
+}
+
+// addPin pins e to the pins file, unless it is already pinned.
+func addPin(path string, e entry) error {
+	if e.Event.ID == "" {
+		return errors.New("event has no ID")
+	}
+	pins, err := loadPins(path)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(pins, func(p Pin) bool { return p.ID == e.Event.ID }) {
+		return nil
+	}
+	return savePins(path, append(pins, pinOf(e)))
+}
+
+func printPins(w io.Writer, pins []Pin) {
+	if len(pins) == 0 {
+		fmt.Fprintln(w, "No pinned events.")
+		return
+	}
+	for i := len(pins) - 1; i >= 0; i-- { // most recently pinned first
+		p := pins[i]
+		fmt.Fprintf(w, "%s  %s: %s\n    %s\n", p.ID, p.User, p.Summary, p.URL)
+	}
+}
+
+// findEvent looks for the event with the given ID in user's recent feed.
+func findEvent(ctx context.Context, user, id string) (entry, error) {
+	events, err := fetchEvents(ctx, user)
+	if err != nil {
+		return entry{}, err
+	}
+	for _, ev := range events {
+		if ev.ID != id {
+			continue
+		}
+		summary, ok := formatEvent(ev)
+		if !ok {
+			summary = ev.Type + " in " + ev.Repo.Name
+		}
+		return entry{User: user, Event: ev, Summary: summary}, nil
+	}
+	return entry{}, fmt.Errorf("event %s is not in %s's recent activity", id, user)
+}
+
+func runPin(args []string) int {
+	fs := flag.NewFlagSet("pin", flag.ExitOnError)
+	user := fs.String("user", "", "User whose feed the event is in (required).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s pin --user <github-username> <event-id>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Bookmarks an event for later; see pins. Event IDs are the id field of JSON,")
+		fmt.Fprintln(fs.Output(), "NDJSON, and CSV output. --pin N on the main command pins a listed event directly.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 || *user == "" {
+		fs.Usage()
+		return 2
+	}
+	e, err := findEvent(context.Background(), *user, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if err := addPin(pinsPath(), e); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Println("Pinned:", e.Summary)
+	return 0
+}
+
+func runPins(args []string) int {
+	fs := flag.NewFlagSet("pins", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s pins list\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s pins remove <event-id>...\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Lists or removes pinned events, kept in pins.json next to the config file")
+		fmt.Fprintln(fs.Output(), "(or $GITHUB_ACTIVITY_PINS).")
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	path := pinsPath()
+	pins, err := loadPins(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	switch {
+	case fs.NArg() == 1 && fs.Arg(0) == "list":
+		printPins(os.Stdout, pins)
+		return 0
+	case fs.NArg() > 1 && fs.Arg(0) == "remove":
+		for _, id := range fs.Args()[1:] {
+			i := slices.IndexFunc(pins, func(p Pin) bool { return p.ID == id })
+			if i < 0 {
+				fmt.Fprintf(os.Stderr, "Error: event %s is not pinned\n", id)
+				return 1
+			}
+			pins = slices.Delete(pins, i, i+1)
+		}
+		if err := savePins(path, pins); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+	fs.Usage()
+	return 2
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddPin(t *testing.T) {
+	pinClock(t)
+	path := filepath.Join(t.TempDir(), "pins.json")
+	e := testEntry("WatchEvent", "acme/app", "Starred acme/app")
+	e.Event.ID = "7"
+	for range 2 { // pinning twice keeps one pin
+		if err := addPin(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pins, err := loadPins(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Pin{ID: "7", User: "alice", Summary: "Starred acme/app", URL: webURL + "/acme/app", Created: "2024-05-01T12:00:00Z", Pinned: "2024-05-04T09:00:00Z"}
+	if len(pins) != 1 || pins[0] != want {
+		t.Errorf("got %+v", pins)
+	}
+	if err := addPin(path, testEntry("WatchEvent", "acme/app", "no id")); err == nil {
+		t.Error("event without ID: want error")
+	}
+}
+
+func TestPrintPins(t *testing.T) {
+	var b strings.Builder
+	printPins(&b, []Pin{
+		{ID: "1", User: "alice", Summary: "first", URL: "u1"},
+		{ID: "2", User: "bob", Summary: "second", URL: "u2"},
+	})
+	if got, want := b.String(), "2  bob: second\n    u2\n1  alice: first\n    u1\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFindEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"5","type":"WatchEvent","created_at":"2024-05-04T08:00:00Z","repo":{"name":"a/b"},"payload":{"action":"started"}}]`))
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	e, err := findEvent(context.Background(), "alice", "5")
+	if err != nil || e.Summary != "Starred a/b" || e.User != "alice" {
+		t.Errorf("got %+v, %v", e, err)
+	}
+	if _, err := findEvent(context.Background(), "alice", "6"); err == nil {
+		t.Error("missing event: want error")
+	}
+}
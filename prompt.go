@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// promptData is what prompt-data prints for shell prompts to format.
+type promptData struct {
+	User         string `json:"user"`
+	Today        int    `json:"today"`                    // events since local midnight
+	LastEventAge *int64 `json:"last_event_age,omitempty"` // seconds; absent with no events
+	Stale        bool   `json:"stale"`                    // older than --cache-ttl, or not cached yet
+}
+
+// promptDataOf summarizes a cached feed. ok is false when nothing is cached.
+func promptDataOf(user string, c statusCache, ok bool, ttl time.Duration) promptData {
+	d := promptData{User: user, Stale: !ok || now().Sub(c.Fetched) >= ttl}
+	start, _, _ := periodBounds("day", now())
+	for _, ev := range c.Events {
+		if !ev.CreatedAt.Before(start) {
+			d.Today++
+		}
+	}
+	if len(c.Events) > 0 {
+		age := int64(now().Sub(c.Events[0].CreatedAt) / time.Second)
+		d.LastEventAge = &age
+	}
+	return d
+}
+
+// refreshLockAge is how long a background refresh may hold its lock. A
+// lock older than this belongs to a refresh that died, and is taken over.
+const refreshLockAge = 2 * time.Minute
+
+// claimRefresh takes the lock file next to the cache at path, reporting
+// false when another refresh holds it, so a burst of prompts over a stale
+// cache starts one fetch rather than one each.
+func claimRefresh(path string) bool {
+	lock := path + ".refreshing"
+	_ = os.MkdirAll(filepath.Dir(lock), 0o700)
+	for range 2 {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			return true
+		}
+		fi, statErr := os.Stat(lock)
+		if !errors.Is(err, fs.ErrExist) || statErr != nil || now().Sub(fi.ModTime()) < refreshLockAge {
+			return false
+		}
+		_ = os.Remove(lock) // abandoned
+	}
+	return false
+}
+
+// releaseRefresh drops the lock claimRefresh took.
+func releaseRefresh(path string) { _ = os.Remove(path + ".refreshing") }
+
+// startRefresh refetches a user's feed in a detached copy of this program,
+// so the prompt never waits on the network. It does nothing while another
+// refresh of the same cache is running.
+func startRefresh(user, path string) error {
+	if !claimRefresh(path) {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, "prompt-data", "--refresh", "--owns-lock", user)
+	if err := cmd.Start(); err != nil {
+		releaseRefresh(path)
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// refreshPromptCache fetches user's feed into the cache at path. ownsLock
+// says this is the background refresh holding the lock, which it releases
+// when done; a refresh run by hand leaves another's lock alone.
+func refreshPromptCache(ctx context.Context, user, path string, ownsLock bool) error {
+	if ownsLock {
+		defer releaseRefresh(path)
+	}
+	events, err := fetchToday(ctx, user)
+	if err != nil {
+		return err
+	}
+	writeStatusCache(path, events)
+	return nil
+}
+
+func runPromptData(args []string) int {
+	fs := flag.NewFlagSet("prompt-data", flag.ExitOnError)
+	ttl := fs.Duration("cache-ttl", 5*time.Minute, "Refresh the cached feed in the background once it is older than this.")
+	refresh := fs.Bool("refresh", false, "Fetch the feed now and update the cache before printing (what the background refresh runs).")
+	ownsLock := fs.Bool("owns-lock", false, "With --refresh: release the refresh lock when done. Only for the background refresh, which the lock was taken for.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s prompt-data [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints a small JSON object for shell prompts such as Starship:")
+		fmt.Fprintln(fs.Output(), `  {"user":"alice","today":4,"last_event_age":7200,"stale":false}`)
+		fmt.Fprintln(fs.Output(), "Only the cache shared with status is read, so it answers in milliseconds; a")
+		fmt.Fprintln(fs.Output(), "stale or missing cache is refreshed in the background for the next prompt.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	user := fs.Arg(0)
+	path := statusCachePath(user)
+	if *refresh {
+		if err := refreshPromptCache(context.Background(), user, path, *ownsLock); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+	}
+	c, ok := readStatusCache(path)
+	d := promptDataOf(user, c, ok, *ttl)
+	if d.Stale && !*refresh {
+		if err := startRefresh(user, path); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: refreshing in the background:", err)
+		}
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(d); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
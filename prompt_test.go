@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromptDataOf(t *testing.T) {
+	pinClock(t) // Saturday 2024-05-04 09:00 UTC
+	c := statusCache{
+		Fetched: now().Add(-time.Minute),
+		Events: []Event{
+			{Type: "PushEvent", CreatedAt: now().Add(-2 * time.Hour)},
+			{Type: "WatchEvent", CreatedAt: now().Add(-5 * time.Hour)},
+			{Type: "PushEvent", CreatedAt: now().Add(-10 * time.Hour)}, // yesterday
+		},
+	}
+	d := promptDataOf("alice", c, true, 5*time.Minute)
+	if d.User != "alice" || d.Today != 2 || d.LastEventAge == nil || *d.LastEventAge != 7200 || d.Stale {
+		t.Errorf("got %+v", d)
+	}
+	if d := promptDataOf("alice", c, true, 30*time.Second); !d.Stale {
+		t.Errorf("older than ttl: got %+v", d)
+	}
+	if d := promptDataOf("alice", statusCache{}, false, 5*time.Minute); !d.Stale || d.Today != 0 || d.LastEventAge != nil {
+		t.Errorf("not cached: got %+v", d)
+	}
+}
+
+func TestClaimRefresh(t *testing.T) {
+	pinClock(t)
+	path := filepath.Join(t.TempDir(), "cache", "status-alice.json")
+	if !claimRefresh(path) {
+		t.Fatal("first claim refused")
+	}
+	if claimRefresh(path) {
+		t.Error("second claim granted while the first refresh runs")
+	}
+	releaseRefresh(path)
+	if !claimRefresh(path) {
+		t.Error("claim refused after release")
+	}
+	// A lock left by a refresh that died is taken over.
+	old := now().Add(-refreshLockAge - time.Second)
+	if err := os.Chtimes(path+".refreshing", old, old); err != nil {
+		t.Fatal(err)
+	}
+	if !claimRefresh(path) {
+		t.Error("abandoned lock not taken over")
+	}
+}
+
+func TestRefreshPromptCacheLock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/missing/") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+	path := filepath.Join(t.TempDir(), "status-alice.json")
+	locked := func() bool {
+		_, err := os.Stat(path + ".refreshing")
+		return err == nil
+	}
+
+	// A refresh run by hand leaves the background refresh's lock alone.
+	if !claimRefresh(path) {
+		t.Fatal("claim refused")
+	}
+	if err := refreshPromptCache(context.Background(), "alice", path, false); err != nil {
+		t.Fatal(err)
+	}
+	if !locked() {
+		t.Error("manual refresh released a lock it didn't take")
+	}
+	if _, ok := readStatusCache(path); !ok {
+		t.Error("cache not written")
+	}
+
+	// The background refresh releases its lock, even when it fails.
+	apiURL = srv.URL + "/missing"
+	if err := refreshPromptCache(context.Background(), "alice", path, true); err == nil {
+		t.Error("want an error from the missing endpoint")
+	}
+	if locked() {
+		t.Error("background refresh kept its lock")
+	}
+}
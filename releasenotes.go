@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mergedPR is a pull request for the release notes, from its merge event
+// and, when the lookup works, the pulls API.
+type mergedPR struct {
+	Number   int
+	Title    string
+	Author   string
+	Labels   []string
+	URL      string
+	MergedAt time.Time
+}
+
+// releaseCategories are the release notes' sections, in order. A pull
+// request goes in the first whose labels or Conventional Commits types
+// match.
+var releaseCategories = []struct {
+	Title  string
+	Labels []string // label substrings, lowercase
+	Types  []string // Conventional Commits types of the title
+}{
+	{"⚠️ Breaking changes", []string{"breaking"}, nil},
+	{"Features", []string{"feature", "enhancement"}, []string{"feat"}},
+	{"Bug fixes", []string{"bug", "fix"}, []string{"fix"}},
+	{"Performance", []string{"performance", "perf"}, []string{"perf"}},
+	{"Documentation", []string{"doc"}, []string{"docs"}},
+	{"Dependencies", []string{"dependencies", "deps"}, []string{"deps"}},
+	{"Maintenance", []string{"chore", "ci", "refactor", "test", "build"}, []string{"chore", "ci", "refactor", "test", "build", "style"}},
+}
+
+// releaseCategory is the title of the section pr belongs in.
+func releaseCategory(pr mergedPR) string {
+	if m := conventionalPrefix.FindString(pr.Title); strings.Contains(m, "!:") {
+		return releaseCategories[0].Title
+	}
+	typ := commitType(pr.Title)
+	for _, c := range releaseCategories {
+		for _, l := range pr.Labels {
+			for _, want := range c.Labels {
+				if strings.Contains(strings.ToLower(l), want) {
+					return c.Title
+				}
+			}
+		}
+		if slices.Contains(c.Types, typ) {
+			return c.Title
+		}
+	}
+	return "Other changes"
+}
+
+// mergedPRs lists the pull requests merged in events from since on, oldest
+// first.
+func mergedPRs(events []Event, since time.Time) []mergedPR {
+	var out []mergedPR
+	seen := map[int]bool{}
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if !prMerged(ev) || ev.CreatedAt.Before(since) {
+			continue
+		}
+		d := detailsOf(ev)
+		if seen[d.Number] {
+			continue
+		}
+		seen[d.Number] = true
+		out = append(out, mergedPR{
+			Number:   d.Number,
+			Title:    d.Title,
+			Author:   ev.Actor.Login,
+			URL:      entityURL(ev),
+			MergedAt: ev.CreatedAt,
+		})
+	}
+	return out
+}
+
+// enrichPR fills in pr's title, author, and labels from the pulls API; the
+// event payload names who merged it, not who wrote it, and has no labels.
+func enrichPR(ctx context.Context, repo string, pr *mergedPR) error {
+	var p struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := getJSON(ctx, apiURL+"/repos/"+repo+"/pulls/"+strconv.Itoa(pr.Number), &p); err != nil {
+		return err
+	}
+	if p.Title != "" {
+		pr.Title = p.Title
+	}
+	if p.HTMLURL != "" {
+		pr.URL = p.HTMLURL
+	}
+	if p.User.Login != "" {
+		pr.Author = p.User.Login
+	}
+	pr.Labels = nil
+	for _, l := range p.Labels {
+		pr.Labels = append(pr.Labels, l.Name)
+	}
+	return nil
+}
+
+// tagDate is when tag's commit was committed.
+func tagDate(ctx context.Context, repo, tag string) (time.Time, error) {
+	var c struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	err := getJSON(ctx, apiURL+"/repos/"+repo+"/commits/"+url.PathEscape(tag), &c)
+	if errors.Is(err, errNotFound) {
+		return time.Time{}, fmt.Errorf("no tag or commit %q in %s", tag, repo)
+	}
+	return c.Commit.Committer.Date, err
+}
+
+// writeReleaseNotes writes a Markdown changelog draft of prs, grouped into
+// releaseCategories. tag, if set, is the previous release, for the compare
+// link.
+func writeReleaseNotes(w io.Writer, repo, tag string, prs []mergedPR) {
+	fmt.Fprintln(w, "## What's Changed")
+	if len(prs) == 0 {
+		fmt.Fprintln(w, "\nNo pull requests were merged.")
+	}
+	sections := map[string][]mergedPR{}
+	for _, pr := range prs {
+		c := releaseCategory(pr)
+		sections[c] = append(sections[c], pr)
+	}
+	titles := []string{}
+	for _, c := range releaseCategories {
+		titles = append(titles, c.Title)
+	}
+	for _, title := range append(titles, "Other changes") {
+		if len(sections[title]) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n### %s\n", title)
+		for _, pr := range sections[title] {
+			fmt.Fprintf(w, "- %s by @%s in [#%d](%s)\n", pr.Title, pr.Author, pr.Number, pr.URL)
+		}
+	}
+	if tag != "" {
+		fmt.Fprintf(w, "\n**Full Changelog**: %s/compare/%s...HEAD\n", repoURL(repo), tag)
+	}
+}
+
+func runReleaseNotes(args []string) int {
+	fs := flag.NewFlagSet("release-notes", flag.ExitOnError)
+	sinceArg := fs.String("since", "", "Previous release: a tag, or a date or time as for the main command's --since (default: the latest tag).")
+	noEnrich := fs.Bool("no-enrich", false, "Don't look up each pull request's labels and author; use only what the event feed says.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s release-notes [options] <owner>/<repo>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Drafts Markdown release notes from the pull requests merged since the previous")
+		fmt.Fprintln(fs.Output(), "release, grouped by their labels or Conventional Commits titles. Only merges still")
+		fmt.Fprintln(fs.Output(), "in the repository's event feed (the last 300 events, up to 90 days) are found.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	repo, err := parseRepo(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	tag := *sinceArg
+	if tag == "" {
+		tags, err := fetchTags(ctx, repo, 1)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		if len(tags) > 0 {
+			tag = tags[0].Name
+		}
+	}
+	since, err := parseTimeArg(tag)
+	if err == nil {
+		tag = ""
+	} else if since, err = tagDate(ctx, repo, tag); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+
+	events, err := fetchRepoEventPages(ctx, repo, pageOptions{PerPage: 100, Since: since})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if n := len(events); n > 0 && events[n-1].CreatedAt.After(since) && !since.IsZero() {
+		fmt.Fprintf(warnings, "Warning: the event feed only goes back to %s; pull requests merged before then are missing\n", events[n-1].CreatedAt.Local().Format("Jan 2, 2006"))
+	}
+	prs := mergedPRs(events, since)
+	if !*noEnrich {
+		for i := range prs {
+			if err := enrichPR(ctx, repo, &prs[i]); err != nil {
+				fmt.Fprintf(warnings, "Warning: pull request #%d: %v\n", prs[i].Number, err)
+			}
+		}
+	}
+	writeReleaseNotes(os.Stdout, repo, tag, prs)
+	return 0
+}
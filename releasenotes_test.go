@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReleaseCategory(t *testing.T) {
+	tests := []struct {
+		title  string
+		labels []string
+		want   string
+	}{
+		{"feat(api)!: drop v1 endpoints", nil, "⚠️ Breaking changes"},
+		{"Remove the old flag", []string{"Breaking Change"}, "⚠️ Breaking changes"},
+		{"Add dark mode", []string{"enhancement"}, "Features"},
+		{"feat: add dark mode", nil, "Features"},
+		{"Crash on empty feed", []string{"type: bug"}, "Bug fixes"},
+		{"Bump x/net", []string{"dependencies"}, "Dependencies"},
+		{"ci: cache modules", nil, "Maintenance"},
+		{"Tweak things", nil, "Other changes"},
+	}
+	for _, tt := range tests {
+		if got := releaseCategory(mergedPR{Title: tt.title, Labels: tt.labels}); got != tt.want {
+			t.Errorf("%q %v: got %q, want %q", tt.title, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func mergeEvent(number int, title string, at time.Time, merged bool) Event {
+	ev := Event{Type: "PullRequestEvent", CreatedAt: at}
+	ev.Actor.Login = "maintainer"
+	ev.Repo.Name = "acme/app"
+	ev.Payload = mustRaw(map[string]any{"action": "closed", "pull_request": map[string]any{"number": number, "title": title, "merged": merged}})
+	return ev
+}
+
+func TestMergedPRs(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC) }
+	events := []Event{ // newest first
+		mergeEvent(3, "fix: crash", day(4), true),
+		mergeEvent(2, "Abandoned", day(3), false),
+		mergeEvent(1, "feat: old", day(1), true),
+	}
+	got := mergedPRs(events, day(2))
+	if len(got) != 1 || got[0].Number != 3 || got[0].Author != "maintainer" || got[0].URL != webURL+"/acme/app/pull/3" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestEnrichPRAndTagDate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/app/pulls/3":
+			w.Write([]byte(`{"title": "fix: crash on empty feed", "html_url": "https://github.com/acme/app/pull/3", "user": {"login": "alice"}, "labels": [{"name": "bug"}]}`))
+		case "/repos/acme/app/commits/v1.0.0":
+			w.Write([]byte(`{"commit": {"committer": {"date": "2024-05-02T10:00:00Z"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	pr := mergedPR{Number: 3, Title: "fix: crash", Author: "maintainer"}
+	if err := enrichPR(context.Background(), "acme/app", &pr); err != nil {
+		t.Fatal(err)
+	}
+	if pr.Title != "fix: crash on empty feed" || pr.Author != "alice" || len(pr.Labels) != 1 || pr.Labels[0] != "bug" {
+		t.Errorf("got %+v", pr)
+	}
+	at, err := tagDate(context.Background(), "acme/app", "v1.0.0")
+	if err != nil || !at.Equal(time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("tagDate: %v, %v", at, err)
+	}
+	if _, err := tagDate(context.Background(), "acme/app", "v9"); err == nil || !strings.Contains(err.Error(), `no tag or commit "v9"`) {
+		t.Errorf("missing tag: %v", err)
+	}
+}
+
+func TestWriteReleaseNotes(t *testing.T) {
+	prs := []mergedPR{
+		{Number: 3, Title: "fix: crash", Author: "alice", URL: "https://github.com/acme/app/pull/3"},
+		{Number: 4, Title: "Add dark mode", Author: "bob", Labels: []string{"enhancement"}, URL: "https://github.com/acme/app/pull/4"},
+		{Number: 5, Title: "Tidy up", Author: "alice", URL: "https://github.com/acme/app/pull/5"},
+	}
+	var b strings.Builder
+	writeReleaseNotes(&b, "acme/app", "v1.0.0", prs)
+	want := `## What's Changed
+
+### Features
+- Add dark mode by @bob in [#4](https://github.com/acme/app/pull/4)
+
+### Bug fixes
+- fix: crash by @alice in [#3](https://github.com/acme/app/pull/3)
+
+### Other changes
+- Tidy up by @alice in [#5](https://github.com/acme/app/pull/5)
+
+**Full Changelog**: ` + webURL + `/acme/app/compare/v1.0.0...HEAD
+`
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
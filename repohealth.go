@@ -0,0 +1,395 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// healthItem is an issue or pull request opened in a repo-health window.
+type healthItem struct {
+	Number    int
+	PR        bool
+	Author    string
+	Created   time.Time
+	Comments  int
+	Response  time.Time // first reply by someone other than the author; zero if none
+	Responder string
+}
+
+// healthReply is a comment or review on an issue or pull request.
+type healthReply struct {
+	Actor string
+	At    time.Time
+}
+
+// fetchHealthItems lists the issues and pull requests opened in repo from
+// since on, newest first.
+func fetchHealthItems(ctx context.Context, repo string, since time.Time) ([]healthItem, error) {
+	var items []healthItem
+	endpoint := apiURL + "/repos/" + repo + "/issues?state=all&sort=created&direction=desc&per_page=100"
+	for endpoint != "" {
+		var page []struct {
+			Number int `json:"number"`
+			User   struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			CreatedAt   time.Time `json:"created_at"`
+			Comments    int       `json:"comments"`
+			PullRequest *struct{} `json:"pull_request"`
+		}
+		next, err := getJSONPage(ctx, endpoint, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range page {
+			if it.CreatedAt.Before(since) {
+				return items, nil
+			}
+			items = append(items, healthItem{Number: it.Number, PR: it.PullRequest != nil, Author: it.User.Login, Created: it.CreatedAt, Comments: it.Comments})
+		}
+		endpoint = next
+	}
+	return items, nil
+}
+
+// eventReplies collects the comments and reviews in events by issue or pull
+// request number.
+func eventReplies(events []Event) map[int][]healthReply {
+	replies := map[int][]healthReply{}
+	for _, ev := range events {
+		switch ev.Type {
+		case "IssueCommentEvent", "PullRequestReviewEvent", "PullRequestReviewCommentEvent":
+			if n := detailsOf(ev).Number; n > 0 {
+				replies[n] = append(replies[n], healthReply{ev.Actor.Login, ev.CreatedAt})
+			}
+		}
+	}
+	return replies
+}
+
+// fetchReplies looks up the comments, and for pull requests the reviews, on
+// an item the event feed has no reply to.
+func fetchReplies(ctx context.Context, repo string, it healthItem) ([]healthReply, error) {
+	var replies []healthReply
+	n := strconv.Itoa(it.Number)
+	if it.Comments > 0 {
+		var comments []struct {
+			User struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if err := getJSON(ctx, apiURL+"/repos/"+repo+"/issues/"+n+"/comments?per_page=100", &comments); err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			replies = append(replies, healthReply{c.User.Login, c.CreatedAt})
+		}
+	}
+	if it.PR {
+		var reviews []struct {
+			User struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			SubmittedAt time.Time `json:"submitted_at"`
+		}
+		if err := getJSON(ctx, apiURL+"/repos/"+repo+"/pulls/"+n+"/reviews?per_page=100", &reviews); err != nil {
+			return nil, err
+		}
+		for _, r := range reviews {
+			replies = append(replies, healthReply{r.User.Login, r.SubmittedAt})
+		}
+	}
+	return replies, nil
+}
+
+// firstResponse sets it.Response to the earliest reply from a person other
+// than its author. Bots' automatic replies don't count.
+func (it *healthItem) firstResponse(replies []healthReply) {
+	for _, r := range replies {
+		if r.Actor == it.Author || isBotLogin(r.Actor) || r.At.Before(it.Created) {
+			continue
+		}
+		if it.Response.IsZero() || r.At.Before(it.Response) {
+			it.Response, it.Responder = r.At, r.Actor
+		}
+	}
+}
+
+// findResponses fills in each item's first response from events, looking
+// replies up in the API for items the feed shows none for but that have
+// comments, or are pull requests older than the feed (whose reviews the
+// comment count leaves out).
+func findResponses(ctx context.Context, repo string, items []healthItem, events []Event) {
+	replies := eventReplies(events)
+	var feedStart time.Time
+	if len(events) > 0 {
+		feedStart = events[len(events)-1].CreatedAt
+	}
+	for i := range items {
+		it := &items[i]
+		it.firstResponse(replies[it.Number])
+		if !it.Response.IsZero() || (it.Comments == 0 && !(it.PR && it.Created.Before(feedStart))) {
+			continue
+		}
+		more, err := fetchReplies(ctx, repo, *it)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: replies to #%d: %v\n", it.Number, err)
+			continue
+		}
+		it.firstResponse(more)
+	}
+}
+
+// median is the middle of ds, which it sorts.
+func median(ds []time.Duration) time.Duration {
+	slices.Sort(ds)
+	n := len(ds)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 0 {
+		return (ds[n/2-1] + ds[n/2]) / 2
+	}
+	return ds[n/2]
+}
+
+// healthWeek is one week's distinct contributors.
+type healthWeek struct {
+	Start  time.Time
+	People map[string]bool
+}
+
+// contributorWeeks counts the distinct people who contributed to the
+// repository (events in learningKinds, plus opening issues and pull requests)
+// each week from since through the current one. Bots aren't counted.
+func contributorWeeks(since time.Time, events []Event, items []healthItem) []healthWeek {
+	start, _, _ := periodBounds("week", since)
+	current, _, _ := periodBounds("week", now())
+	var weeks []healthWeek
+	for w := start; !w.After(current); w = w.AddDate(0, 0, 7) {
+		weeks = append(weeks, healthWeek{Start: w, People: map[string]bool{}})
+	}
+	add := func(login string, t time.Time) {
+		if isBotLogin(login) || t.Before(start) {
+			return
+		}
+		for i := len(weeks) - 1; i >= 0; i-- {
+			if !t.Before(weeks[i].Start) {
+				weeks[i].People[login] = true
+				return
+			}
+		}
+	}
+	for _, ev := range events {
+		if _, ok := learningKinds[ev.Type]; ok {
+			add(ev.Actor.Login, ev.CreatedAt)
+		}
+	}
+	for _, it := range items {
+		add(it.Author, it.Created)
+	}
+	return weeks
+}
+
+// contributorTrend compares the average weekly contributors in the first and
+// second halves of the complete weeks (all but the current one).
+func contributorTrend(weeks []healthWeek) (trend string, before, after float64) {
+	done := weeks[:max(len(weeks)-1, 0)]
+	if len(done) < 4 {
+		return "", 0, 0
+	}
+	avg := func(ws []healthWeek) float64 {
+		total := 0
+		for _, w := range ws {
+			total += len(w.People)
+		}
+		return float64(total) / float64(len(ws))
+	}
+	half := len(done) / 2
+	before, after = avg(done[:half]), avg(done[half:])
+	switch {
+	case after > before*1.2:
+		trend = "growing"
+	case after < before*0.8:
+		trend = "shrinking"
+	default:
+		trend = "steady"
+	}
+	return trend, before, after
+}
+
+// busFactorHints points out where the repository depends on few people: who
+// makes most of the commits, merges most pull requests, and answers most new
+// issues and pull requests.
+func busFactorHints(events []Event, items []healthItem) []string {
+	var hints []string
+	commits := map[string]int{}
+	total := 0
+	var mergers []string
+	for _, ev := range events {
+		if isBotLogin(ev.Actor.Login) {
+			continue
+		}
+		if ev.Type == "PushEvent" {
+			n := pushSize(ev)
+			commits[ev.Actor.Login] += n
+			total += n
+		}
+		if prMerged(ev) {
+			mergers = append(mergers, ev.Actor.Login)
+		}
+	}
+	if total > 0 {
+		var pushers []countItem
+		for login, n := range commits {
+			pushers = append(pushers, countItem{login, n})
+		}
+		slices.SortFunc(pushers, func(a, b countItem) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Key, b.Key))
+		})
+		var names []string
+		sum := 0
+		for _, p := range pushers {
+			names = append(names, fmt.Sprintf("%s (%d%%)", p.Key, percent(p.Count, total)))
+			if sum += p.Count; sum*2 >= total {
+				break
+			}
+		}
+		if len(names) == 1 {
+			hints = append(hints, fmt.Sprintf("Bus factor 1: %s pushed %d%% of the commits.", pushers[0].Key, percent(pushers[0].Count, total)))
+		} else {
+			hints = append(hints, fmt.Sprintf("Bus factor %d: half the commits came from %s.", len(names), strings.Join(names, ", ")))
+		}
+	}
+	if top := rank(mergers); len(mergers) >= 4 && top[0].Count*4 >= len(mergers)*3 {
+		hints = append(hints, fmt.Sprintf("%s merged %d of %d pull requests.", top[0].Key, top[0].Count, len(mergers)))
+	}
+	var responders []string
+	for _, it := range items {
+		if it.Responder != "" {
+			responders = append(responders, it.Responder)
+		}
+	}
+	if top := rank(responders); len(responders) >= 4 && top[0].Count*4 >= len(responders)*3 {
+		hints = append(hints, fmt.Sprintf("%s gave the first response on %d of %d issues and pull requests.", top[0].Key, top[0].Count, len(responders)))
+	}
+	return hints
+}
+
+// responsiveness summarizes first responses to items that are, or aren't,
+// pull requests.
+func responsiveness(items []healthItem, prs bool) string {
+	var waits []time.Duration
+	opened, unanswered := 0, 0
+	for _, it := range items {
+		if it.PR != prs {
+			continue
+		}
+		opened++
+		if it.Response.IsZero() {
+			unanswered++
+			continue
+		}
+		waits = append(waits, it.Response.Sub(it.Created))
+	}
+	s := fmt.Sprintf("%d opened", opened)
+	if len(waits) > 0 {
+		s += ", median first response " + formatLeft(median(waits))
+	}
+	if unanswered > 0 {
+		s += fmt.Sprintf(", %d without a response", unanswered)
+	}
+	return s
+}
+
+func printRepoHealth(w io.Writer, repo string, since time.Time, events []Event, items []healthItem) {
+	fmt.Fprintf(w, "Community health of %s since %s\n", repo, since.Local().Format("Jan 2, 2006"))
+
+	fmt.Fprintln(w, "\nResponsiveness:")
+	fmt.Fprintln(w, "  Issues:        ", responsiveness(items, false))
+	fmt.Fprintln(w, "  Pull requests: ", responsiveness(items, true))
+
+	fmt.Fprintln(w, "\nContributors, week by week:")
+	weeks := contributorWeeks(since, events, items)
+	for _, wk := range weeks {
+		fmt.Fprintf(w, "  %s  %3d  %s\n", wk.Start.Format("Jan 02"), len(wk.People), strings.Repeat("█", len(wk.People)))
+	}
+	if trend, before, after := contributorTrend(weeks); trend != "" {
+		fmt.Fprintf(w, "  Trend: %s (%.1f a week, then %.1f)\n", trend, before, after)
+	}
+
+	fmt.Fprintln(w, "\nBus factor:")
+	hints := busFactorHints(events, items)
+	if len(hints) == 0 {
+		fmt.Fprintln(w, "  No commits or merges in the window to judge by.")
+	}
+	for _, h := range hints {
+		fmt.Fprintln(w, "  "+h)
+	}
+}
+
+func runRepoHealth(args []string) int {
+	fs := flag.NewFlagSet("repo-health", flag.ExitOnError)
+	sinceArg := fs.String("since", "2160h", "Start of the window: a date, an RFC 3339 time, or a duration before now.")
+	tz := fs.String("tz", "", "Time zone that weeks start in (default: local).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s repo-health [options] <owner>/<repo>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Reports a repository's community health: the median time until new issues and")
+		fmt.Fprintln(fs.Output(), "pull requests get a first response, the number of contributors week by week, and")
+		fmt.Fprintln(fs.Output(), "hints of a low bus factor. Commits and merges come from the repository's event")
+		fmt.Fprintln(fs.Output(), "feed, which holds the last 300 events (up to 90 days).")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	repo, err := parseRepo(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	since, err := parseTimeArg(*sinceArg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	events, err := fetchRepoEventPages(ctx, repo, pageOptions{PerPage: 100, Since: since})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	for len(events) > 0 && events[len(events)-1].CreatedAt.Before(since) {
+		events = events[:len(events)-1]
+	}
+	if n := len(events); n > 0 && events[n-1].CreatedAt.After(since) {
+		fmt.Fprintf(warnings, "Warning: the event feed only goes back to %s; commits and merges before then aren't counted\n", events[n-1].CreatedAt.Local().Format("Jan 2, 2006"))
+	}
+	items, err := fetchHealthItems(ctx, repo, since)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	findResponses(ctx, repo, items, events)
+	printRepoHealth(os.Stdout, repo, since, events, items)
+	return 0
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// healthEvent is an event in acme/app by actor at May day, hour:00 UTC.
+func healthEvent(typ, actor string, day, hour int, payload any) Event {
+	e := Event{Type: typ, CreatedAt: time.Date(2024, 5, day, hour, 0, 0, 0, time.UTC), Payload: mustRaw(payload)}
+	e.Actor.Login = actor
+	e.Repo.Name = "acme/app"
+	return e
+}
+
+func TestFindResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/app/issues/2/comments":
+			fmt.Fprint(w, `[{"user":{"login":"ci[bot]"},"created_at":"2024-05-02T10:05:00Z"},{"user":{"login":"bob"},"created_at":"2024-05-03T10:00:00Z"}]`)
+		case "/repos/acme/app/pulls/2/reviews":
+			fmt.Fprint(w, `[]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	day := func(d, h int) time.Time { return time.Date(2024, 5, d, h, 0, 0, 0, time.UTC) }
+	items := []healthItem{
+		{Number: 3, Author: "erin", Created: day(3, 9)},
+		{Number: 2, PR: true, Author: "dave", Created: day(2, 10), Comments: 2},
+		{Number: 1, Author: "carol", Created: day(1, 10), Comments: 2},
+	}
+	comment := map[string]any{"action": "created", "issue": map[string]any{"number": 1}}
+	events := []Event{
+		healthEvent("IssueCommentEvent", "alice", 1, 13, comment),
+		healthEvent("IssueCommentEvent", "carol", 1, 11, comment),
+	}
+	findResponses(context.Background(), "acme/app", items, events)
+
+	if it := items[2]; it.Responder != "alice" || it.Response.Sub(it.Created) != 3*time.Hour {
+		t.Errorf("#1 answered by %q at %v", it.Responder, it.Response)
+	}
+	if it := items[1]; it.Responder != "bob" {
+		t.Errorf("#2 answered by %q", it.Responder)
+	}
+	if got, want := responsiveness(items, false), "2 opened, median first response 3 hours, 1 without a response"; got != want {
+		t.Errorf("issues: got %q, want %q", got, want)
+	}
+	if got, want := responsiveness(items, true), "1 opened, median first response 24 hours"; got != want {
+		t.Errorf("pull requests: got %q, want %q", got, want)
+	}
+}
+
+func TestContributorTrend(t *testing.T) {
+	pinClock(t)
+	var events []Event
+	for week, people := range []int{1, 1, 3, 3} {
+		for p := range people {
+			events = append(events, healthEvent("PushEvent", fmt.Sprintf("dev%d", p), 6+7*week, 12, PushPayload{Size: 1}))
+		}
+	}
+	// Events are in May 6-27, so move the clock to the week after.
+	now = func() time.Time { return time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC) }
+	weeks := contributorWeeks(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), events, nil)
+	if len(weeks) != 5 || len(weeks[2].People) != 3 {
+		t.Fatalf("weeks: %+v", weeks)
+	}
+	if trend, before, after := contributorTrend(weeks); trend != "growing" || before != 1 || after != 3 {
+		t.Errorf("got %s (%v, then %v)", trend, before, after)
+	}
+}
+
+func TestBusFactorHints(t *testing.T) {
+	merged := map[string]any{"action": "closed", "pull_request": map[string]any{"number": 1, "merged": true}}
+	events := []Event{
+		healthEvent("PushEvent", "alice", 1, 9, PushPayload{Size: 8}),
+		healthEvent("PushEvent", "bob", 1, 10, PushPayload{Size: 2}),
+		healthEvent("PushEvent", "dependabot[bot]", 1, 11, PushPayload{Size: 50}),
+	}
+	for range 4 {
+		events = append(events, healthEvent("PullRequestEvent", "alice", 2, 9, merged))
+	}
+	want := []string{
+		"Bus factor 1: alice pushed 80% of the commits.",
+		"alice merged 4 of 4 pull requests.",
+	}
+	if got := busFactorHints(events, nil); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	events = []Event{
+		healthEvent("PushEvent", "alice", 1, 9, PushPayload{Size: 4}),
+		healthEvent("PushEvent", "bob", 1, 10, PushPayload{Size: 3}),
+		healthEvent("PushEvent", "carol", 1, 11, PushPayload{Size: 3}),
+	}
+	if got := busFactorHints(events, nil); len(got) != 1 || got[0] != "Bus factor 2: half the commits came from alice (40%), bob (30%)." {
+		t.Errorf("got %q", got)
+	}
+}
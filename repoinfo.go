@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+)
+
+// repoInfo is the repository metadata filters and reports need.
+type repoInfo struct {
+	Fork          bool   `json:"fork"`
+	Language      string `json:"language"` // primary language, empty if GitHub found none
+	DefaultBranch string `json:"default_branch"`
+}
+
+// repoInfoCache holds metadata used during this run, so each repository
+// is looked up once however many events it has.
+var repoInfoCache = struct {
+	sync.Mutex
+	m map[string]repoInfo
+}{m: map[string]repoInfo{}}
+
+// lookupRepo fetches a repository's metadata, reusing what this run or,
+// within the metadata TTL, an earlier one looked up.
+func lookupRepo(ctx context.Context, repo string) (repoInfo, error) {
+	repoInfoCache.Lock()
+	info, ok := repoInfoCache.m[repo]
+	repoInfoCache.Unlock()
+	if ok {
+		return info, nil
+	}
+	endpoint := apiURL + "/repos/" + repo
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return info, err
+	}
+	key := cacheKey(endpoint, requestCredential(ctx, u).Token)
+	if !metaCache.get(key, &info) {
+		err := getJSON(ctx, endpoint, &info)
+		if errors.Is(err, errNotFound) {
+			return info, errors.New("repository not found")
+		}
+		if err != nil {
+			return info, err
+		}
+		metaCache.put(key, info)
+	}
+	repoInfoCache.Lock()
+	repoInfoCache.m[repo] = info
+	repoInfoCache.Unlock()
+	return info, nil
+}
+
+// forkCheck keeps events on forks (forks true) or on repositories that
+// aren't forks (forks false).
+func forkCheck(forks bool) eventCheck {
+	return func(ctx context.Context, ev Event) (bool, error) {
+		info, err := lookupRepo(ctx, ev.Repo.Name)
+		if err != nil {
+			return false, err
+		}
+		return info.Fork == forks, nil
+	}
+}
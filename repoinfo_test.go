@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForkCheck(t *testing.T) {
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/repos/alice/fork":
+			_, _ = w.Write([]byte(`{"fork": true}`))
+		case "/repos/alice/own":
+			_, _ = w.Write([]byte(`{"fork": false}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+	repoInfoCache.m = map[string]repoInfo{}
+
+	ev := func(repo string) Event {
+		e := Event{Type: "WatchEvent"}
+		e.Repo.Name = repo
+		return e
+	}
+	events := []Event{ev("alice/fork"), ev("alice/own"), ev("alice/fork"), ev("alice/gone"), ev("alice/own")}
+	forks := selectChecked(context.Background(), events, filters{}, []eventCheck{forkCheck(true)}, 10)
+	own := selectChecked(context.Background(), events, filters{}, []eventCheck{forkCheck(false)}, 10)
+	if len(forks) != 2 || len(own) != 2 {
+		t.Errorf("got %d forks, %d own", len(forks), len(own))
+	}
+	if calls["/repos/alice/fork"] != 1 || calls["/repos/alice/own"] != 1 {
+		t.Errorf("lookups not cached: %v", calls)
+	}
+}
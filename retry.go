@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy says how API requests that fail transiently are retried: 5xx
+// responses, 429s, and secondary rate limits (403s with Retry-After).
+type retryPolicy struct {
+	Retries int           // retries after the first attempt
+	MaxWait time.Duration // give up rather than wait longer than this at once
+	Base    time.Duration // first backoff, doubled for each retry
+}
+
+// retries is the policy doGetJSON follows. main sets the defaults; tests
+// leave retrying off.
+var retries retryPolicy
+
+var defaultRetryPolicy = retryPolicy{Retries: 3, MaxWait: time.Minute, Base: time.Second}
+
+// delay returns how long to wait before retrying resp, the response to the
+// given attempt (0 for the first), or false when it shouldn't be retried.
+// Retry-After is honored; otherwise the backoff doubles with jitter.
+func (p retryPolicy) delay(resp *http.Response, attempt int) (time.Duration, bool) {
+	if attempt >= p.Retries {
+		return 0, false
+	}
+	after, hasAfter := retryAfter(resp.Header.Get("Retry-After"))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusForbidden && hasAfter:
+	case resp.StatusCode == http.StatusInternalServerError,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+	if hasAfter {
+		return after, after <= p.MaxWait
+	}
+	d := p.Base << attempt
+	d = d/2 + rand.N(d/2+1) // jitter, so parallel clients don't retry in step
+	return min(d, p.MaxWait), true
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now()), 0), true
+	}
+	return 0, false
+}
+
+// requestTimeout limits each attempt at an API request, from sending it to
+// reading the body; 0 means no limit. main sets the default.
+var requestTimeout time.Duration
+
+const defaultRequestTimeout = 10 * time.Second
+
+// doWithRetry sends req, retrying under the retries policy. req must not
+// have a body.
+func doWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := doWithTimeout(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		wait, ok := retries.delay(resp, attempt)
+		if !ok {
+			return resp, nil
+		}
+		resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// doWithTimeout sends one attempt under requestTimeout. The timeout keeps
+// running while the body is read and ends when it is closed.
+func doWithTimeout(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if requestTimeout <= 0 {
+		return http.DefaultClient.Do(req)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	resp, err := http.DefaultClient.Do(req.WithContext(attemptCtx))
+	if err != nil {
+		cancel()
+		if ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("no response within %s", requestTimeout)
+		}
+		return nil, err
+	}
+	resp.Body = cancelOnClose{resp.Body, cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	pinClock(t)
+	p := retryPolicy{Retries: 2, MaxWait: 10 * time.Second, Base: time.Second}
+	resp := func(status int, retryAfter string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		return r
+	}
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		attempt int
+		min     time.Duration
+		max     time.Duration
+		ok      bool
+	}{
+		{"502", resp(502, ""), 0, 500 * time.Millisecond, time.Second, true},
+		{"503 second retry", resp(503, ""), 1, time.Second, 2 * time.Second, true},
+		{"out of retries", resp(503, ""), 2, 0, 0, false},
+		{"secondary rate limit", resp(403, "3"), 0, 3 * time.Second, 3 * time.Second, true},
+		{"retry-after date", resp(429, now().Add(5*time.Second).Format(http.TimeFormat)), 0, 5 * time.Second, 5 * time.Second, true},
+		{"retry-after too long", resp(429, "3600"), 0, 0, 0, false},
+		{"plain 403", resp(403, ""), 0, 0, 0, false},
+		{"404", resp(404, ""), 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		d, ok := p.delay(tt.resp, tt.attempt)
+		if ok != tt.ok || (ok && (d < tt.min || d > tt.max)) {
+			t.Errorf("%s: got %v, %v", tt.name, d, ok)
+		}
+	}
+}
+
+func TestGetJSONRetries(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, "unicorn", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"login": "alice"}`))
+	}))
+	defer srv.Close()
+	restore := retries
+	defer func() { retries = restore }()
+
+	retries = retryPolicy{Retries: 2, MaxWait: time.Second, Base: time.Millisecond}
+	var v struct{ Login string }
+	if err := getJSON(context.Background(), srv.URL, &v); err != nil || v.Login != "alice" || calls != 3 {
+		t.Errorf("got %+v, %v after %d calls", v, err, calls)
+	}
+
+	calls = 0
+	retries.Retries = 1
+	if err := getJSON(context.Background(), srv.URL, &v); err == nil || calls != 2 {
+		t.Errorf("got %v after %d calls, want an error after 2", err, calls)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	restore := requestTimeout
+	requestTimeout = 20 * time.Millisecond
+	defer func() { requestTimeout = restore }()
+
+	var v any
+	err := getJSON(context.Background(), srv.URL, &v)
+	if err == nil || !strings.Contains(err.Error(), "no response within 20ms") {
+		t.Errorf("got %v", err)
+	}
+}
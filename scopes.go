@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// impliedScopes lists the OAuth scopes each broader scope grants.
+var impliedScopes = map[string][]string{
+	"repo":             {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org":        {"write:org", "read:org"},
+	"write:org":        {"read:org"},
+	"admin:repo_hook":  {"write:repo_hook", "read:repo_hook"},
+	"write:repo_hook":  {"read:repo_hook"},
+	"admin:public_key": {"write:public_key", "read:public_key"},
+	"write:public_key": {"read:public_key"},
+	"admin:gpg_key":    {"write:gpg_key", "read:gpg_key"},
+	"write:gpg_key":    {"read:gpg_key"},
+	"user":             {"read:user", "user:email", "user:follow"},
+	"write:packages":   {"read:packages"},
+	"project":          {"read:project"},
+}
+
+// scopeError explains a failed response in terms of token scopes, using the
+// X-OAuth-Scopes (granted) and X-Accepted-OAuth-Scopes (any one suffices)
+// headers GitHub sends for token-authenticated requests. It returns nil when
+// the headers are absent or the token already has an accepted scope.
+func scopeError(h http.Header) error {
+	accepted := splitScopes(h.Get("X-Accepted-OAuth-Scopes"))
+	if len(accepted) == 0 || len(h.Values("X-OAuth-Scopes")) == 0 {
+		return nil
+	}
+	granted := map[string]bool{}
+	for _, s := range splitScopes(h.Get("X-OAuth-Scopes")) {
+		granted[s] = true
+		for _, implied := range impliedScopes[s] {
+			granted[implied] = true
+		}
+	}
+	for _, s := range accepted {
+		if granted[s] {
+			return nil
+		}
+	}
+	need := "scope " + accepted[0]
+	if len(accepted) > 1 {
+		need = "one of the scopes " + strings.Join(accepted, ", ")
+	}
+	q := url.Values{"scopes": {strings.Join(accepted, ",")}, "description": {"github-activity"}}
+	return fmt.Errorf("token is missing %s; create a token with it at %s/settings/tokens/new?%s", need, webURL, q.Encode())
+}
+
+func splitScopes(s string) []string {
+	var scopes []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			scopes = append(scopes, f)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScopeError(t *testing.T) {
+	tests := []struct {
+		granted, accepted string
+		want              string // substring of the error, "" for nil
+	}{
+		{"", "", ""},
+		{"repo, read:org", "repo", ""},
+		{"repo", "public_repo", ""},   // implied
+		{"admin:org", "read:org", ""}, // implied
+		{"public_repo", "repo", "missing scope repo; create a token with it at https://github.com/settings/tokens/new?description=github-activity&scopes=repo"},
+		{"", "notifications, repo", "missing one of the scopes notifications, repo"},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		h.Set("X-OAuth-Scopes", tt.granted)
+		if tt.accepted != "" {
+			h.Set("X-Accepted-OAuth-Scopes", tt.accepted)
+		}
+		err := scopeError(h)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("granted %q accepted %q: unexpected %v", tt.granted, tt.accepted, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("granted %q accepted %q: got %v, want %q", tt.granted, tt.accepted, err, tt.want)
+		}
+	}
+
+	// Unauthenticated responses carry no X-OAuth-Scopes at all.
+	h := http.Header{}
+	h.Set("X-Accepted-OAuth-Scopes", "repo")
+	if err := scopeError(h); err != nil {
+		t.Errorf("unauthenticated: %v", err)
+	}
+}
+
+func TestGetJSON_MissingScope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "read:user")
+		w.Header().Set("X-Accepted-OAuth-Scopes", "notifications")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+	var v any
+	err := getJSON(context.Background(), srv.URL, &v)
+	if err == nil || !strings.Contains(err.Error(), "missing scope notifications") {
+		t.Fatalf("got %v", err)
+	}
+}
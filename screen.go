@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// screenReport is a recruiter-friendly summary of a user's public activity.
+type screenReport struct {
+	User  string
+	Since time.Time // oldest contribution
+	Days  int       // days from Since through today
+
+	ActiveDays    int
+	Weeks         int
+	ActiveWeeks   int
+	LongestStreak int // consecutive active days
+
+	Languages []countItem // contributions per repository language
+
+	Owned, External int         // distinct repositories contributed to
+	TopRepos        []countItem // contributions per repository
+
+	Reviews, ReviewedPRs, ReviewRepos int
+
+	PRsOpened, IssuesOpened, Commits int
+}
+
+// buildScreen summarizes events (newest first). Repository languages come
+// from the repositories API; lookups that fail are warned about and left
+// out of the language breakdown.
+func buildScreen(ctx context.Context, user string, events []Event) screenReport {
+	r := screenReport{User: user}
+	var contributions []Event
+	for _, ev := range events {
+		if _, ok := learningKinds[ev.Type]; ok {
+			contributions = append(contributions, ev)
+		}
+	}
+	if len(contributions) == 0 {
+		return r
+	}
+	r.Since = contributions[len(contributions)-1].CreatedAt
+
+	today, _, _ := periodBounds("day", now())
+	first, _, _ := periodBounds("day", r.Since)
+	r.Days = int(today.Sub(first).Hours()/24+0.5) + 1
+	firstWeek, _, _ := periodBounds("week", r.Since)
+	thisWeek, _, _ := periodBounds("week", now())
+	r.Weeks = int(thisWeek.Sub(firstWeek).Hours()/(24*7)+0.5) + 1
+
+	days, weeks := map[string]bool{}, map[string]bool{}
+	var repoNames, reviewed, reviewRepos []string
+	for _, ev := range contributions {
+		day, _, _ := periodBounds("day", ev.CreatedAt)
+		week, _, _ := periodBounds("week", ev.CreatedAt)
+		days[day.Format("2006-01-02")] = true
+		weeks[week.Format("2006-01-02")] = true
+		repoNames = append(repoNames, ev.Repo.Name)
+
+		d := detailsOf(ev)
+		switch ev.Type {
+		case "PullRequestReviewEvent":
+			r.Reviews++
+			reviewed = append(reviewed, fmt.Sprintf("%s#%d", ev.Repo.Name, d.Number))
+			reviewRepos = append(reviewRepos, ev.Repo.Name)
+		case "PullRequestEvent":
+			if d.Action == "opened" {
+				r.PRsOpened++
+			}
+		case "IssuesEvent":
+			if d.Action == "opened" {
+				r.IssuesOpened++
+			}
+		case "PushEvent":
+			r.Commits += pushSize(ev)
+		}
+	}
+	r.ActiveDays, r.ActiveWeeks = len(days), len(weeks)
+	r.ReviewedPRs, r.ReviewRepos = len(rank(reviewed)), len(rank(reviewRepos))
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		streak := 0
+		for days[d.AddDate(0, 0, streak).Format("2006-01-02")] {
+			streak++
+		}
+		r.LongestStreak = max(r.LongestStreak, streak)
+	}
+
+	r.TopRepos = rank(repoNames)
+	var languages []string
+	for _, repo := range r.TopRepos {
+		if owner, _, _ := strings.Cut(repo.Key, "/"); strings.EqualFold(owner, user) {
+			r.Owned++
+		} else {
+			r.External++
+		}
+		info, err := lookupRepo(ctx, repo.Key)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: language of %s: %v\n", repo.Key, err)
+			continue
+		}
+		if info.Language != "" {
+			for range repo.Count {
+				languages = append(languages, info.Language)
+			}
+		}
+	}
+	r.Languages = rank(languages)
+	return r
+}
+
+// percent is n as a whole percentage of total.
+func percent(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return (n*100 + total/2) / total
+}
+
+// writeScreenMarkdown writes r as a Markdown report.
+func writeScreenMarkdown(w io.Writer, r screenReport) {
+	fmt.Fprintf(w, "# Candidate screen: %s\n\n", r.User)
+	if r.Since.IsZero() {
+		fmt.Fprintf(w, "_No public contributions in the last 90 days; generated %s._\n", now().Format("Jan 2, 2006"))
+		return
+	}
+	fmt.Fprintf(w, "_Public GitHub activity since %s (%d days); generated %s._\n\n", r.Since.Local().Format("Jan 2, 2006"), r.Days, now().Format("Jan 2, 2006"))
+
+	fmt.Fprintln(w, "## Summary")
+	fmt.Fprintf(w, "- **Consistency:** active on %d of %d days (%d%%) and %d of %d weeks; longest streak %s\n",
+		r.ActiveDays, r.Days, percent(r.ActiveDays, r.Days), r.ActiveWeeks, r.Weeks, plural(r.LongestStreak, "day", "days"))
+	if len(r.Languages) > 0 {
+		total := 0
+		for _, l := range r.Languages {
+			total += l.Count
+		}
+		var parts []string
+		for _, l := range r.Languages[:min(len(r.Languages), 5)] {
+			parts = append(parts, fmt.Sprintf("%s (%d%%)", l.Key, percent(l.Count, total)))
+		}
+		fmt.Fprintf(w, "- **Languages:** %s\n", strings.Join(parts, ", "))
+	}
+	repos := r.Owned + r.External
+	fmt.Fprintf(w, "- **Repositories:** %d contributed to: %d their own, %d external (%d%% external)\n",
+		repos, r.Owned, r.External, percent(r.External, repos))
+	if r.Reviews > 0 {
+		fmt.Fprintf(w, "- **Code review:** %s on %s in %s\n", plural(r.Reviews, "review", "reviews"),
+			plural(r.ReviewedPRs, "pull request", "pull requests"), plural(r.ReviewRepos, "repository", "repositories"))
+	} else {
+		fmt.Fprintln(w, "- **Code review:** no public reviews")
+	}
+	fmt.Fprintf(w, "- **Output:** %s opened, %s opened, %s pushed\n",
+		plural(r.PRsOpened, "pull request", "pull requests"), plural(r.IssuesOpened, "issue", "issues"), plural(r.Commits, "commit", "commits"))
+
+	fmt.Fprintln(w, "\n## Most active repositories")
+	fmt.Fprintln(w, "| Repository | Owner | Contributions |")
+	fmt.Fprintln(w, "|---|---|---:|")
+	for _, repo := range r.TopRepos[:min(len(r.TopRepos), 10)] {
+		owner := "external"
+		if o, _, _ := strings.Cut(repo.Key, "/"); strings.EqualFold(o, r.User) {
+			owner = "own"
+		}
+		fmt.Fprintf(w, "| %s | %s | %d |\n", repo.Key, owner, repo.Count)
+	}
+	fmt.Fprintln(w, "\n_GitHub only keeps a user's last 300 public events (up to 90 days), and private work never shows, so treat this as a sample rather than a full history._")
+}
+
+func runScreen(args []string) int {
+	fs := flag.NewFlagSet("screen", flag.ExitOnError)
+	output := fs.String("output", "", "Write the report to this file instead of stdout; a .pdf name writes a PDF.")
+	tz := fs.String("tz", "", "Time zone that days and weeks are counted in (default: local).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s screen [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Writes a recruiter-friendly Markdown (or PDF) summary of a user's public activity:")
+		fmt.Fprintln(fs.Output(), "languages, consistency, own vs. external repositories, and code review.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	ctx := context.Background()
+	events, err := fetchEventPages(ctx, fs.Arg(0), pageOptions{PerPage: 100})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	var md strings.Builder
+	writeScreenMarkdown(&md, buildScreen(ctx, fs.Arg(0), events))
+
+	if *output == "" {
+		fmt.Print(md.String())
+		return 0
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if strings.EqualFold(filepath.Ext(*output), ".pdf") {
+		err = writePDF(f, md.String())
+	} else {
+		_, err = io.WriteString(f, md.String())
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
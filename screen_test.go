@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScreenReport(t *testing.T) {
+	pinClock(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/app":
+			fmt.Fprint(w, `{"language":"Go"}`)
+		case "/repos/bob/lib":
+			fmt.Fprint(w, `{"language":"Rust"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+	repoInfoCache.m = map[string]repoInfo{}
+
+	r := buildScreen(context.Background(), "bob", menteeEvents())
+	if r.Days != 20 || r.ActiveDays != 5 || r.Weeks != 3 || r.ActiveWeeks != 2 || r.LongestStreak != 3 {
+		t.Errorf("consistency: %+v", r)
+	}
+	if r.Owned != 1 || r.External != 1 || r.Reviews != 1 || r.PRsOpened != 3 || r.IssuesOpened != 1 {
+		t.Errorf("counts: %+v", r)
+	}
+
+	var b strings.Builder
+	writeScreenMarkdown(&b, r)
+	out := b.String()
+	for _, want := range []string{
+		"# Candidate screen: bob\n",
+		"_Public GitHub activity since Apr 15, 2024 (20 days); generated May 4, 2024._",
+		"- **Consistency:** active on 5 of 20 days (25%) and 2 of 3 weeks; longest streak 3 days\n",
+		"- **Languages:** Go (80%), Rust (20%)\n",
+		"- **Repositories:** 2 contributed to: 1 their own, 1 external (50% external)\n",
+		"- **Code review:** 1 review on 1 pull request in 1 repository\n",
+		"- **Output:** 3 pull requests opened, 1 issue opened, 0 commits pushed\n",
+		"| acme/app | external | 4 |\n| bob/lib | own | 1 |\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	b.Reset()
+	writeScreenMarkdown(&b, buildScreen(context.Background(), "carol", nil))
+	if !strings.Contains(b.String(), "No public contributions") {
+		t.Errorf("empty: %q", b.String())
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackMaxSkew is how old a Slack request's timestamp may be before it is
+// rejected as a possible replay.
+const slackMaxSkew = 5 * time.Minute
+
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on.")
+	secret := fs.String("slack-signing-secret", "", "Slack app signing secret used to verify slash-command requests (default: $SLACK_SIGNING_SECRET).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s serve [options]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Serves activity over HTTP. Endpoints:")
+		fmt.Fprintln(fs.Output(), "  POST /slack/command  Slack slash command, e.g. /github-activity torvalds --n=5")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *secret == "" {
+		*secret = os.Getenv("SLACK_SIGNING_SECRET")
+	}
+	if *secret == "" {
+		fmt.Fprintln(os.Stderr, "Error: a Slack signing secret is required (--slack-signing-secret or $SLACK_SIGNING_SECRET)")
+		return 2
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /slack/command", slackHandler{secret: *secret})
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", *addr)
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+// slackHandler answers Slack slash commands. The command text is a username
+// followed by a few of the main command's options.
+type slackHandler struct {
+	secret string
+}
+
+func (h slackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		http.Error(w, "read failed", http.StatusBadRequest)
+		return
+	}
+	if err := verifySlackSignature(h.secret, r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+	// Slack gives up after three seconds.
+	ctx, cancel := context.WithTimeout(r.Context(), 2500*time.Millisecond)
+	defer cancel()
+	text, err := queryActivity(ctx, strings.Fields(form.Get("text")))
+	if err != nil {
+		text = "Error: " + err.Error()
+	} else {
+		text = "```\n" + text + "\n```"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"response_type": "ephemeral", "text": text})
+}
+
+// verifySlackSignature checks Slack's v0 request signature: an HMAC-SHA256
+// of "v0:<timestamp>:<body>" keyed with the signing secret.
+func verifySlackSignature(secret string, h http.Header, body []byte) error {
+	ts := h.Get("X-Slack-Request-Timestamp")
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid request timestamp")
+	}
+	if skew := now().Sub(time.Unix(secs, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return errors.New("request timestamp too far from now")
+	}
+	sig, ok := strings.CutPrefix(h.Get("X-Slack-Signature"), "v0=")
+	got, err := hex.DecodeString(sig)
+	if !ok || err != nil {
+		return errors.New("missing or invalid signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", ts)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// queryActivity renders a user's recent activity for a chat command whose
+// arguments are "<username> [--n=N] [--type=...]".
+func queryActivity(ctx context.Context, args []string) (string, error) {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	limit := fs.Int("n", 10, "")
+	var typeNames stringList
+	fs.Var(&typeNames, "type", "")
+	// Allow the options on either side of the username.
+	var user string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		user, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if user == "" && fs.NArg() > 0 {
+		user = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return "", err
+		}
+	}
+	if user == "" || fs.NArg() > 0 {
+		return "", errors.New("usage: <github-username> [--n=N] [--type=push,pr,...]")
+	}
+	types, err := resolveEventTypes(typeNames)
+	if err != nil {
+		return "", err
+	}
+	*limit = min(max(*limit, 1), 100)
+
+	f := filters{Types: types}
+	events, err := fetchEventPages(ctx, user, pageOptions{PerPage: 100, Enough: func(evs []Event) bool {
+		return len(selectEntries(user, evs, f, *limit)) >= *limit
+	}})
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	out, err := newRenderer(&buf, outputOptions{Format: "text", EventTypes: types})
+	if err != nil {
+		return "", err
+	}
+	entries := arrangeEntries(selectEntries(user, events, f, *limit), true, false)
+	if err := out.feed(user, events, entries); err != nil {
+		return "", err
+	}
+	if err := out.flush(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signSlack(secret, ts, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	pinClock(t)
+	ts := strconv.FormatInt(now().Unix(), 10)
+	body := []byte("text=torvalds")
+	header := func(ts, sig string) http.Header {
+		h := http.Header{}
+		h.Set("X-Slack-Request-Timestamp", ts)
+		h.Set("X-Slack-Signature", sig)
+		return h
+	}
+	if err := verifySlackSignature("s3cret", header(ts, signSlack("s3cret", ts, string(body))), body); err != nil {
+		t.Errorf("valid request: %v", err)
+	}
+	old := strconv.FormatInt(now().Add(-10*time.Minute).Unix(), 10)
+	for name, h := range map[string]http.Header{
+		"wrong secret": header(ts, signSlack("other", ts, string(body))),
+		"stale":        header(old, signSlack("s3cret", old, string(body))),
+		"no signature": header(ts, ""),
+		"no timestamp": header("", signSlack("s3cret", "", string(body))),
+	} {
+		if err := verifySlackSignature("s3cret", h, body); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestSlackHandler(t *testing.T) {
+	pinClock(t)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/torvalds/events" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"type":"WatchEvent","created_at":"2024-05-03T10:00:00Z","repo":{"name":"a/one"},"payload":{"action":"started"}},
+			{"type":"WatchEvent","created_at":"2024-05-03T09:00:00Z","repo":{"name":"a/two"},"payload":{"action":"started"}}]`))
+	}))
+	defer api.Close()
+	restore := apiURL
+	apiURL = api.URL
+	defer func() { apiURL = restore }()
+
+	post := func(text string) (int, map[string]string) {
+		body := url.Values{"text": {text}}.Encode()
+		ts := strconv.FormatInt(now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/slack/command", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", ts)
+		req.Header.Set("X-Slack-Signature", signSlack("s3cret", ts, body))
+		rec := httptest.NewRecorder()
+		slackHandler{secret: "s3cret"}.ServeHTTP(rec, req)
+		var resp map[string]string
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp
+	}
+
+	code, resp := post("torvalds --n=1")
+	if code != http.StatusOK || resp["response_type"] != "ephemeral" {
+		t.Fatalf("got %d %v", code, resp)
+	}
+	if !strings.Contains(resp["text"], "Starred a/one") || strings.Contains(resp["text"], "a/two") || !strings.HasPrefix(resp["text"], "```") {
+		t.Errorf("text: %q", resp["text"])
+	}
+	if _, resp := post("--n=5"); !strings.HasPrefix(resp["text"], "Error: usage:") {
+		t.Errorf("no user: %q", resp["text"])
+	}
+	if _, resp := post("--type=push torvalds --n=lots"); !strings.Contains(resp["text"], `invalid value "lots"`) {
+		t.Errorf("bad option after the user: %q", resp["text"])
+	}
+	if _, resp := post("nobody"); !strings.Contains(resp["text"], "user not found") {
+		t.Errorf("unknown user: %q", resp["text"])
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// sizeBuckets are upper bounds (exclusive) on changed lines for each size
+// label, following the common size/XS..size/XL pull request labels.
+var sizeBuckets = []struct {
+	Label string
+	Below int
+}{
+	{"XS", 10},
+	{"S", 30},
+	{"M", 100},
+	{"L", 500},
+	{"XL", -1}, // everything else
+}
+
+// diffStat is the size of a change.
+type diffStat struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+func (d diffStat) size() string {
+	lines := d.Additions + d.Deletions
+	for _, b := range sizeBuckets {
+		if b.Below < 0 || lines < b.Below {
+			return b.Label
+		}
+	}
+	return ""
+}
+
+// sizePayload holds the fields a diffstat is looked up from.
+type sizePayload struct {
+	Before      string `json:"before"`
+	Head        string `json:"head"`
+	Number      int    `json:"number"`
+	PullRequest *struct {
+		Number    int  `json:"number"`
+		Additions *int `json:"additions"`
+		Deletions *int `json:"deletions"`
+	} `json:"pull_request"`
+}
+
+// eventDiffStat returns the size of a pull request or push. Pull request
+// payloads often carry their diffstat; otherwise it comes from the pulls or
+// compare API. ok is false for other events and for pushes that create a
+// branch, which have nothing to compare against.
+func eventDiffStat(ctx context.Context, ev Event) (d diffStat, ok bool, err error) {
+	var p sizePayload
+	if json.Unmarshal(ev.Payload, &p) != nil {
+		return d, false, nil
+	}
+	switch ev.Type {
+	case "PullRequestEvent":
+		if p.PullRequest == nil {
+			return d, false, nil
+		}
+		if p.PullRequest.Additions != nil && p.PullRequest.Deletions != nil {
+			return diffStat{*p.PullRequest.Additions, *p.PullRequest.Deletions}, true, nil
+		}
+		err = getJSON(ctx, apiURL+"/repos/"+ev.Repo.Name+"/pulls/"+strconv.Itoa(p.PullRequest.Number), &d)
+		return d, err == nil, err
+	case "PushEvent":
+		if p.Head == "" || strings.Trim(p.Before, "0") == "" {
+			return d, false, nil
+		}
+		var cmp struct {
+			Files []diffStat `json:"files"`
+		}
+		if err := getJSON(ctx, apiURL+"/repos/"+ev.Repo.Name+"/compare/"+p.Before+"..."+p.Head, &cmp); err != nil {
+			return d, false, err
+		}
+		for _, f := range cmp.Files {
+			d.Additions += f.Additions
+			d.Deletions += f.Deletions
+		}
+		return d, true, nil
+	}
+	return d, false, nil
+}
+
+// annotateSizes tags pull request and push summaries with their size label,
+// e.g. "Opened PR #3 in alice/repo [M]". Lookups that fail are reported as
+// warnings and leave the entry as it was.
+func annotateSizes(ctx context.Context, entries []entry) []entry {
+	out := make([]entry, len(entries))
+	for i, e := range entries {
+		out[i] = e
+		d, ok, err := eventDiffStat(ctx, e.Event)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: size of %s: %v\n", e.Summary, err)
+		}
+		if ok {
+			out[i].Summary += " [" + d.size() + "]"
+		}
+	}
+	return out
+}
+
+// prSizes counts the user's distinct pull requests per size label.
+func prSizes(ctx context.Context, events []Event) (map[string]int, error) {
+	counts := map[string]int{}
+	seen := map[string]bool{}
+	for _, ev := range events {
+		if ev.Type != "PullRequestEvent" {
+			continue
+		}
+		key := fmt.Sprintf("%s#%d", ev.Repo.Name, detailsOf(ev).Number)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		d, ok, err := eventDiffStat(ctx, ev)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if ok {
+			counts[d.size()]++
+		}
+	}
+	return counts, nil
+}
+
+func printPRSizes(w io.Writer, user string, counts map[string]int) {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total == 0 {
+		fmt.Fprintf(w, "%s has no recent pull requests.\n", user)
+		return
+	}
+	fmt.Fprintf(w, "%s: %d pull requests by size (changed lines)\n", user, total)
+	lower := 0
+	for _, b := range sizeBuckets {
+		rng := fmt.Sprintf("%d+", lower)
+		if b.Below >= 0 {
+			rng = fmt.Sprintf("%d-%d", lower, b.Below-1)
+		}
+		n := counts[b.Label]
+		fmt.Fprintf(w, "  %-2s %-8s %s %d\n", b.Label, rng, strings.Repeat("█", n*30/total), n)
+		lower = b.Below
+	}
+}
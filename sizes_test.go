@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiffStatSize(t *testing.T) {
+	for lines, want := range map[int]string{0: "XS", 9: "XS", 10: "S", 99: "M", 100: "L", 499: "L", 500: "XL", 20000: "XL"} {
+		if got := (diffStat{Additions: lines}).size(); got != want {
+			t.Errorf("%d lines: got %s, want %s", lines, got, want)
+		}
+	}
+}
+
+func TestEventDiffStat(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/repos/alice/repo/pulls/7":
+			_, _ = w.Write([]byte(`{"additions": 40, "deletions": 20}`))
+		case "/repos/alice/repo/compare/aaa...bbb":
+			_, _ = w.Write([]byte(`{"files": [{"additions": 3, "deletions": 1}, {"additions": 2, "deletions": 0}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	ev := func(typ string, payload any) Event {
+		e := Event{Type: typ, Payload: mustRaw(payload)}
+		e.Repo.Name = "alice/repo"
+		return e
+	}
+	tests := []struct {
+		name string
+		ev   Event
+		want diffStat
+		ok   bool
+	}{
+		{"pr in payload", ev("PullRequestEvent", map[string]any{"pull_request": map[string]any{"number": 3, "additions": 700, "deletions": 5}}), diffStat{700, 5}, true},
+		{"pr from api", ev("PullRequestEvent", map[string]any{"pull_request": map[string]any{"number": 7}}), diffStat{40, 20}, true},
+		{"push", ev("PushEvent", map[string]any{"before": "aaa", "head": "bbb"}), diffStat{5, 1}, true},
+		{"new branch", ev("PushEvent", map[string]any{"before": "0000000000", "head": "bbb"}), diffStat{}, false},
+		{"other", ev("WatchEvent", map[string]any{"action": "started"}), diffStat{}, false},
+	}
+	for _, tt := range tests {
+		got, ok, err := eventDiffStat(context.Background(), tt.ev)
+		if err != nil || got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %+v, %v, %v", tt.name, got, ok, err)
+		}
+	}
+	if len(paths) != 2 {
+		t.Errorf("API calls: %v", paths)
+	}
+}
+
+func TestPrintPRSizes(t *testing.T) {
+	var b strings.Builder
+	printPRSizes(&b, "alice", map[string]int{"S": 1, "M": 3})
+	out := b.String()
+	for _, want := range []string{"alice: 4 pull requests", "XS 0-9", "M  30-99", "XL 500+", "██████████████████████ 3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	b.Reset()
+	printPRSizes(&b, "alice", map[string]int{})
+	if got := b.String(); got != "alice has no recent pull requests.\n" {
+		t.Errorf("empty: %q", got)
+	}
+}
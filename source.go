@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DataSource produces the events shown for one user. The built-in source is
+// the GitHub API; --source=exec:<command> plugs in any other provider.
+type DataSource interface {
+	Events(ctx context.Context, user string) ([]Event, error)
+	Close() error
+}
+
+// newDataSource returns the source named by a --source value: "github" or
+// "exec:<command> [args...]". pages says how far back the GitHub source
+// goes; plugins return what they have. With orgs, the GitHub source reads
+// organizations' events instead of users'.
+func newDataSource(spec string, pages pageOptions, orgs bool) (DataSource, error) {
+	switch {
+	case spec == "" || spec == "github":
+		return githubSource{pages: pages, orgs: orgs}, nil
+	case orgs:
+		return nil, errors.New("--target=org needs --source=github")
+	case strings.HasPrefix(spec, "exec:"):
+		args := strings.Fields(strings.TrimPrefix(spec, "exec:"))
+		if len(args) == 0 {
+			return nil, errors.New("--source=exec: needs a command")
+		}
+		return startExecSource(args[0], args[1:]...)
+	}
+	return nil, fmt.Errorf("unknown source %q (want github or exec:<command>)", spec)
+}
+
+type githubSource struct {
+	pages pageOptions
+	orgs  bool // names are organizations (--target=org)
+}
+
+func (s githubSource) Events(ctx context.Context, user string) ([]Event, error) {
+	if s.orgs {
+		return fetchOrgEventPages(ctx, user, s.pages)
+	}
+	return fetchEventPages(ctx, user, s.pages)
+}
+
+func (githubSource) Close() error { return nil }
+
+// execSource runs a plugin as a subprocess and talks JSON-RPC 1.0 to it over
+// stdin/stdout, so plugins can be written in any language. For each user the
+// plugin receives
+//
+//	{"method":"Source.Events","params":[{"user":"alice"}],"id":1}
+//
+// and must answer with {"id":1,"result":[<GitHub-shaped events>],"error":null}.
+// Anything the plugin writes to stderr is passed through.
+type execSource struct {
+	cmd    *exec.Cmd
+	client *rpc.Client
+}
+
+// pluginEventsArgs is the single parameter of Source.Events.
+type pluginEventsArgs struct {
+	User string `json:"user"`
+}
+
+func startExecSource(name string, args ...string) (*execSource, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start source plugin: %w", err)
+	}
+	conn := struct {
+		io.Reader
+		io.WriteCloser
+	}{stdout, stdin}
+	return &execSource{cmd: cmd, client: jsonrpc.NewClient(conn)}, nil
+}
+
+func (s *execSource) Events(ctx context.Context, user string) ([]Event, error) {
+	var raw []json.RawMessage
+	call := s.client.Go("Source.Events", pluginEventsArgs{User: user}, &raw, nil)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.Done:
+	}
+	if call.Error != nil {
+		return nil, fmt.Errorf("source plugin: %w", call.Error)
+	}
+	return decodeEvents(raw)
+}
+
+// Close shuts down the plugin by closing its stdin and waits for it to exit.
+func (s *execSource) Close() error {
+	_ = s.client.Close()
+	return s.cmd.Wait()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"strings"
+	"testing"
+)
+
+// PluginArgs and PluginSource implement a fake source plugin. net/rpc
+// requires exported types on the serving side.
+type PluginArgs struct {
+	User string `json:"user"`
+}
+
+type PluginSource struct{}
+
+func (PluginSource) Events(args PluginArgs, reply *[]map[string]any) error {
+	if args.User == "nobody" {
+		return errors.New("no such account")
+	}
+	*reply = []map[string]any{{
+		"id":         "1",
+		"type":       "WatchEvent",
+		"created_at": "2024-05-01T12:00:00Z",
+		"actor":      map[string]any{"login": args.User},
+		"repo":       map[string]any{"name": "devops/" + args.User},
+		"payload":    map[string]any{"action": "started"},
+	}}
+	return nil
+}
+
+// TestHelperSourcePlugin is not a real test: when re-executed by
+// TestExecSource it serves the fake plugin on stdin/stdout.
+func TestHelperSourcePlugin(t *testing.T) {
+	if os.Getenv("GHA_TEST_SOURCE_PLUGIN") != "1" {
+		t.Skip("helper process for TestExecSource")
+	}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Source", PluginSource{}); err != nil {
+		t.Fatal(err)
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(struct {
+		io.Reader
+		io.WriteCloser
+	}{os.Stdin, os.Stdout}))
+	os.Exit(0)
+}
+
+func TestExecSource(t *testing.T) {
+	t.Setenv("GHA_TEST_SOURCE_PLUGIN", "1")
+	src, err := newDataSource("exec:"+os.Args[0]+" -test.run=^TestHelperSourcePlugin$", pageOptions{Pages: 1}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	events, err := src.Events(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Repo.Name != "devops/alice" || events[0].Actor.Login != "alice" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	line, ok := formatEvent(events[0])
+	if !ok || line != "Starred devops/alice" {
+		t.Fatalf("unexpected line %q", line)
+	}
+
+	_, err = src.Events(context.Background(), "nobody")
+	if err == nil || !strings.Contains(err.Error(), "no such account") {
+		t.Fatalf("expected plugin error, got %v", err)
+	}
+}
+
+func TestNewDataSource(t *testing.T) {
+	if src, err := newDataSource("github", pageOptions{Pages: 1}, false); err != nil || src == nil {
+		t.Fatalf("github source: %v", err)
+	}
+	for _, bad := range []string{"exec:", "ftp://example.com"} {
+		if _, err := newDataSource(bad, pageOptions{Pages: 1}, false); err == nil {
+			t.Fatalf("newDataSource(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGitHubSource_Orgs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/acme/events" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[{"id":"1","type":"WatchEvent","actor":{"login":"alice"},"repo":{"name":"acme/app"},"created_at":"2024-05-01T12:00:00Z"}]`)
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	src, err := newDataSource("github", pageOptions{Pages: 1}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := src.Events(context.Background(), "acme")
+	if err != nil || len(events) != 1 || events[0].Actor.Login != "alice" {
+		t.Fatalf("Events = %+v, %v", events, err)
+	}
+	e := entry{User: "acme", Event: events[0], Summary: "Starred acme/app"}
+	if got := actorPrefix(e); got != "alice: " {
+		t.Errorf("actorPrefix = %q", got)
+	}
+	if _, err := newDataSource("exec:plugin", pageOptions{Pages: 1}, true); err == nil {
+		t.Error("exec source with orgs should fail")
+	}
+}
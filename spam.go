@@ -0,0 +1,160 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// spamSimilarity is how alike two titles' words must be (shared words over
+// all words) for them to count as near-identical.
+const spamSimilarity = 0.6
+
+// spamItem is an issue or pull request opened in a repository feed.
+type spamItem struct {
+	At    time.Time
+	Repo  string
+	PR    bool
+	Title string
+	URL   string
+	words []string
+}
+
+// spamSuspect is an actor who opened many near-identical items within a
+// short time.
+type spamSuspect struct {
+	Actor string
+	Items []spamItem // oldest first
+}
+
+// titleWords is the set of lowercase words in a title, ignoring numbers and
+// punctuation, so "Update README.md" and "update readme (2)" match.
+func titleWords(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool { return !unicode.IsLetter(r) })
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+// titleSimilarity is the share of a and b's combined words they have in
+// common (their Jaccard index).
+func titleSimilarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for _, w := range a {
+		if _, ok := slices.BinarySearch(b, w); ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// detectSpam finds actors in events (newest first) who opened at least
+// minItems issues or pull requests with near-identical titles within window,
+// as in Hacktoberfest spam. Bots, whose pull requests are alike by design,
+// are left out. Suspects come most prolific first.
+func detectSpam(events []Event, minItems int, window time.Duration) []spamSuspect {
+	opened := map[string][]spamItem{}
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if (ev.Type != "IssuesEvent" && ev.Type != "PullRequestEvent") || isBotLogin(ev.Actor.Login) {
+			continue
+		}
+		d := detailsOf(ev)
+		if d.Action != "opened" {
+			continue
+		}
+		opened[ev.Actor.Login] = append(opened[ev.Actor.Login], spamItem{
+			At: ev.CreatedAt, Repo: ev.Repo.Name, PR: ev.Type == "PullRequestEvent",
+			Title: d.Title, URL: entityURL(ev), words: titleWords(d.Title),
+		})
+	}
+
+	var suspects []spamSuspect
+	for actor, items := range opened {
+		if len(items) < minItems {
+			continue
+		}
+		// Group alike titles, comparing each with the first of a group.
+		var groups [][]spamItem
+	items:
+		for _, it := range items {
+			for g := range groups {
+				if titleSimilarity(groups[g][0].words, it.words) >= spamSimilarity {
+					groups[g] = append(groups[g], it)
+					continue items
+				}
+			}
+			groups = append(groups, []spamItem{it})
+		}
+		// Keep the largest burst within window of any group.
+		var burst []spamItem
+		for _, g := range groups {
+			start := 0
+			for end := range g {
+				for g[end].At.Sub(g[start].At) > window {
+					start++
+				}
+				if end-start+1 > len(burst) {
+					burst = g[start : end+1]
+				}
+			}
+		}
+		if len(burst) >= minItems {
+			suspects = append(suspects, spamSuspect{Actor: actor, Items: burst})
+		}
+	}
+	slices.SortFunc(suspects, func(a, b spamSuspect) int {
+		return cmp.Or(cmp.Compare(len(b.Items), len(a.Items)), strings.Compare(a.Actor, b.Actor))
+	})
+	return suspects
+}
+
+// spanText describes how long a burst took, e.g. "in 3 hours".
+func spanText(d time.Duration) string {
+	if d < time.Minute {
+		return "within a minute"
+	}
+	return "in " + formatLeft(d)
+}
+
+func printSpam(w io.Writer, target string, events []Event, suspects []spamSuspect) {
+	if len(suspects) == 0 {
+		fmt.Fprintf(w, "No spam-like bursts of issues or pull requests in %s%s.\n", target, sinceOldest(events))
+		return
+	}
+	fmt.Fprintf(w, "Possible spam in %s%s:\n", target, sinceOldest(events))
+	for _, s := range suspects {
+		prs := 0
+		repos := map[string]bool{}
+		for _, it := range s.Items {
+			if it.PR {
+				prs++
+			}
+			repos[it.Repo] = true
+		}
+		var what string
+		switch prs {
+		case len(s.Items):
+			what = plural(prs, "pull request", "pull requests")
+		case 0:
+			what = plural(len(s.Items), "issue", "issues")
+		default:
+			what = plural(len(s.Items), "issue or pull request", "issues and pull requests")
+		}
+		where := ""
+		if len(repos) > 1 {
+			where = " across " + plural(len(repos), "repository", "repositories")
+		}
+		span := s.Items[len(s.Items)-1].At.Sub(s.Items[0].At)
+		fmt.Fprintf(w, "\n%s opened %s%s %s, with titles like “%s”\n", s.Actor, what, where, spanText(span), s.Items[0].Title)
+		for _, it := range s.Items {
+			fmt.Fprintf(w, "  %s  %s  %s\n", it.At.Local().Format("Jan 02 15:04"), it.URL, it.Title)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		alike bool
+	}{
+		{"Update README.md", "update readme.md (2)", true},
+		{"Update README.md", "Update README", true},
+		{"Fix typo in docs", "Fix typo in README", true},
+		{"Update README.md", "Add dark mode", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := titleSimilarity(titleWords(tt.a), titleWords(tt.b)) >= spamSimilarity; got != tt.alike {
+			t.Errorf("%q ~ %q: got %v", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestDetectSpam(t *testing.T) {
+	pinClock(t)
+	opened := func(actor, repo string, pr bool, n int, title string, at time.Time) Event {
+		typ, key := "IssuesEvent", "issue"
+		if pr {
+			typ, key = "PullRequestEvent", "pull_request"
+		}
+		e := Event{Type: typ, CreatedAt: at, Payload: mustRaw(map[string]any{"action": "opened", key: map[string]any{"number": n, "title": title}})}
+		e.Actor.Login = actor
+		e.Repo.Name = repo
+		return e
+	}
+	at := func(day, hour int) time.Time { return time.Date(2024, 5, day, hour, 0, 0, 0, time.UTC) }
+	events := []Event{
+		opened("mallory", "acme/app", true, 40, "Update README", at(3, 9)),
+		opened("alice", "acme/app", false, 9, "Crash on start", at(2, 14)),
+		opened("mallory", "acme/lib", true, 12, "update readme.md (2)", at(1, 12)),
+		opened("dependabot[bot]", "acme/app", true, 8, "Bump x from 1 to 2", at(1, 11)),
+		opened("dependabot[bot]", "acme/lib", true, 13, "Bump x from 1 to 2", at(1, 11)),
+		opened("dependabot[bot]", "acme/web", true, 3, "Bump x from 1 to 2", at(1, 11)),
+		opened("mallory", "acme/app", true, 7, "Update README.md", at(1, 10)),
+		opened("alice", "acme/app", false, 6, "Docs unclear", at(1, 10)),
+		opened("mallory", "acme/web", true, 2, "Update README.md", at(1, 9)),
+		opened("alice", "acme/app", false, 5, "Add dark mode", at(1, 9)),
+	}
+	suspects := detectSpam(events, 3, 24*time.Hour)
+	if len(suspects) != 1 || suspects[0].Actor != "mallory" || len(suspects[0].Items) != 3 {
+		t.Fatalf("got %+v", suspects)
+	}
+
+	var b strings.Builder
+	printSpam(&b, "acme", events, suspects)
+	want := "Possible spam in acme since May 1, 2024:\n\n" +
+		"mallory opened 3 pull requests across 3 repositories in 3 hours, with titles like “Update README.md”\n" +
+		"  May 01 09:00  https://github.com/acme/web/pull/2  Update README.md\n" +
+		"  May 01 10:00  https://github.com/acme/app/pull/7  Update README.md\n" +
+		"  May 01 12:00  https://github.com/acme/lib/pull/12  update readme.md (2)\n"
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	if got := detectSpam(events, 4, 24*time.Hour); len(got) != 0 {
+		t.Errorf("min 4: got %+v", got)
+	}
+	if got := detectSpam(events, 4, 72*time.Hour); len(got) != 1 || len(got[0].Items) != 4 {
+		t.Errorf("72h window: got %+v", got)
+	}
+}
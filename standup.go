@@ -0,0 +1,163 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// standupWindow is a section of a standup: the events from Start until End.
+type standupWindow struct {
+	Title      string
+	Start, End time.Time
+}
+
+// standupWindows splits the time before t into "Yesterday" and "Today"
+// (local days). On Mondays, yesterday reaches back to Friday, so weekend
+// work isn't lost.
+func standupWindows(t time.Time) [2]standupWindow {
+	t = t.Local()
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	yesterday := standupWindow{Title: "Yesterday", Start: today.AddDate(0, 0, -1), End: today}
+	if today.Weekday() == time.Monday {
+		yesterday = standupWindow{Title: "Since Friday", Start: today.AddDate(0, 0, -3), End: today}
+	}
+	return [2]standupWindow{yesterday, {Title: "Today", Start: today, End: t}}
+}
+
+// prMerged reports whether ev is a pull request being merged.
+func prMerged(ev Event) bool {
+	if ev.Type != "PullRequestEvent" {
+		return false
+	}
+	var p struct {
+		Action      string `json:"action"`
+		PullRequest struct {
+			Merged bool `json:"merged"`
+		} `json:"pull_request"`
+	}
+	return json.Unmarshal(ev.Payload, &p) == nil && p.Action == "closed" && p.PullRequest.Merged
+}
+
+// standupRepo is one repository's items in a standup section.
+type standupRepo struct {
+	Name  string
+	Items []entry // merged pull requests first, then oldest first
+}
+
+// standupRepos groups the entries (newest first) in w by repository,
+// busiest repository first, collapsing runs of pushes.
+func standupRepos(entries []entry, w standupWindow) []standupRepo {
+	byRepo := map[string][]entry{}
+	for _, e := range entries {
+		at := e.Event.CreatedAt
+		if at.Before(w.Start) || !at.Before(w.End) {
+			continue
+		}
+		byRepo[e.Event.Repo.Name] = append(byRepo[e.Event.Repo.Name], e)
+	}
+	var repos []standupRepo
+	for name, es := range byRepo {
+		items := collapsePushes(es)
+		slices.Reverse(items)
+		slices.SortStableFunc(items, func(a, b entry) int {
+			switch ma, mb := prMerged(a.Event), prMerged(b.Event); {
+			case ma && !mb:
+				return -1
+			case mb && !ma:
+				return 1
+			}
+			return 0
+		})
+		repos = append(repos, standupRepo{Name: name, Items: items})
+	}
+	slices.SortFunc(repos, func(a, b standupRepo) int {
+		return cmp.Or(cmp.Compare(len(b.Items), len(a.Items)), strings.Compare(a.Name, b.Name))
+	})
+	return repos
+}
+
+// standupSummary is an item's line: merged pull requests are reworded,
+// since the feed only calls them closed.
+func standupSummary(e entry) string {
+	if !prMerged(e.Event) {
+		return e.Summary
+	}
+	d := detailsOf(e.Event)
+	return fmt.Sprintf("Merged pull request #%d “%s” in %s", d.Number, d.Title, e.Event.Repo.Name)
+}
+
+// printStandup writes the yesterday and today sections as Markdown, or as
+// Slack mrkdwn, which has single-asterisk bold and no list syntax.
+func printStandup(w io.Writer, entries []entry, slack bool) {
+	bold, bullet, sub := "**", "- ", "  - "
+	if slack {
+		bold, bullet, sub = "*", "• ", "    ◦ "
+	}
+	for i, win := range standupWindows(now()) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s%s (%s)%s\n", bold, win.Title, win.Start.Format("Mon, Jan 2"), bold)
+		repos := standupRepos(entries, win)
+		if len(repos) == 0 {
+			fmt.Fprintln(w, bullet+"Nothing on GitHub")
+		}
+		for _, r := range repos {
+			fmt.Fprintln(w, bullet+r.Name)
+			for _, e := range r.Items {
+				if prMerged(e.Event) {
+					fmt.Fprintf(w, "%s%s%s%s\n", sub, bold, standupSummary(e), bold)
+				} else {
+					fmt.Fprintln(w, sub+standupSummary(e))
+				}
+			}
+		}
+	}
+}
+
+func runStandup(args []string) int {
+	fs := flag.NewFlagSet("standup", flag.ExitOnError)
+	slack := fs.Bool("slack", false, "Format for Slack (mrkdwn) instead of Markdown.")
+	tz := fs.String("tz", "", "Time zone that days start in (default: local).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s standup [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints what the user did yesterday (since Friday, on Mondays) and so far today,")
+		fmt.Fprintln(fs.Output(), "grouped by repository with merged pull requests first and in bold, ready to paste")
+		fmt.Fprintln(fs.Output(), "into a standup thread.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	since := standupWindows(now())[0].Start
+	events, err := fetchEventPages(context.Background(), fs.Arg(0), pageOptions{PerPage: 100, Since: since})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	var entries []entry
+	for _, ev := range events {
+		if summary, ok := formatEvent(ev); ok {
+			entries = append(entries, entry{User: fs.Arg(0), Event: ev, Summary: summary})
+		}
+	}
+	printStandup(os.Stdout, entries, *slack)
+	return 0
+}
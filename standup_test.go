@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStandupWindows(t *testing.T) {
+	pinClock(t)
+	w := standupWindows(now()) // Saturday
+	if w[0].Title != "Yesterday" || !w[0].Start.Equal(time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)) || !w[1].Start.Equal(w[0].End) {
+		t.Errorf("Saturday: got %+v", w)
+	}
+	w = standupWindows(time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC))
+	if w[0].Title != "Since Friday" || !w[0].Start.Equal(time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Monday: got %+v", w[0])
+	}
+}
+
+func TestPrintStandup(t *testing.T) {
+	pinClock(t)
+	at := func(e entry, day, hour int) entry {
+		e.Event.CreatedAt = time.Date(2024, 5, day, hour, 0, 0, 0, time.UTC)
+		return e
+	}
+	merged := testEntry("PullRequestEvent", "acme/app", "Closed a pull request #12 “Fix login” in acme/app")
+	merged.Event.Payload = mustRaw(map[string]any{"action": "closed", "pull_request": map[string]any{"number": 12, "title": "Fix login", "merged": true}})
+	entries := []entry{ // newest first
+		at(testEntry("IssuesEvent", "acme/app", "Opened issue #13 in acme/app"), 4, 8),
+		at(merged, 3, 16),
+		at(testEntry("WatchEvent", "bob/lib", "Starred bob/lib"), 3, 15),
+		at(testEntry("IssueCommentEvent", "acme/app", "Commented on issue #7 in acme/app"), 3, 10),
+		at(testEntry("PushEvent", "acme/old", "Pushed 1 commit(s) to acme/old"), 2, 10),
+	}
+
+	var b strings.Builder
+	printStandup(&b, entries, false)
+	want := `**Yesterday (Fri, May 3)**
+- acme/app
+  - **Merged pull request #12 “Fix login” in acme/app**
+  - Commented on issue #7 in acme/app
+- bob/lib
+  - Starred bob/lib
+
+**Today (Sat, May 4)**
+- acme/app
+  - Opened issue #13 in acme/app
+`
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	b.Reset()
+	printStandup(&b, entries[2:3], true)
+	want = `*Yesterday (Fri, May 3)*
+• bob/lib
+    ◦ Starred bob/lib
+
+*Today (Sat, May 4)*
+• Nothing on GitHub
+`
+	if got := b.String(); got != want {
+		t.Errorf("slack: got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// countItem is one row of a ranked count.
+type countItem struct {
+	Key   string
+	Count int
+}
+
+// rank counts keys and orders them by count, then name.
+func rank(keys []string) []countItem {
+	counts := map[string]int{}
+	for _, k := range keys {
+		counts[k]++
+	}
+	items := make([]countItem, 0, len(counts))
+	for k, n := range counts {
+		items = append(items, countItem{k, n})
+	}
+	slices.SortFunc(items, func(a, b countItem) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Key, b.Key))
+	})
+	return items
+}
+
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	starredTargets := fs.Bool("starred-targets", false, "Rank the owners and organizations whose repositories the user starred.")
+	stargazers := fs.Bool("stargazers", false, "List who recently starred the repository (with an <owner>/<repo> argument).")
+	workHours := fs.Bool("working-hours", false, "Estimate the user's typical active hours and days.")
+	prSizesMode := fs.Bool("pr-sizes", false, "Show how the user's recent pull requests are distributed across size labels (XS-XL).")
+	commitTypes := fs.Bool("commit-types", false, "Break the user's recently pushed commits down by Conventional Commits type (feat, fix, docs, ...).")
+	timeEstimate := fs.Bool("time-estimate", false, "Estimate hours of GitHub-visible activity per day and repository, clustering events into sessions.")
+	detectSpamMode := fs.Bool("detect-spam", false, "Flag actors who opened many near-identical issues or pull requests in a short time (with an <owner>/<repo> or organization argument).")
+	spamMin := fs.Int("spam-min", 3, "How many alike issues or pull requests within --spam-window --detect-spam flags.")
+	spamWindow := fs.Duration("spam-window", 24*time.Hour, "How short a time --detect-spam looks for bursts in.")
+	tz := fs.String("tz", "", "Time zone for --working-hours (default: inferred from commit dates, else local) and --time-estimate days.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s stats [options] <github-username>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s stats --stargazers <owner>/<repo>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s stats --detect-spam <owner>/<repo> | <organization>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Summarizes recent activity. Without options, counts events by type.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	modes := 0
+	for _, on := range []bool{*starredTargets, *stargazers, *workHours, *prSizesMode, *commitTypes, *timeEstimate, *detectSpamMode} {
+		if on {
+			modes++
+		}
+	}
+	if modes > 1 {
+		fmt.Fprintln(os.Stderr, "Error: --starred-targets, --stargazers, --working-hours, --pr-sizes, --commit-types, --time-estimate, and --detect-spam are mutually exclusive")
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	if *stargazers {
+		repo, err := parseRepo(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		events, err := fetchRepoEvents(ctx, repo)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		printStargazers(os.Stdout, fs.Arg(0), events)
+		return 0
+	}
+
+	if *detectSpamMode {
+		if *spamMin < 2 || *spamWindow <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --spam-min must be at least 2 and --spam-window positive")
+			return 2
+		}
+		var events []Event
+		var err error
+		if strings.Contains(fs.Arg(0), "/") {
+			repo, perr := parseRepo(fs.Arg(0))
+			if perr != nil {
+				fmt.Fprintln(os.Stderr, "Error:", perr)
+				return 2
+			}
+			events, err = fetchRepoEventPages(ctx, repo, pageOptions{PerPage: 100})
+		} else {
+			events, err = fetchOrgEventPages(ctx, fs.Arg(0), pageOptions{PerPage: 100})
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		printSpam(os.Stdout, fs.Arg(0), events, detectSpam(events, *spamMin, *spamWindow))
+		return 0
+	}
+
+	var events []Event
+	var err error
+	if *timeEstimate {
+		// Estimates cover all the history the API keeps, not just a page.
+		events, err = fetchEventPages(ctx, fs.Arg(0), pageOptions{PerPage: 100})
+	} else {
+		events, err = fetchEvents(ctx, fs.Arg(0))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	switch {
+	case *workHours:
+		loc, inferred := time.Local, false
+		if *tz == "" {
+			if zone, ok := inferZone(ctx, events); ok {
+				loc, inferred = zone, true
+			}
+		}
+		printWorkingHours(os.Stdout, fs.Arg(0), events, loc, inferred)
+	case *prSizesMode:
+		counts, err := prSizes(ctx, events)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		printPRSizes(os.Stdout, fs.Arg(0), counts)
+	case *commitTypes:
+		printCommitTypes(os.Stdout, fs.Arg(0), userCommits(ctx, events))
+	case *timeEstimate:
+		printTimeEstimate(os.Stdout, fs.Arg(0), events)
+	case *starredTargets:
+		printStarredTargets(os.Stdout, fs.Arg(0), events)
+	default:
+		printTypeCounts(os.Stdout, fs.Arg(0), events)
+	}
+	return 0
+}
+
+// sinceOldest describes how far back events go, for stats headers.
+func sinceOldest(events []Event) string {
+	if len(events) == 0 {
+		return ""
+	}
+	return " since " + events[len(events)-1].CreatedAt.Local().Format("Jan 2, 2006")
+}
+
+func printTypeCounts(w io.Writer, user string, events []Event) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No recent public activity.")
+		return
+	}
+	var types []string
+	for _, ev := range events {
+		types = append(types, strings.TrimSuffix(ev.Type, "Event"))
+	}
+	fmt.Fprintf(w, "%s: %d events%s\n", user, len(events), sinceOldest(events))
+	printCounts(w, rank(types))
+}
+
+func printStarredTargets(w io.Writer, user string, events []Event) {
+	var owners []string
+	var starred []Event
+	for _, ev := range events {
+		if ev.Type != "WatchEvent" {
+			continue
+		}
+		owner, _, _ := strings.Cut(ev.Repo.Name, "/")
+		owners = append(owners, owner)
+		starred = append(starred, ev)
+	}
+	if len(owners) == 0 {
+		fmt.Fprintf(w, "%s starred no repositories recently.\n", user)
+		return
+	}
+	fmt.Fprintf(w, "%s starred %d repositories%s, by owner:\n", user, len(owners), sinceOldest(starred))
+	printCounts(w, rank(owners))
+}
+
+func printStargazers(w io.Writer, repo string, events []Event) {
+	type star struct {
+		login string
+		at    time.Time
+	}
+	var stars []star
+	for _, ev := range events {
+		if ev.Type == "WatchEvent" {
+			stars = append(stars, star{ev.Actor.Login, ev.CreatedAt})
+		}
+	}
+	if len(stars) == 0 {
+		fmt.Fprintf(w, "No recent stars on %s.\n", repo)
+		return
+	}
+	fmt.Fprintf(w, "Recently starred %s (%d):\n", repo, len(stars))
+	for _, s := range stars {
+		fmt.Fprintf(w, "- %s %s\n", s.at.Local().Format("2006-01-02 15:04"), s.login)
+	}
+}
+
+// printCounts writes ranked counts as an aligned two-column list.
+func printCounts(w io.Writer, items []countItem) {
+	width := 0
+	for _, it := range items {
+		width = max(width, displayWidth(it.Key))
+	}
+	for _, it := range items {
+		fmt.Fprintf(w, "  %s  %d\n", padWidth(it.Key, width), it.Count)
+	}
+}
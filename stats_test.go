@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func statsEvent(typ, repo, actor string, daysAgo int) Event {
+	ev := Event{Type: typ, CreatedAt: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC).AddDate(0, 0, -daysAgo)}
+	ev.Repo.Name = repo
+	ev.Actor.Login = actor
+	return ev
+}
+
+func TestRank(t *testing.T) {
+	got := rank([]string{"b", "a", "c", "a", "b", "a"})
+	want := []countItem{{"a", 3}, {"b", 2}, {"c", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+}
+
+func TestPrintStarredTargets(t *testing.T) {
+	pinClock(t)
+	events := []Event{
+		statsEvent("WatchEvent", "golang/go", "alice", 0),
+		statsEvent("PushEvent", "alice/x", "alice", 1),
+		statsEvent("WatchEvent", "golang/tools", "alice", 2),
+		statsEvent("WatchEvent", "charmbracelet/bubbletea", "alice", 3),
+	}
+	var b strings.Builder
+	printStarredTargets(&b, "alice", events)
+	want := "alice starred 3 repositories since May 7, 2024, by owner:\n  golang         2\n  charmbracelet  1\n"
+	if b.String() != want {
+		t.Errorf("got %q\nwant %q", b.String(), want)
+	}
+
+	b.Reset()
+	printStarredTargets(&b, "alice", events[1:2])
+	if b.String() != "alice starred no repositories recently.\n" {
+		t.Errorf("none: %q", b.String())
+	}
+}
+
+func TestPrintStargazers(t *testing.T) {
+	pinClock(t)
+	events := []Event{
+		statsEvent("WatchEvent", "alice/repo", "bob", 0),
+		statsEvent("ForkEvent", "alice/repo", "carol", 1),
+		statsEvent("WatchEvent", "alice/repo", "dave", 2),
+	}
+	var b strings.Builder
+	printStargazers(&b, "alice/repo", events)
+	want := "Recently starred alice/repo (2):\n- 2024-05-10 12:00 bob\n- 2024-05-08 12:00 dave\n"
+	if b.String() != want {
+		t.Errorf("got %q\nwant %q", b.String(), want)
+	}
+}
+
+func TestPrintTypeCounts(t *testing.T) {
+	pinClock(t)
+	events := []Event{
+		statsEvent("PushEvent", "a/b", "alice", 0),
+		statsEvent("PushEvent", "a/b", "alice", 1),
+		statsEvent("IssuesEvent", "a/b", "alice", 2),
+	}
+	var b strings.Builder
+	printTypeCounts(&b, "alice", events)
+	want := "alice: 3 events since May 8, 2024\n  Push    2\n  Issues  1\n"
+	if b.String() != want {
+		t.Errorf("got %q\nwant %q", b.String(), want)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// statusCache is a user's feed as last fetched by status.
+type statusCache struct {
+	Fetched time.Time `json:"fetched"`
+	Events  []Event   `json:"events"`
+}
+
+// statusCachePath is where status keeps a user's feed between runs.
+func statusCachePath(user string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "github-activity", "status-"+strings.ToLower(user)+".json")
+}
+
+// readStatusCache loads a cached feed. A cache that can't be read is just
+// missing.
+func readStatusCache(path string) (statusCache, bool) {
+	var c statusCache
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &c) != nil {
+		return statusCache{}, false
+	}
+	return c, true
+}
+
+// writeStatusCache saves a freshly fetched feed, ignoring failures.
+func writeStatusCache(path string, events []Event) {
+	if path == "" {
+		return
+	}
+	if data, err := json.Marshal(statusCache{Fetched: now(), Events: events}); err == nil {
+		_ = os.MkdirAll(filepath.Dir(path), 0o700)
+		// The feed can include private events, so only the user may read it.
+		_ = os.WriteFile(path, data, 0o600)
+		_ = os.Chmod(path, 0o600) // WriteFile keeps the mode of a file written by older versions
+	}
+}
+
+// cachedEvents returns the user's events from the cache when they are
+// younger than ttl, and otherwise fetches and caches them. A cache that
+// can't be read or written just means fetching.
+func cachedEvents(ctx context.Context, user, path string, ttl time.Duration) ([]Event, error) {
+	if c, ok := readStatusCache(path); ok && now().Sub(c.Fetched) < ttl {
+		return c.Events, nil
+	}
+	events, err := fetchToday(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	writeStatusCache(path, events)
+	return events, nil
+}
+
+// fetchToday fetches the user's events back to the start of today, which is
+// all a status segment counts.
+func fetchToday(ctx context.Context, user string) ([]Event, error) {
+	start, _, _ := periodBounds("day", now())
+	return fetchEventPages(ctx, user, pageOptions{PerPage: 100, Since: start})
+}
+
+// statusSegment summarizes today's activity in one short line for status
+// bars and prompts, e.g. "alice: 4⬆ 2PR 1★ 2h ago": pushes, pull requests
+// opened, and stars today, then the time since the latest event.
+func statusSegment(user string, events []Event) string {
+	if len(events) == 0 {
+		return user + ": idle"
+	}
+	start, _, _ := periodBounds("day", now())
+	var pushes, prs, stars int
+	for _, ev := range events {
+		if ev.CreatedAt.Before(start) {
+			continue
+		}
+		switch ev.Type {
+		case "PushEvent":
+			pushes++
+		case "PullRequestEvent":
+			if detailsOf(ev).Action == "opened" {
+				prs++
+			}
+		case "WatchEvent":
+			stars++
+		}
+	}
+	parts := []string{user + ":"}
+	for _, c := range []struct {
+		n      int
+		symbol string
+	}{{pushes, "⬆"}, {prs, "PR"}, {stars, "★"}} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", c.n, c.symbol))
+		}
+	}
+	parts = append(parts, shortAgo(now().Sub(events[0].CreatedAt)))
+	return strings.Join(parts, " ")
+}
+
+// shortAgo renders an age compactly: "now", "5m ago", "2h ago", "3d ago".
+func shortAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	ttl := fs.Duration("cache-ttl", time.Minute, "Reuse the last fetched feed for this long, so status bars can run this every few seconds. 0 always fetches.")
+	tz := fs.String("tz", "", "Time zone that decides when \"today\" starts (default: local).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s status [options] <github-username>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints a one-line summary of today's activity for tmux status bars and shell")
+		fmt.Fprintln(fs.Output(), "prompts, e.g. \"alice: 4⬆ 2PR 1★ 2h ago\" (pushes, PRs opened, stars, last event).")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := setTimezone(*tz, false); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	user := fs.Arg(0)
+	events, err := cachedEvents(context.Background(), user, statusCachePath(user), *ttl)
+	if err != nil {
+		// Status bars show stdout; keep the segment short.
+		fmt.Println(user + ": ?")
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Println(statusSegment(user, events))
+	return 0
+}
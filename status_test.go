@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStatusSegment(t *testing.T) {
+	pinClock(t) // Saturday 2024-05-04 09:00 UTC
+	at := func(typ string, ago time.Duration, payload any) Event {
+		return Event{Type: typ, CreatedAt: now().Add(-ago), Payload: mustRaw(payload)}
+	}
+	events := []Event{
+		at("PushEvent", 2*time.Hour, map[string]any{}),
+		at("PullRequestEvent", 3*time.Hour, map[string]any{"action": "opened"}),
+		at("PullRequestEvent", 4*time.Hour, map[string]any{"action": "closed"}),
+		at("PushEvent", 5*time.Hour, map[string]any{}),
+		at("WatchEvent", 6*time.Hour, map[string]any{}),
+		at("PushEvent", 10*time.Hour, map[string]any{}), // yesterday
+	}
+	if got := statusSegment("alice", events); got != "alice: 2⬆ 1PR 1★ 2h ago" {
+		t.Errorf("got %q", got)
+	}
+	if got := statusSegment("alice", events[5:]); got != "alice: 10h ago" {
+		t.Errorf("nothing today: %q", got)
+	}
+	if got := statusSegment("alice", nil); got != "alice: idle" {
+		t.Errorf("no events: %q", got)
+	}
+}
+
+func TestCachedEvents(t *testing.T) {
+	pinClock(t)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`[{"id":"1","type":"WatchEvent","created_at":"2024-05-04T08:00:00Z","repo":{"name":"a/b"},"payload":{"action":"started"}}]`))
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	path := filepath.Join(t.TempDir(), "status-alice.json")
+	for range 3 {
+		events, err := cachedEvents(context.Background(), "alice", path, time.Minute)
+		if err != nil || len(events) != 1 || events[0].Repo.Name != "a/b" {
+			t.Fatalf("got %v, %v", events, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times within the TTL", calls)
+	}
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+			t.Errorf("mode: %v, %v", fi.Mode(), err)
+		}
+	}
+	start := now()
+	now = func() time.Time { return start.Add(2 * time.Minute) }
+	_, _ = cachedEvents(context.Background(), "alice", path, time.Minute)
+	if calls != 2 {
+		t.Errorf("stale cache not refreshed (%d fetches)", calls)
+	}
+}
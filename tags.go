@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Tag is an entry from the repository tags API.
+type Tag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type tagPayload struct {
+	Action  string `json:"action"`
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
+	Release struct {
+		Name    string `json:"name"`
+		TagName string `json:"tag_name"`
+	} `json:"release"`
+}
+
+// tagActivity is one tag creation, deletion, or release seen in the
+// repository's event feed.
+type tagActivity struct {
+	At        time.Time
+	Actor     string
+	Tag       string
+	Action    string // "created", "deleted", or the release action
+	Release   string // release name, when the tag has an associated release
+	IsRelease bool   // the activity is the release itself, not the tag
+}
+
+func runTags(args []string) int {
+	fs := flag.NewFlagSet("tags", flag.ExitOnError)
+	limit := fs.Int("n", 10, "Max number of tags to list from the tags API.")
+	tz := fs.String("tz", "", "Show dates in this IANA time zone instead of the local zone.")
+	utc := fs.Bool("utc", false, "Show dates in UTC.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s tags [options] <owner>/<repo>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Summarizes recent tag creations/deletions and releases, plus the latest tags.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	repo, err := parseRepo(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	if err := setTimezone(*tz, *utc); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	events, err := fetchRepoEvents(ctx, repo)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	tags, err := fetchTags(ctx, repo, *limit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	printTags(os.Stdout, fs.Arg(0), tagActivities(events), tags)
+	return 0
+}
+
+func fetchTags(ctx context.Context, repo string, limit int) ([]Tag, error) {
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	var tags []Tag
+	err := getJSON(ctx, fmt.Sprintf("%s/repos/%s/tags?per_page=%d", apiURL, repo, limit), &tags)
+	if errors.Is(err, errNotFound) {
+		return nil, errors.New("repository not found")
+	}
+	return tags, err
+}
+
+// tagActivities extracts tag and release events, newest first, attaching
+// release names to the tag creations they belong to.
+func tagActivities(events []Event) []tagActivity {
+	releases := map[string]string{} // tag name -> release name
+	for _, ev := range events {
+		var p tagPayload
+		if ev.Type == "ReleaseEvent" && json.Unmarshal(ev.Payload, &p) == nil && p.Release.TagName != "" {
+			name := p.Release.Name
+			if name == "" {
+				name = p.Release.TagName
+			}
+			releases[p.Release.TagName] = name
+		}
+	}
+
+	var out []tagActivity
+	tagged := map[string]bool{}
+	for _, ev := range events {
+		var p tagPayload
+		if json.Unmarshal(ev.Payload, &p) != nil {
+			continue
+		}
+		a := tagActivity{At: ev.CreatedAt, Actor: ev.Actor.Login}
+		switch {
+		case (ev.Type == "CreateEvent" || ev.Type == "DeleteEvent") && p.RefType == "tag":
+			a.Tag, a.Action = p.Ref, "created"
+			if ev.Type == "DeleteEvent" {
+				a.Action = "deleted"
+			} else {
+				a.Release = releases[p.Ref]
+				tagged[p.Ref] = true
+			}
+		case ev.Type == "ReleaseEvent" && p.Release.TagName != "":
+			a.Tag, a.Action, a.Release, a.IsRelease = p.Release.TagName, p.Action, releases[p.Release.TagName], true
+		default:
+			continue
+		}
+		out = append(out, a)
+	}
+
+	// Releases whose tag creation is also in the feed are already described
+	// on the tag line.
+	filtered := out[:0]
+	for _, a := range out {
+		if a.IsRelease && tagged[a.Tag] {
+			continue
+		}
+		filtered = append(filtered, a)
+	}
+	return filtered
+}
+
+func printTags(w io.Writer, repo string, activity []tagActivity, tags []Tag) {
+	fmt.Fprintf(w, "Tag activity in %s:\n", repo)
+	if len(activity) == 0 {
+		fmt.Fprintln(w, "No recent tag or release events.")
+	}
+	for _, a := range activity {
+		when := a.At.Local().Format("2006-01-02")
+		if a.IsRelease {
+			fmt.Fprintf(w, "- %s: %s %s release “%s” for tag %s\n", when, a.Actor, a.Action, a.Release, a.Tag)
+			continue
+		}
+		line := fmt.Sprintf("- %s: %s %s tag %s", when, a.Actor, a.Action, a.Tag)
+		if a.Release != "" {
+			line += fmt.Sprintf(" (release “%s”)", a.Release)
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintln(w, "\nLatest tags:")
+	if len(tags) == 0 {
+		fmt.Fprintln(w, "No tags.")
+	}
+	for _, t := range tags {
+		sha := t.Commit.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		fmt.Fprintf(w, "- %s (%s)\n", t.Name, sha)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tagEvent(typ, actor string, payload any) Event {
+	ev := Event{Type: typ, CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Payload: mustRaw(payload)}
+	ev.Actor.Login = actor
+	return ev
+}
+
+func TestTagActivities(t *testing.T) {
+	events := []Event{
+		tagEvent("ReleaseEvent", "alice", map[string]any{"action": "published", "release": map[string]any{"name": "Version 2", "tag_name": "v2.0.0"}}),
+		tagEvent("CreateEvent", "alice", map[string]any{"ref_type": "tag", "ref": "v2.0.0"}),
+		tagEvent("CreateEvent", "alice", map[string]any{"ref_type": "branch", "ref": "feature"}),
+		tagEvent("DeleteEvent", "bob", map[string]any{"ref_type": "tag", "ref": "v2.0.0-rc1"}),
+		tagEvent("ReleaseEvent", "bob", map[string]any{"action": "published", "release": map[string]any{"tag_name": "v1.9.9"}}),
+		tagEvent("PushEvent", "bob", map[string]any{"size": 1}),
+	}
+	got := tagActivities(events)
+	if len(got) != 3 {
+		t.Fatalf("want 3 activities, got %+v", got)
+	}
+	if got[0].Tag != "v2.0.0" || got[0].Action != "created" || got[0].Release != "Version 2" || got[0].IsRelease {
+		t.Fatalf("unexpected tag creation: %+v", got[0])
+	}
+	if got[1].Tag != "v2.0.0-rc1" || got[1].Action != "deleted" {
+		t.Fatalf("unexpected tag deletion: %+v", got[1])
+	}
+	if !got[2].IsRelease || got[2].Tag != "v1.9.9" || got[2].Release != "v1.9.9" {
+		t.Fatalf("unexpected release: %+v", got[2])
+	}
+}
+
+func TestPrintTags(t *testing.T) {
+	var buf strings.Builder
+	tag := Tag{Name: "v2.0.0"}
+	tag.Commit.SHA = "0123456789abcdef"
+	printTags(&buf, "alice/repo", []tagActivity{{
+		At: time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local), Actor: "alice", Tag: "v2.0.0", Action: "created", Release: "Version 2",
+	}}, []Tag{tag})
+	got := buf.String()
+	for _, want := range []string{
+		"- 2024-05-01: alice created tag v2.0.0 (release “Version 2”)",
+		"- v2.0.0 (0123456)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestFetchTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/alice/repo/tags" || r.URL.Query().Get("per_page") != "5" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`[{"name":"v1","commit":{"sha":"abc"}}]`))
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	tags, err := fetchTags(context.Background(), "alice/repo", 5)
+	if err != nil || len(tags) != 1 || tags[0].Name != "v1" {
+		t.Fatalf("got %+v, %v", tags, err)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// telegramAPI is the Bot API base URL; tests point it at a fake server.
+var telegramAPI = "https://api.telegram.org"
+
+// tgPollSeconds is how long getUpdates waits for a message before
+// answering with none.
+const tgPollSeconds = 30
+
+// telegramClient calls the Bot API. Its timeout outlasts a getUpdates long
+// poll, but keeps a stalled API from hanging watch.
+var telegramClient = &http.Client{Timeout: (tgPollSeconds + 30) * time.Second}
+
+// telegramBot sends watch alerts to a chat and answers /activity commands.
+type telegramBot struct {
+	token string
+	chat  string // alert destination; empty when only answering commands
+}
+
+// tgUpdate is the part of a Bot API update that commands need.
+type tgUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"` // public chats and channels only
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// call invokes a Bot API method with JSON parameters and decodes its result.
+func (b telegramBot) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, telegramAPI+"/bot"+b.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := telegramClient.Do(req)
+	if err != nil {
+		// The URL holds the token; don't echo it in errors.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	var r struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("telegram %s: %s", method, resp.Status)
+	}
+	if !r.OK {
+		return fmt.Errorf("telegram %s: %s", method, r.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(r.Result, result)
+	}
+	return nil
+}
+
+func (b telegramBot) send(ctx context.Context, chat any, text string) error {
+	return b.call(ctx, "sendMessage", map[string]any{
+		"chat_id":                  chat,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}, nil)
+}
+
+func (b telegramBot) alert(ctx context.Context, user string, entries []entry) error {
+	return b.send(ctx, b.chat, alertText(user, entries))
+}
+
+// serveCommands long-polls for messages and answers each /activity command
+// until ctx is done.
+func (b telegramBot) serveCommands(ctx context.Context) {
+	var offset int64
+	for ctx.Err() == nil {
+		var updates []tgUpdate
+		err := b.call(ctx, "getUpdates", map[string]any{"offset": offset, "timeout": tgPollSeconds, "allowed_updates": []string{"message"}}, &updates)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(warnings, "Warning: %v\n", err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(5 * time.Second):
+				}
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			b.handle(ctx, u)
+		}
+	}
+}
+
+// handle answers an /activity command; other messages are ignored. With
+// an alert chat set, only that chat is answered, as each query spends the
+// operator's GitHub rate limit.
+func (b telegramBot) handle(ctx context.Context, u tgUpdate) {
+	if u.Message == nil {
+		return
+	}
+	chat := u.Message.Chat
+	if b.chat != "" && b.chat != strconv.FormatInt(chat.ID, 10) && (chat.Username == "" || !strings.EqualFold(b.chat, "@"+chat.Username)) {
+		return
+	}
+	fields := strings.Fields(u.Message.Text)
+	// In groups commands may be addressed as /activity@SomeBot.
+	if len(fields) == 0 || strings.SplitN(fields[0], "@", 2)[0] != "/activity" {
+		return
+	}
+	text, err := queryActivity(ctx, fields[1:])
+	if err != nil {
+		text = "Error: " + err.Error()
+	}
+	if err := b.send(ctx, u.Message.Chat.ID, text); err != nil {
+		fmt.Fprintf(warnings, "Warning: %v\n", err)
+	}
+}
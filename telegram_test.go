@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramBot(t *testing.T) {
+	var sent []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botTOKEN/sendMessage":
+			var params map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&params)
+			sent = append(sent, params)
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+		case "/users/alice/events":
+			_, _ = w.Write([]byte(`[{"type":"WatchEvent","created_at":"2024-05-03T10:00:00Z","repo":{"name":"a/one"},"payload":{"action":"started"}}]`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":false,"description":"Unauthorized"}`))
+		}
+	}))
+	defer srv.Close()
+	restoreTG, restoreAPI := telegramAPI, apiURL
+	telegramAPI, apiURL = srv.URL, srv.URL
+	defer func() { telegramAPI, apiURL = restoreTG, restoreAPI }()
+
+	bot := telegramBot{token: "TOKEN", chat: "-100"}
+	e := entry{Summary: "Starred a/one"}
+	if err := bot.alert(context.Background(), "alice", []entry{e}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0]["chat_id"] != "-100" || sent[0]["text"] != "New GitHub activity by alice:\n- Starred a/one" {
+		t.Errorf("alert: %v", sent)
+	}
+
+	update := func(text string) tgUpdate {
+		var u tgUpdate
+		_ = json.Unmarshal([]byte(`{"update_id":1,"message":{"text":`+mustJSON(text)+`,"chat":{"id":42}}}`), &u)
+		return u
+	}
+	sent = nil
+	bot.handle(context.Background(), update("/activity alice")) // chat 42 isn't the alert chat
+	if len(sent) != 0 {
+		t.Errorf("answered another chat: %v", sent)
+	}
+	anyChat := telegramBot{token: "TOKEN"}
+	anyChat.handle(context.Background(), update("/activity@ActivityBot alice --n=1"))
+	anyChat.handle(context.Background(), update("hello"))
+	if len(sent) != 1 || sent[0]["chat_id"] != float64(42) || !strings.Contains(sent[0]["text"].(string), "Starred a/one") {
+		t.Errorf("command reply: %v", sent)
+	}
+
+	bad := telegramBot{token: "WRONG"}
+	if err := bad.send(context.Background(), 1, "x"); err == nil || !strings.Contains(err.Error(), "Unauthorized") || strings.Contains(err.Error(), "WRONG") {
+		t.Errorf("bad token: %v", err)
+	}
+}
+
+func mustJSON(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func TestTelegramBotTimeout(t *testing.T) {
+	restoreClient, restoreTG := telegramClient, telegramAPI
+	telegramClient = &http.Client{Timeout: 50 * time.Millisecond}
+	telegramAPI = stalledServer(t).URL
+	defer func() { telegramClient, telegramAPI = restoreClient, restoreTG }()
+
+	if err := (telegramBot{token: "TOKEN"}).send(context.Background(), 1, "x"); err == nil || strings.Contains(err.Error(), "TOKEN") {
+		t.Errorf("stalled API: got %v, want a timeout error without the token", err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateEvent is the data a --template is evaluated against. Payload holds
+// the event's full decoded payload for fields not surfaced elsewhere, e.g.
+// {{.Payload.ref}} or {{index .Payload.commits 0 "message"}}.
+type templateEvent struct {
+	ID        string
+	Type      string
+	CreatedAt time.Time
+	User      string
+	Actor     string
+	Repo      string
+	URL       string
+	Summary   string
+	Action    string
+	Number    int
+	Title     string
+	Payload   map[string]any
+}
+
+func newTemplateEvent(e entry) templateEvent {
+	d := detailsOf(e.Event)
+	te := templateEvent{
+		ID:        e.Event.ID,
+		Type:      e.Event.Type,
+		CreatedAt: e.Event.CreatedAt,
+		User:      e.User,
+		Actor:     e.Event.Actor.Login,
+		Repo:      e.Event.Repo.Name,
+		URL:       entityURL(e.Event),
+		Summary:   e.Summary,
+		Action:    d.Action,
+		Number:    d.Number,
+		Title:     d.Title,
+	}
+	_ = json.Unmarshal(e.Event.Payload, &te.Payload)
+	return te
+}
+
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"date": func(layout string, t time.Time) string {
+		return t.Local().Format(layout)
+	},
+}
+
+// parseEventTemplate builds the template set for the template format. file,
+// if set, holds templates named after event types ({{define "PushEvent"}})
+// plus an optional "default"; text is used for events without a template of
+// their own, falling back to "default" and then to the plain summary.
+func parseEventTemplate(text, file string) (*template.Template, error) {
+	t := template.New("event").Funcs(templateFuncs).Option("missingkey=zero")
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("template file: %w", err)
+		}
+		if _, err := t.New(filepath.Base(file)).Parse(string(b)); err != nil {
+			return nil, fmt.Errorf("invalid template file: %w", err)
+		}
+	}
+	if text == "" {
+		text = "{{.Summary}}"
+		if t.Lookup("default") != nil {
+			text = `{{template "default" .}}`
+		}
+	}
+	if _, err := t.Parse(text); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// templateRenderer prints each entry through a user-supplied template, one
+// event per line, using the event type's own template when one is defined.
+type templateRenderer struct {
+	w io.Writer
+	t *template.Template
+}
+
+func (r *templateRenderer) feed(_ string, _ []Event, entries []entry) error {
+	for _, e := range entries {
+		t := r.t
+		if typed := r.t.Lookup(e.Event.Type); typed != nil {
+			t = typed
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, newTemplateEvent(e)); err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+		line := b.String()
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		if _, err := io.WriteString(r.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *templateRenderer) flush() error { return nil }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	var buf strings.Builder
+	out, err := newRenderer(&buf, outputOptions{
+		Format:   "template",
+		Template: `{{.CreatedAt.Format "2006-01-02"}} {{upper .Action}} #{{.Number}} {{.Repo}} ref={{.Payload.ref}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := testEntry("PullRequestEvent", "alice/repo", "")
+	e.Event.Payload = mustRaw(map[string]any{
+		"action":       "opened",
+		"ref":          "main",
+		"pull_request": map[string]any{"number": 7},
+	})
+	_ = out.feed("alice", nil, []entry{e})
+	if got, want := buf.String(), "2024-05-01 OPENED #7 alice/repo ref=main\n"; got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestParseEventTemplate_Invalid(t *testing.T) {
+	if _, err := parseEventTemplate("{{.Type", ""); err == nil || !strings.Contains(err.Error(), "invalid template") {
+		t.Fatalf("expected invalid template error, got %v", err)
+	}
+}
+
+func TestTemplateFile_PerEventType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team.tmpl")
+	err := os.WriteFile(path, []byte(`
+{{define "PushEvent"}}push {{.Repo}}{{end}}
+{{define "default"}}{{.Type}}: {{.Summary}}{{end}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	out, err := newRenderer(&buf, outputOptions{Format: "template", TemplateFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = out.feed("alice", nil, []entry{
+		testEntry("PushEvent", "alice/a", "Pushed 1 commit(s) to alice/a"),
+		testEntry("WatchEvent", "alice/b", "Starred alice/b"),
+	})
+	want := "push alice/a\nWatchEvent: Starred alice/b\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestTemplateFile_FallsBackToSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "push.tmpl")
+	_ = os.WriteFile(path, []byte(`{{define "PushEvent"}}push{{end}}`), 0o644)
+	tmpl, err := parseEventTemplate("", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	r := &templateRenderer{w: &buf, t: tmpl}
+	_ = r.feed("alice", nil, []entry{testEntry("WatchEvent", "alice/b", "Starred alice/b")})
+	if got := buf.String(); got != "Starred alice/b\n" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTemplateFile_Missing(t *testing.T) {
+	if _, err := parseEventTemplate("", filepath.Join(t.TempDir(), "nope.tmpl")); err == nil {
+		t.Fatal("expected error for missing template file")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// isTerminal reports whether f is attached to a terminal rather than a file
+// or pipe.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns the width of the terminal f is attached to, or 0 when
+// it can't be determined (e.g. output is piped). $COLUMNS takes precedence so
+// users and scripts can override detection.
+func terminalWidth(f *os.File) int {
+	if c, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && c > 0 {
+		return c
+	}
+	if !isTerminal(f) {
+		return 0
+	}
+	w, _ := terminalSize(f)
+	return w
+}
+
+// terminalHeight is like terminalWidth but for rows, with $LINES as the
+// override.
+func terminalHeight(f *os.File) int {
+	if l, err := strconv.Atoi(os.Getenv("LINES")); err == nil && l > 0 {
+		return l
+	}
+	if !isTerminal(f) {
+		return 0
+	}
+	_, h := terminalSize(f)
+	return h
+}
+
+// runeWidth returns how many terminal columns r occupies: 0 for combining
+// marks and control characters, 2 for East Asian wide and emoji code points.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.IsControl(r) || r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F):
+		return 0
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F, // CJK ... Yi
+		r >= 0xAC00 && r <= 0xD7A3,                // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF,                // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F,                // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60,                // fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r == 0x2B50, r == 0x2B55, r == 0x2705, r == 0x274C, r == 0x26A1, // emoji-presentation symbols
+		r >= 0x1F300 && r <= 0x1FAFF, // emoji and pictographs
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
+
+// displayWidth returns how many terminal columns s occupies.
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// truncateWidth shortens s to at most width columns, ending it with "…" when
+// anything was cut. It never splits a multi-byte character.
+func truncateWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if displayWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	w := 0
+	for _, r := range s {
+		rw := runeWidth(r)
+		if w+rw > width-1 { // leave room for the ellipsis
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+	}
+	return b.String() + "…"
+}
+
+// padWidth pads s with spaces to width columns.
+func padWidth(s string, width int) string {
+	if n := width - displayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
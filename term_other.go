@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !windows
+
+package main
+
+import "os"
+
+func terminalSize(*os.File) (width, height int) { return 0, 0 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"abc": 3,
+		"“é”": 3,
+		"日本語": 6,
+		"é":  1, // combining acute accent
+		"⭐":   2,
+		"🚀":   2,
+	}
+	for in, want := range tests {
+		if got := displayWidth(in); got != want {
+			t.Fatalf("displayWidth(%q)=%d want %d", in, got, want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a longer title", 8, "a longe…"},
+		{"“Ünïcödé” title", 6, "“Ünïc…"},
+		{"日本語のタイトル", 7, "日本語…"},
+		{"anything", 0, ""},
+	}
+	for _, tc := range tests {
+		got := truncateWidth(tc.in, tc.width)
+		if got != tc.want {
+			t.Fatalf("truncateWidth(%q, %d)=%q want %q", tc.in, tc.width, got, tc.want)
+		}
+		if displayWidth(got) > tc.width {
+			t.Fatalf("truncateWidth(%q, %d) is %d columns wide", tc.in, tc.width, displayWidth(got))
+		}
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	if got := padWidth("日本", 6); got != "日本  " {
+		t.Fatalf("got %q", got)
+	}
+	if got := padWidth("toolong", 3); got != "toolong" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTerminalHeightOverride(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	t.Setenv("LINES", "")
+	if h := terminalHeight(f); h != 0 {
+		t.Errorf("regular file height = %d, want 0", h)
+	}
+	t.Setenv("LINES", "42")
+	if h := terminalHeight(f); h != 42 {
+		t.Errorf("$LINES height = %d, want 42", h)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var procGetConsoleScreenBufferInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("GetConsoleScreenBufferInfo")
+
+func terminalSize(f *os.File) (width, height int) {
+	var info struct {
+		Size, CursorPosition     struct{ X, Y int16 }
+		Attributes               uint16
+		Left, Top, Right, Bottom int16
+		MaximumWindowSize        struct{ X, Y int16 }
+	}
+	r, _, _ := procGetConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0, 0
+	}
+	return int(info.Right-info.Left) + 1, int(info.Bottom-info.Top) + 1
+}
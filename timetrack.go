@@ -0,0 +1,129 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// sessionGap is the quiet time that ends a working session.
+	sessionGap = 45 * time.Minute
+	// sessionLead is credited before each session's first event, for the
+	// work that led up to it (a lone push still took some time to write).
+	sessionLead = 15 * time.Minute
+)
+
+// session is a run of events with no gap longer than sessionGap.
+type session struct {
+	Start, End time.Time
+	Events     []Event // oldest first
+}
+
+func (s session) duration() time.Duration { return s.End.Sub(s.Start) + sessionLead }
+
+// sessions clusters events (newest first, as the API returns them) into
+// working sessions, oldest first.
+func sessions(events []Event) []session {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b Event) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	var out []session
+	for _, ev := range sorted {
+		if n := len(out); n > 0 && ev.CreatedAt.Sub(out[n-1].End) <= sessionGap {
+			out[n-1].End = ev.CreatedAt
+			out[n-1].Events = append(out[n-1].Events, ev)
+			continue
+		}
+		out = append(out, session{Start: ev.CreatedAt, End: ev.CreatedAt, Events: []Event{ev}})
+	}
+	return out
+}
+
+// timeEntry is the estimated time spent on one repository on one local day.
+type timeEntry struct {
+	Day      string // 2006-01-02
+	Repo     string
+	Duration time.Duration
+	Events   []Event // oldest first
+}
+
+// timeEntries splits each session's time across its repositories in
+// proportion to their events, and totals it per day (of the session's start)
+// and repository. Entries are ordered by day, then repository.
+func timeEntries(ss []session) []timeEntry {
+	index := map[[2]string]int{}
+	var out []timeEntry
+	for _, s := range ss {
+		day := s.Start.Local().Format("2006-01-02")
+		share := s.duration() / time.Duration(len(s.Events))
+		for _, ev := range s.Events {
+			key := [2]string{day, ev.Repo.Name}
+			i, ok := index[key]
+			if !ok {
+				i = len(out)
+				index[key] = i
+				out = append(out, timeEntry{Day: day, Repo: ev.Repo.Name})
+			}
+			out[i].Duration += share
+			out[i].Events = append(out[i].Events, ev)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b timeEntry) int {
+		return cmp.Or(strings.Compare(a.Day, b.Day), strings.Compare(a.Repo, b.Repo))
+	})
+	return out
+}
+
+// hours renders a duration as decimal hours, e.g. "1.5h".
+func hours(d time.Duration) string {
+	return fmt.Sprintf("%.1fh", d.Hours())
+}
+
+func printTimeEstimate(w io.Writer, user string, events []Event) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No recent public activity.")
+		return
+	}
+	ss := sessions(events)
+	entries := timeEntries(ss)
+	var total time.Duration
+	days := map[string]time.Duration{}
+	daySessions := map[string]int{}
+	repos := map[string]time.Duration{}
+	for _, s := range ss {
+		total += s.duration()
+		daySessions[s.Start.Local().Format("2006-01-02")]++
+	}
+	for _, e := range entries {
+		days[e.Day] += e.Duration
+		repos[e.Repo] += e.Duration
+	}
+	fmt.Fprintf(w, "%s: ~%s of GitHub-visible activity in %d sessions%s\n", user, hours(total), len(ss), sinceOldest(events))
+	fmt.Fprintf(w, "(a gap over %d minutes starts a new session; each gets %d minutes of lead time)\n", int(sessionGap.Minutes()), int(sessionLead.Minutes()))
+
+	fmt.Fprintln(w, "\nBy day:")
+	dayKeys := make([]string, 0, len(days))
+	for d := range days {
+		dayKeys = append(dayKeys, d)
+	}
+	slices.Sort(dayKeys)
+	for _, d := range dayKeys {
+		t, _ := time.ParseInLocation("2006-01-02", d, time.Local)
+		fmt.Fprintf(w, "  %s %s  %6s  (%d sessions)\n", d, t.Format("Mon"), hours(days[d]), daySessions[d])
+	}
+
+	fmt.Fprintln(w, "\nBy repository:")
+	repoKeys := make([]string, 0, len(repos))
+	width := 0
+	for r := range repos {
+		repoKeys = append(repoKeys, r)
+		width = max(width, displayWidth(r))
+	}
+	slices.SortFunc(repoKeys, func(a, b string) int { return cmp.Or(cmp.Compare(repos[b], repos[a]), strings.Compare(a, b)) })
+	for _, r := range repoKeys {
+		fmt.Fprintf(w, "  %s  %6s\n", padWidth(r, width), hours(repos[r]))
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// timedEvents builds newest-first events in repo at the given offsets from
+// 2024-05-03 09:00 UTC.
+func timedEvents(specs ...any) []Event {
+	base := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+	var events []Event
+	for i := 0; i < len(specs); i += 2 {
+		ev := Event{Type: "PushEvent", CreatedAt: base.Add(specs[i].(time.Duration))}
+		ev.Repo.Name = specs[i+1].(string)
+		events = append([]Event{ev}, events...)
+	}
+	return events
+}
+
+func TestSessions(t *testing.T) {
+	events := timedEvents(
+		0*time.Minute, "a/x",
+		30*time.Minute, "a/x",
+		75*time.Minute, "a/y", // 45 minutes after: same session
+		3*time.Hour, "a/x", // new session
+	)
+	ss := sessions(events)
+	if len(ss) != 2 || len(ss[0].Events) != 3 || len(ss[1].Events) != 1 {
+		t.Fatalf("got %+v", ss)
+	}
+	if got := ss[0].duration(); got != 90*time.Minute {
+		t.Errorf("first session: %v", got)
+	}
+	if got := ss[1].duration(); got != sessionLead {
+		t.Errorf("lone event: %v", got)
+	}
+}
+
+func TestTimeEntries(t *testing.T) {
+	pinClock(t)
+	events := timedEvents(
+		0*time.Minute, "a/x",
+		30*time.Minute, "a/x",
+		75*time.Minute, "a/y",
+		25*time.Hour, "a/y", // next day
+	)
+	got := timeEntries(sessions(events))
+	want := []struct {
+		day, repo string
+		d         time.Duration
+		n         int
+	}{
+		{"2024-05-03", "a/x", 60 * time.Minute, 2},
+		{"2024-05-03", "a/y", 30 * time.Minute, 1},
+		{"2024-05-04", "a/y", 15 * time.Minute, 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i, w := range want {
+		if got[i].Day != w.day || got[i].Repo != w.repo || got[i].Duration != w.d || len(got[i].Events) != w.n {
+			t.Errorf("%d: got %s %s %v %d", i, got[i].Day, got[i].Repo, got[i].Duration, len(got[i].Events))
+		}
+	}
+}
+
+func TestPrintTimeEstimate(t *testing.T) {
+	pinClock(t)
+	var b strings.Builder
+	printTimeEstimate(&b, "alice", timedEvents(0*time.Minute, "a/x", 30*time.Minute, "a/x", 75*time.Minute, "a/y"))
+	out := b.String()
+	for _, want := range []string{
+		"alice: ~1.5h of GitHub-visible activity in 1 sessions since May 3, 2024",
+		"2024-05-03 Fri    1.5h  (1 sessions)",
+		"a/x    1.0h",
+		"a/y    0.5h",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
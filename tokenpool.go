@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// poolReserve is how many requests a pooled token keeps in reserve: once it
+// has no more than this left, requests move to the token with the most.
+const poolReserve = 100
+
+// pooledToken is a token in a pool and its core rate limit as of the last
+// response that used it.
+type pooledToken struct {
+	Cred      credential
+	Limit     int // 0 until a response reports it
+	Remaining int
+	Reset     time.Time
+}
+
+// left is how many requests t has, as far as is known at time at: unknown
+// and reset quotas count as untouched.
+func (t *pooledToken) left(at time.Time) int {
+	if t.Limit == 0 || !at.Before(t.Reset) {
+		return math.MaxInt
+	}
+	return t.Remaining
+}
+
+// tokenPool spreads requests over several tokens for one host, staying on
+// one token until its quota runs low. Safe for concurrent use.
+type tokenPool struct {
+	mu      sync.Mutex
+	tokens  []*pooledToken
+	current int
+}
+
+// pick returns the token for the next request.
+func (p *tokenPool) pick() credential {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	at := now()
+	cur := p.tokens[p.current]
+	if cur.left(at) > poolReserve {
+		return cur.Cred
+	}
+	best := p.current
+	for i, t := range p.tokens {
+		if t.left(at) > p.tokens[best].left(at) {
+			best = i
+		}
+	}
+	if best != p.current {
+		debugf("token pool: %s has %d requests left; switching to %s", cur.Cred.Source, cur.Remaining, p.tokens[best].Cred.Source)
+		p.current = best
+	}
+	return p.tokens[best].Cred
+}
+
+// observe records the core rate limit a response made with token reports.
+// Other buckets, such as search, are limited separately and ignored.
+func (p *tokenPool) observe(token string, h http.Header) {
+	if r := h.Get("X-RateLimit-Resource"); r != "" && r != "core" {
+		return
+	}
+	limit, err1 := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, err2 := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, err3 := parseUnix(h.Get("X-RateLimit-Reset"))
+	if err1 != nil || err2 != nil || err3 != nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range p.tokens {
+		if t.Cred.Token == token {
+			t.Limit, t.Remaining, t.Reset = limit, remaining, reset
+		}
+	}
+}
+
+// poolConfig is the config file's "tokens" object, mapping hosts to token
+// lists, e.g. {"tokens": {"github.com": ["ghp_a", "ghp_b"]}}. Set by main.
+var poolConfig map[string][]string
+
+// tokenPools holds each host's pool once built; nil means the host has none.
+var tokenPools = struct {
+	sync.Mutex
+	m map[string]*tokenPool
+}{m: map[string]*tokenPool{}}
+
+// poolVar is the environment variable holding host's token pool, separated
+// by commas or whitespace.
+func poolVar(host string) string {
+	if !isDotCom(host) {
+		return "GITHUB_ENTERPRISE_TOKENS"
+	}
+	return "GITHUB_TOKENS"
+}
+
+// poolFor is host's token pool from poolVar and poolConfig, or nil if
+// neither lists any tokens.
+func poolFor(host string) *tokenPool {
+	tokenPools.Lock()
+	defer tokenPools.Unlock()
+	key := tokenHost(host)
+	if p, ok := tokenPools.m[key]; ok {
+		return p
+	}
+	var p *tokenPool
+	add := func(token, source string) {
+		if p == nil {
+			p = &tokenPool{}
+		}
+		for _, t := range p.tokens {
+			if t.Cred.Token == token {
+				return
+			}
+		}
+		p.tokens = append(p.tokens, &pooledToken{Cred: credential{Token: token, Source: source}})
+	}
+	name := poolVar(host)
+	env := strings.FieldsFunc(os.Getenv(name), func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	for i, t := range env {
+		add(t, fmt.Sprintf("$%s #%d", name, i+1))
+	}
+	for i, t := range poolConfig[key] {
+		if t = strings.TrimSpace(t); t != "" {
+			add(t, fmt.Sprintf("config tokens.%s #%d", key, i+1))
+		}
+	}
+	tokenPools.m[key] = p
+	return p
+}
+
+// observeQuota updates the pool c came from, if any, with the rate limit
+// in a response to host.
+func observeQuota(host string, c credential, h http.Header) {
+	if c.Token == "" {
+		return
+	}
+	if p := poolFor(host); p != nil {
+		p.observe(c.Token, h)
+	}
+}
+
+// loadPoolConfig reads the "tokens" object from the config file at path.
+func loadPoolConfig(path string) (map[string][]string, error) {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := cfg["tokens"]
+	if !ok {
+		return nil, nil
+	}
+	var pools map[string][]string
+	if err := json.Unmarshal(raw, &pools); err != nil {
+		return nil, fmt.Errorf(`"tokens": want an object of host: [token, ...]: %w`, err)
+	}
+	out := map[string][]string{}
+	for host, tokens := range pools {
+		out[tokenHost(host)] = append(out[tokenHost(host)], tokens...)
+	}
+	return out, nil
+}
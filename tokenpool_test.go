@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// quotaHeader is a response header reporting a core rate limit.
+func quotaHeader(remaining int, reset time.Time) http.Header {
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	h.Set("X-RateLimit-Resource", "core")
+	return h
+}
+
+func TestTokenPoolRotates(t *testing.T) {
+	pinClock(t)
+	reset := now().Add(30 * time.Minute)
+	p := &tokenPool{tokens: []*pooledToken{
+		{Cred: credential{Token: "a", Source: "#1"}},
+		{Cred: credential{Token: "b", Source: "#2"}},
+	}}
+	if got := p.pick().Token; got != "a" {
+		t.Fatalf("first pick: %q", got)
+	}
+	p.observe("a", quotaHeader(4000, reset))
+	if got := p.pick().Token; got != "a" {
+		t.Errorf("with quota left, switched to %q", got)
+	}
+	p.observe("a", quotaHeader(poolReserve, reset))
+	if got := p.pick().Token; got != "b" {
+		t.Errorf("at the reserve, picked %q", got)
+	}
+	search := quotaHeader(0, reset)
+	search.Set("X-RateLimit-Resource", "search")
+	p.observe("b", search)
+	if got := p.pick().Token; got != "b" {
+		t.Errorf("search limit moved the pool to %q", got)
+	}
+	p.observe("b", quotaHeader(10, reset))
+	if got := p.pick().Token; got != "a" {
+		t.Errorf("with b nearly used up, picked %q", got)
+	}
+	now = func() time.Time { return reset.Add(time.Second) }
+	if got := p.pick().Token; got != "a" {
+		t.Errorf("after the reset, picked %q", got)
+	}
+}
+
+func TestPoolFor(t *testing.T) {
+	t.Setenv("GITHUB_TOKENS", "t1, t2\nt1")
+	t.Setenv("GITHUB_ENTERPRISE_TOKENS", "")
+	t.Setenv("GH_TOKEN", "single")
+	restore := poolConfig
+	poolConfig = map[string][]string{"github.com": {"t3"}}
+	tokenPools.m = map[string]*tokenPool{}
+	defer func() { poolConfig, tokenPools.m = restore, map[string]*tokenPool{} }()
+
+	p := poolFor("api.github.com")
+	if p == nil || len(p.tokens) != 3 {
+		t.Fatalf("pool: %+v", p)
+	}
+	for i, want := range []string{"$GITHUB_TOKENS #1", "$GITHUB_TOKENS #2", "config tokens.github.com #1"} {
+		if got := p.tokens[i].Cred.Source; got != want {
+			t.Errorf("token %d from %q, want %q", i, got, want)
+		}
+	}
+	if c := hostCredential(context.Background(), "api.github.com"); c.Source != "$GITHUB_TOKENS #1" {
+		t.Errorf("pool doesn't take precedence: %+v", c)
+	}
+	if poolFor("github.example.com") != nil {
+		t.Error("github.com tokens pooled for an Enterprise host")
+	}
+}
+
+func TestLoadPoolConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"n": 5, "tokens": {"api.github.com": ["a"], "GitHub.example.com": ["b", "c"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadPoolConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got["github.com"]) != 1 || len(got["github.example.com"]) != 2 {
+		t.Errorf("got %v", got)
+	}
+	if err := os.WriteFile(path, []byte(`{"tokens": ["a", "b"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPoolConfig(path); err == nil {
+		t.Error("a bare token list was accepted")
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// selfRepo is where release binaries are published.
+const selfRepo = "Khoa-Trinh/github-user-activity-cli"
+
+// maxBinaryBytes caps a downloaded release asset.
+const maxBinaryBytes = 128 << 20
+
+// checksumsAsset lists "<sha256>  <asset>" lines, as written by sha256sum.
+const checksumsAsset = "checksums.txt"
+
+// signatureAsset holds the base64 Ed25519 signature of checksumsAsset, made
+// with the private half of releaseKey.
+const signatureAsset = checksumsAsset + ".sig"
+
+// releaseKey is the base64 Ed25519 public key release checksums are signed
+// with, embedded by release builds with
+//
+//	-ldflags "-X main.releaseKey=<base64 public key>"
+//
+// Builds without one cannot verify releases, so they refuse to update.
+var releaseKey = ""
+
+// Release is the subset of the releases API used by update.
+type Release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+func (r Release) assetURL(name string) string {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL
+		}
+	}
+	return ""
+}
+
+// assetName is the release asset built for this platform.
+func assetName() string {
+	name := "github-activity_" + runtime.GOOS + "_" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func runUpdate(args []string) int {
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	check := fs.Bool("check", false, "Only report whether a newer release exists.")
+	force := fs.Bool("force", false, "Install the latest release even if it is not newer (or this is a dev build).")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s update [options]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Replaces this binary with the latest GitHub release after verifying the signature")
+		fmt.Fprintln(fs.Output(), "of its checksums file and the binary's SHA-256 checksum.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx := context.Background()
+	var rel Release
+	if err := getJSON(ctx, apiURL+"/repos/"+selfRepo+"/releases/latest", &rel); err != nil {
+		if errors.Is(err, errNotFound) {
+			err = errors.New("no releases published")
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+
+	newer := compareVersions(rel.TagName, version) > 0
+	switch {
+	case *check && newer:
+		fmt.Printf("Update available: %s → %s\n", version, rel.TagName)
+		return 0
+	case *check || (!newer && !*force):
+		fmt.Printf("Already up to date (%s; latest release is %s).\n", version, rel.TagName)
+		return 0
+	case version == "dev" && !*force:
+		fmt.Fprintln(os.Stderr, "Error: this is a development build; use --force to replace it with", rel.TagName)
+		return 1
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: locating executable:", err)
+		return 1
+	}
+	if err := installRelease(ctx, rel, exe); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Printf("Updated %s → %s\n", version, rel.TagName)
+	return 0
+}
+
+// installRelease downloads this platform's asset from rel, checks it against
+// the release's checksums file once that file's signature verifies, and
+// replaces the file at exe with it.
+func installRelease(ctx context.Context, rel Release, exe string) error {
+	name := assetName()
+	binURL, sumURL, sigURL := rel.assetURL(name), rel.assetURL(checksumsAsset), rel.assetURL(signatureAsset)
+	if binURL == "" {
+		return fmt.Errorf("release %s has no binary for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	if sumURL == "" {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", rel.TagName, checksumsAsset)
+	}
+	if sigURL == "" {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", rel.TagName, signatureAsset)
+	}
+
+	sums, err := download(ctx, sumURL)
+	if err != nil {
+		return err
+	}
+	sig, err := download(ctx, sigURL)
+	if err != nil {
+		return err
+	}
+	if err := verifyChecksums(sums, sig); err != nil {
+		return err
+	}
+	want, err := lookupChecksum(sums, name)
+	if err != nil {
+		return err
+	}
+	bin, err := download(ctx, binURL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(bin)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return replaceFile(exe, bin)
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBinaryBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	if len(body) > maxBinaryBytes {
+		return nil, fmt.Errorf("download exceeds %d bytes: %s", maxBinaryBytes, url)
+	}
+	return body, nil
+}
+
+// verifyChecksums checks sig, a base64 Ed25519 signature, over sums with
+// releaseKey.
+func verifyChecksums(sums, sig []byte) error {
+	key, err := base64.StdEncoding.DecodeString(releaseKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("this build has no release signing key; refusing to install an unverified binary")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(raw) != ed25519.SignatureSize || !ed25519.Verify(key, sums, raw) {
+		return fmt.Errorf("signature of %s does not verify; refusing to install", checksumsAsset)
+	}
+	return nil
+}
+
+// lookupChecksum finds the hex SHA-256 for name in sha256sum output.
+func lookupChecksum(sums []byte, name string) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(sums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		if _, err := hex.DecodeString(fields[0]); err != nil || len(fields[0]) != 2*sha256.Size {
+			return "", fmt.Errorf("malformed checksum for %s", name)
+		}
+		return strings.ToLower(fields[0]), nil
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// replaceFile swaps the file at path for data, keeping its permissions. The
+// old file is moved aside first because Windows cannot overwrite a running
+// executable, only rename it.
+func replaceFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".github-activity-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write next to %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	old := path + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Rename(old, path)
+		return err
+	}
+	_ = os.Remove(old) // fails on Windows while the old binary runs; harmless
+	return nil
+}
+
+// compareVersions orders "vMAJOR.MINOR.PATCH" strings numerically. Anything
+// unparseable (such as "dev") sorts before every release.
+func compareVersions(a, b string) int {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	s, _, _ = strings.Cut(strings.TrimPrefix(s, "v"), "-") // ignore pre-release tags
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v1.2", "v1.2.1", -1},
+		{"1.0.0", "v1.0.0", 0},
+		{"v2.0.0-rc1", "v1.9.0", 1},
+		{"v0.1.0", "dev", 1},
+		{"dev", "v0.1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLookupChecksum(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	sums := []byte(sum + "  other\n" + strings.ToUpper(sum) + " *github-activity_linux_amd64\n")
+	if got, err := lookupChecksum(sums, "github-activity_linux_amd64"); err != nil || got != sum {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := lookupChecksum(sums, "missing"); err == nil {
+		t.Error("expected error for unlisted asset")
+	}
+	if _, err := lookupChecksum([]byte("xyz  missing\n"), "missing"); err == nil {
+		t.Error("expected error for malformed checksum")
+	}
+}
+
+// signReleases makes releaseKey a fresh key for the test and returns its
+// private half.
+func signReleases(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := releaseKey
+	releaseKey = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { releaseKey = old })
+	return priv
+}
+
+func TestInstallRelease(t *testing.T) {
+	priv := signReleases(t)
+	bin := []byte("new binary")
+	sum := sha256.Sum256(bin)
+	checksums := hex.EncodeToString(sum[:]) + "  " + assetName() + "\n"
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(checksums)))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bin":
+			_, _ = w.Write(bin)
+		case "/sums":
+			_, _ = w.Write([]byte(checksums))
+		case "/sig":
+			_, _ = w.Write([]byte(sig + "\n"))
+		case "/bad":
+			_, _ = w.Write([]byte("tampered"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	release := func(binPath string) Release {
+		return Release{TagName: "v9.9.9", Assets: []releaseAsset{
+			{assetName(), srv.URL + binPath},
+			{checksumsAsset, srv.URL + "/sums"},
+			{signatureAsset, srv.URL + "/sig"},
+		}}
+	}
+
+	exe := filepath.Join(t.TempDir(), "github-activity")
+	if err := os.WriteFile(exe, []byte("old binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := installRelease(context.Background(), release("/bad"), exe)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old binary" {
+		t.Fatalf("binary replaced despite bad checksum: %q", got)
+	}
+
+	if err := installRelease(context.Background(), release("/bin"), exe); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(exe)
+	if string(got) != "new binary" {
+		t.Errorf("binary = %q", got)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm() != 0o755 {
+		t.Errorf("mode = %v, want 0755", info.Mode().Perm())
+	}
+	if _, err := os.Stat(exe + ".old"); !os.IsNotExist(err) {
+		t.Errorf("old binary left behind: %v", err)
+	}
+}
+
+func TestInstallReleaseSignature(t *testing.T) {
+	priv := signReleases(t)
+	bin := []byte("tampered binary")
+	sum := sha256.Sum256(bin)
+	checksums := hex.EncodeToString(sum[:]) + "  " + assetName() + "\n"
+	// Signed before an attacker swapped in their own binary's checksum.
+	genuine := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("0000  "+assetName()+"\n")))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bin":
+			_, _ = w.Write(bin)
+		case "/sums":
+			_, _ = w.Write([]byte(checksums))
+		case "/sig":
+			_, _ = w.Write([]byte(genuine))
+		case "/garbage":
+			_, _ = w.Write([]byte("not a signature"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	exe := filepath.Join(t.TempDir(), "github-activity")
+	if err := os.WriteFile(exe, []byte("old binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	release := func(sigPath string) Release {
+		assets := []releaseAsset{
+			{assetName(), srv.URL + "/bin"},
+			{checksumsAsset, srv.URL + "/sums"},
+		}
+		if sigPath != "" {
+			assets = append(assets, releaseAsset{signatureAsset, srv.URL + sigPath})
+		}
+		return Release{TagName: "v9.9.9", Assets: assets}
+	}
+
+	for _, tt := range []struct{ sig, want string }{
+		{"/sig", "does not verify"},
+		{"/garbage", "does not verify"},
+		{"", "has no " + signatureAsset},
+	} {
+		err := installRelease(context.Background(), release(tt.sig), exe)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("signature %q: got %v, want %q", tt.sig, err, tt.want)
+		}
+	}
+
+	releaseKey = ""
+	if err := installRelease(context.Background(), release("/sig"), exe); err == nil || !strings.Contains(err.Error(), "no release signing key") {
+		t.Errorf("without a key: got %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old binary" {
+		t.Fatalf("binary replaced despite bad signature: %q", got)
+	}
+}
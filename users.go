@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// expandStdinUsers replaces a "-" argument with the logins read from r, one
+// per line, e.g. piped from gh api orgs/acme/members --jq '.[].login'. Blank
+// lines, # comments, and repeated logins are skipped.
+func expandStdinUsers(args []string, r io.Reader) ([]string, error) {
+	var users []string
+	seen := map[string]bool{}
+	add := func(u string) {
+		if key := strings.ToLower(u); !seen[key] {
+			seen[key] = true
+			users = append(users, u)
+		}
+	}
+	read := false
+	for _, arg := range args {
+		if arg != "-" {
+			add(arg)
+			continue
+		}
+		if read {
+			continue
+		}
+		read = true
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				add(line)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if len(users) == 0 {
+		return nil, errors.New("no usernames on standard input")
+	}
+	return users, nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandStdinUsers(t *testing.T) {
+	stdin := "alice\n\n# maintainers\n  bob  \nAlice\ncarol\n"
+	got, err := expandStdinUsers([]string{"dave", "-", "bob", "-"}, strings.NewReader(stdin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dave", "alice", "bob", "carol"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := expandStdinUsers([]string{"-"}, strings.NewReader("\n# none\n")); err == nil {
+		t.Error("empty stdin: want error")
+	}
+}
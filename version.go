@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Build metadata, set by release builds with
+//
+//	-ldflags "-X main.version=v1.2.3 -X main.commit=abc1234 -X main.buildDate=2024-05-01T12:00:00Z"
+//
+// When they are empty, commit and buildDate fall back to the VCS stamp Go
+// embeds in binaries built from a checkout.
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+// features lists optional capabilities compiled into this binary, so scripts
+// can detect them from `version --json`. Build-tagged files append to it
+// from init, e.g. keyring_unix.go adds "keyring-secret-service".
+var features []string
+
+// buildInfo is what the version command reports.
+type buildInfo struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	BuildDate string   `json:"build_date"`
+	Modified  bool     `json:"modified"`
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+	Features  []string `json:"features"`
+}
+
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Features:  features,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+func runVersion(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "Print build information as JSON.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s version [--json]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints the version, commit, build date, Go version, and compiled-in features.")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := printVersion(os.Stdout, currentBuildInfo(), *jsonOut); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+func printVersion(w io.Writer, info buildInfo, asJSON bool) error {
+	if asJSON {
+		if info.Features == nil {
+			info.Features = []string{} // always an array for scripts
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	commit := info.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	if info.Modified {
+		commit += "-dirty"
+	}
+	fmt.Fprintf(w, "github-activity %s\n", info.Version)
+	fmt.Fprintf(w, "  commit:   %s\n", commit)
+	if info.BuildDate != "" {
+		fmt.Fprintf(w, "  built:    %s\n", info.BuildDate)
+	}
+	fmt.Fprintf(w, "  go:       %s %s\n", info.GoVersion, info.Platform)
+	if len(info.Features) > 0 {
+		fmt.Fprintf(w, "  features: %s\n", strings.Join(info.Features, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPrintVersion(t *testing.T) {
+	info := buildInfo{
+		Version:   "v1.2.3",
+		Commit:    "0123456789abcdef",
+		BuildDate: "2024-05-01T12:00:00Z",
+		Modified:  true,
+		GoVersion: "go1.24.5",
+		Platform:  "linux/amd64",
+	}
+
+	var b strings.Builder
+	if err := printVersion(&b, info, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"github-activity v1.2.3", "commit:   0123456789ab-dirty", "built:    2024-05-01T12:00:00Z", "go:       go1.24.5 linux/amd64"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, b.String())
+		}
+	}
+
+	b.Reset()
+	if err := printVersion(&b, info, true); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["version"] != "v1.2.3" || got["commit"] != "0123456789abcdef" || got["go_version"] != "go1.24.5" {
+		t.Errorf("got %v", got)
+	}
+	if f, ok := got["features"].([]any); !ok || len(f) != 0 {
+		t.Errorf("features = %v, want empty array", got["features"])
+	}
+}
+
+func TestCurrentBuildInfoPrefersLdflags(t *testing.T) {
+	restore := commit
+	commit = "fromldflags"
+	defer func() { commit = restore }()
+	if got := currentBuildInfo().Commit; got != "fromldflags" {
+		t.Errorf("Commit = %q", got)
+	}
+}
+
+func TestCurrentBuildInfoFeatures(t *testing.T) {
+	want := map[string]string{"linux": "keyring-secret-service", "darwin": "keyring-macos", "windows": "keyring-windows"}[runtime.GOOS]
+	if want == "" {
+		t.Skip("no keyring support on " + runtime.GOOS)
+	}
+	if got := currentBuildInfo().Features; !slices.Contains(got, want) {
+		t.Errorf("Features = %v, want %q", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"time"
+)
+
+// alertSink is somewhere watch sends new events: the terminal, a chat, or a
+// webhook.
+type alertSink interface {
+	alert(ctx context.Context, user string, entries []entry) error
+}
+
+// sinkClient sends alerts. Its timeout keeps a receiver that never answers
+// from holding up every later alert.
+var sinkClient = &http.Client{Timeout: 30 * time.Second}
+
+// watcher remembers which events each user's feed has shown, so each poll
+// reports only what is new.
+type watcher struct {
+	seen map[string]map[string]bool // user -> event IDs
+}
+
+func newWatcher() *watcher { return &watcher{seen: map[string]map[string]bool{}} }
+
+// newEvents returns the events not seen in earlier polls, newest first, and
+// remembers the current feed. The first poll for a user only takes note of
+// what is already there.
+func (w *watcher) newEvents(user string, events []Event) []Event {
+	seen, primed := w.seen[user]
+	current := map[string]bool{}
+	var fresh []Event
+	for _, ev := range events {
+		current[ev.ID] = true
+		if primed && !seen[ev.ID] {
+			fresh = append(fresh, ev)
+		}
+	}
+	w.seen[user] = current // forget events that dropped off the feed
+	return fresh
+}
+
+// caughtUp reports whether a user's events so far reach back to one an
+// earlier poll saw, so paging can stop there. Before the first poll, one page
+// is enough to take note of.
+func (w *watcher) caughtUp(user string) func([]Event) bool {
+	seen, primed := w.seen[user]
+	return func(events []Event) bool {
+		return !primed || slices.ContainsFunc(events, func(ev Event) bool { return seen[ev.ID] })
+	}
+}
+
+// poll fetches each user's new events, paging back to the last one seen, and sends new events that pass f to
+// every sink, oldest first. Failures are reported and don't stop the watch.
+func (w *watcher) poll(ctx context.Context, users []string, f filters, sinks []alertSink) {
+	for _, user := range users {
+		events, err := fetchEventPages(ctx, user, pageOptions{PerPage: 100, Enough: w.caughtUp(user)})
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: %s: %v\n", user, err)
+			continue
+		}
+		fresh := w.newEvents(user, events)
+		entries := selectEntries(user, fresh, f, len(fresh)+1)
+		if len(entries) == 0 {
+			continue
+		}
+		slices.Reverse(entries)
+		for _, s := range sinks {
+			if err := s.alert(ctx, user, entries); err != nil {
+				fmt.Fprintf(warnings, "Warning: sending alert: %v\n", err)
+			}
+		}
+	}
+}
+
+// terminalSink prints new events as they arrive. On a terminal it can also
+// ring the bell and put a count in the window (or tmux pane) title, so a
+// watch running in the background gets noticed.
+type terminalSink struct {
+	w     io.Writer
+	bell  bool
+	title bool
+}
+
+func (s terminalSink) alert(_ context.Context, user string, entries []entry) error {
+	for _, e := range entries {
+		fmt.Fprintf(s.w, "%s %s: %s\n", e.Event.CreatedAt.Local().Format("2006-01-02 15:04"), user, e.Summary)
+	}
+	if s.title {
+		noun := "events"
+		if len(entries) == 1 {
+			noun = "event"
+		}
+		// OSC 0 sets the icon name and window title.
+		fmt.Fprintf(s.w, "\x1b]0;%d new %s — %s\a", len(entries), noun, user)
+	}
+	if s.bell {
+		fmt.Fprint(s.w, "\a")
+	}
+	return nil
+}
+
+// alertText is the plain-text body chat sinks send for new events.
+func alertText(user string, entries []entry) string {
+	text := fmt.Sprintf("New GitHub activity by %s:", user)
+	for _, e := range entries {
+		text += "\n- " + e.Summary
+	}
+	return text
+}
+
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", 5*time.Minute, "Time between polls (at least 1m).")
+	bell := fs.Bool("bell", false, "Ring the terminal bell when new events arrive.")
+	var typeNames stringList
+	fs.Var(&typeNames, "type", "Only alert on these event types (repeatable or comma-separated; aliases as for the main command).")
+	tgToken := fs.String("telegram-token", "", "Telegram bot token (default: $TELEGRAM_BOT_TOKEN). With a token the bot also answers /activity <user> commands.")
+	tgChat := fs.String("telegram-chat", "", "Telegram chat ID to send alerts to.")
+	mxServer := fs.String("matrix-homeserver", "", "Matrix homeserver URL to send alerts through, e.g. https://matrix.example.org.")
+	mxToken := fs.String("matrix-token", "", "Matrix access token of the sending account (default: $MATRIX_ACCESS_TOKEN).")
+	mxRoom := fs.String("matrix-room", "", "Matrix room ID to send alerts to, e.g. !abc123:example.org (the account must have joined it).")
+	var hooks, hookHeaders repeatedFlag
+	fs.Var(&hooks, "webhook", "URL to POST each new event to as JSON (repeatable).")
+	fs.Var(&hookHeaders, "webhook-header", "Header to send with webhooks, as 'Name: value' (repeatable), e.g. an Authorization header.")
+	hookBody := fs.String("webhook-template", "", `Go template for the webhook body instead of the event's JSON, e.g. '{"value1": {{json .Summary}}}'. Has the --template fields plus json.`)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s watch [options] <github-username>...\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Polls users' activity and reports new events as they appear: on the terminal,")
+		fmt.Fprintln(fs.Output(), "and in any configured chats. Runs until interrupted. On a terminal, the window")
+		fmt.Fprintln(fs.Output(), "title shows the latest count, e.g. \"3 new events — alice\".")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if !parseFlags(fs, args) {
+		return 0
+	}
+	if *tgToken == "" {
+		*tgToken = os.Getenv("TELEGRAM_BOT_TOKEN")
+	}
+	if fs.NArg() == 0 && *tgToken == "" {
+		fs.Usage()
+		return 2
+	}
+	if *interval < time.Minute {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be at least 1m")
+		return 2
+	}
+	if *tgChat != "" && *tgToken == "" {
+		fmt.Fprintln(os.Stderr, "Error: --telegram-chat needs a bot token (--telegram-token or $TELEGRAM_BOT_TOKEN)")
+		return 2
+	}
+	if *mxToken == "" {
+		*mxToken = os.Getenv("MATRIX_ACCESS_TOKEN")
+	}
+	if (*mxServer != "" || *mxRoom != "") && (*mxServer == "" || *mxRoom == "" || *mxToken == "") {
+		fmt.Fprintln(os.Stderr, "Error: Matrix alerts need --matrix-homeserver, --matrix-room, and a token (--matrix-token or $MATRIX_ACCESS_TOKEN)")
+		return 2
+	}
+	if len(hooks) == 0 && (len(hookHeaders) > 0 || *hookBody != "") {
+		fmt.Fprintln(os.Stderr, "Error: --webhook-header and --webhook-template need --webhook")
+		return 2
+	}
+	var hookSinks []alertSink
+	for _, u := range hooks {
+		s, err := newWebhookSink(u, hookHeaders, *hookBody)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		hookSinks = append(hookSinks, s)
+	}
+	types, err := resolveEventTypes(typeNames)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	tty := isTerminal(os.Stdout)
+	sinks := []alertSink{terminalSink{w: os.Stdout, bell: *bell && tty, title: tty}}
+	if *tgToken != "" {
+		bot := telegramBot{token: *tgToken, chat: *tgChat}
+		if *tgChat != "" {
+			sinks = append(sinks, bot)
+		}
+		go bot.serveCommands(ctx)
+	}
+	if *mxServer != "" {
+		sinks = append(sinks, matrixSink{homeserver: *mxServer, token: *mxToken, room: *mxRoom})
+	}
+	sinks = append(sinks, hookSinks...)
+
+	w := newWatcher()
+	users := fs.Args()
+	f := filters{Types: types}
+	for {
+		w.poll(ctx, users, f, sinks)
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(*interval):
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWatcherNewEvents(t *testing.T) {
+	ev := func(id string) Event { return Event{ID: id} }
+	w := newWatcher()
+	if got := w.newEvents("alice", []Event{ev("2"), ev("1")}); len(got) != 0 {
+		t.Fatalf("first poll reported %v", got)
+	}
+	got := w.newEvents("alice", []Event{ev("4"), ev("3"), ev("2")})
+	if len(got) != 2 || got[0].ID != "4" || got[1].ID != "3" {
+		t.Errorf("second poll: %v", got)
+	}
+	if got := w.newEvents("alice", []Event{ev("4"), ev("3")}); len(got) != 0 {
+		t.Errorf("unchanged feed: %v", got)
+	}
+	if got := w.newEvents("bob", []Event{ev("9")}); len(got) != 0 {
+		t.Errorf("new user's first poll: %v", got)
+	}
+}
+
+type recordingSink struct{ alerts []string }
+
+func (s *recordingSink) alert(_ context.Context, user string, entries []entry) error {
+	s.alerts = append(s.alerts, alertText(user, entries))
+	return nil
+}
+
+func TestWatcherPoll(t *testing.T) {
+	var feed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "["+strings.Join(feed, ",")+"]")
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	star := func(id, repo string) string {
+		return `{"id":"` + id + `","type":"WatchEvent","created_at":"2024-05-03T10:00:00Z","repo":{"name":"` + repo + `"},"payload":{"action":"started"}}`
+	}
+	push := `{"id":"3","type":"PushEvent","created_at":"2024-05-03T10:00:00Z","repo":{"name":"a/p"},"payload":{"size":1}}`
+	sink := &recordingSink{}
+	w := newWatcher()
+	f := filters{Types: []string{"WatchEvent"}}
+
+	feed = []string{star("1", "a/old")}
+	w.poll(context.Background(), []string{"alice"}, f, []alertSink{sink})
+	feed = []string{star("4", "a/newest"), push, star("2", "a/new"), star("1", "a/old")}
+	w.poll(context.Background(), []string{"alice"}, f, []alertSink{sink})
+	want := "New GitHub activity by alice:\n- Starred a/new\n- Starred a/newest"
+	if len(sink.alerts) != 1 || sink.alerts[0] != want {
+		t.Errorf("alerts: %q", sink.alerts)
+	}
+}
+
+func TestWatcherPollPages(t *testing.T) {
+	var feed [][]string // pages of event IDs, newest first
+	var fetched []string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		fetched = append(fetched, strconv.Itoa(page))
+		if page < len(feed) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/alice/events?per_page=100&page=%d>; rel="next"`, srv.URL, page+1))
+		}
+		var events []string
+		for _, id := range feed[page-1] {
+			events = append(events, `{"id":"`+id+`","type":"WatchEvent","created_at":"2024-05-03T10:00:00Z","repo":{"name":"a/`+id+`"},"payload":{"action":"started"}}`)
+		}
+		fmt.Fprint(w, "["+strings.Join(events, ",")+"]")
+	}))
+	defer srv.Close()
+	restore := apiURL
+	apiURL = srv.URL
+	defer func() { apiURL = restore }()
+
+	sink := &recordingSink{}
+	w := newWatcher()
+	feed = [][]string{{"5", "4"}, {"3", "2"}}
+	w.poll(context.Background(), []string{"alice"}, filters{}, []alertSink{sink})
+	if got := strings.Join(fetched, ","); got != "1" {
+		t.Errorf("first poll fetched pages %s, want 1", got)
+	}
+
+	// More than a page arrived between polls.
+	fetched = nil
+	feed = [][]string{{"8", "7"}, {"6", "5"}, {"4", "3"}}
+	w.poll(context.Background(), []string{"alice"}, filters{}, []alertSink{sink})
+	if got := strings.Join(fetched, ","); got != "1,2" {
+		t.Errorf("second poll fetched pages %s, want 1,2", got)
+	}
+	want := "New GitHub activity by alice:\n- Starred a/6\n- Starred a/7\n- Starred a/8"
+	if len(sink.alerts) != 1 || sink.alerts[0] != want {
+		t.Errorf("alerts: %q", sink.alerts)
+	}
+}
+
+func TestTerminalSink(t *testing.T) {
+	pinClock(t)
+	entries := []entry{testEntry("WatchEvent", "a/one", "Starred a/one")}
+	var b strings.Builder
+	_ = terminalSink{w: &b}.alert(context.Background(), "alice", entries)
+	if got := b.String(); got != "2024-05-01 12:00 alice: Starred a/one\n" {
+		t.Errorf("plain: %q", got)
+	}
+
+	b.Reset()
+	_ = terminalSink{w: &b, bell: true, title: true}.alert(context.Background(), "alice", append(entries, entries...))
+	if got := b.String(); !strings.HasSuffix(got, "\x1b]0;2 new events — alice\a\a") {
+		t.Errorf("bell and title: %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// waybarOutput is the object Waybar's custom modules (with "return-type":
+// "json") read; i3status-rust and other bars accept the same shape.
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+// waybarClass says how recent the latest activity is, for styling the
+// module: "active" within the hour, "recent" within the day, else "idle".
+func waybarClass(latest time.Time) string {
+	switch age := now().Sub(latest); {
+	case latest.IsZero():
+		return "idle"
+	case age < time.Hour:
+		return "active"
+	case age < 24*time.Hour:
+		return "recent"
+	}
+	return "idle"
+}
+
+// waybarRenderer buffers every user and writes one status bar object on
+// flush: a status segment per user as the text and the events as the
+// tooltip.
+type waybarRenderer struct {
+	w        io.Writer
+	segments []string
+	tooltip  []string
+	latest   time.Time
+}
+
+func (r *waybarRenderer) feed(user string, _ []Event, entries []entry) error {
+	events := make([]Event, len(entries))
+	for i, e := range entries {
+		events[i] = e.Event
+		if e.Event.CreatedAt.After(r.latest) {
+			r.latest = e.Event.CreatedAt
+		}
+		r.tooltip = append(r.tooltip, e.Event.CreatedAt.Local().Format("Jan 02 15:04")+"  "+actorPrefix(e)+e.Summary)
+	}
+	r.segments = append(r.segments, statusSegment(user, events))
+	return nil
+}
+
+// pangoEscaper escapes text for Waybar, which renders text and tooltips as
+// Pango markup.
+var pangoEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (r *waybarRenderer) flush() error {
+	out := waybarOutput{
+		Text:    pangoEscaper.Replace(strings.Join(r.segments, "  ")),
+		Tooltip: pangoEscaper.Replace(strings.Join(r.tooltip, "\n")),
+		Class:   waybarClass(r.latest),
+	}
+	if out.Tooltip == "" {
+		out.Tooltip = "No recent activity"
+	}
+	return json.NewEncoder(r.w).Encode(out)
+}
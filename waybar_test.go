@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaybarClass(t *testing.T) {
+	pinClock(t)
+	tests := []struct {
+		latest time.Time
+		want   string
+	}{
+		{time.Time{}, "idle"},
+		{now().Add(-10 * time.Minute), "active"},
+		{now().Add(-5 * time.Hour), "recent"},
+		{now().Add(-48 * time.Hour), "idle"},
+	}
+	for _, tt := range tests {
+		if got := waybarClass(tt.latest); got != tt.want {
+			t.Errorf("%v: got %s, want %s", tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestWaybarRenderer(t *testing.T) {
+	pinClock(t)
+	push := testEntry("PushEvent", "alice/repo", "Pushed 2 commit(s) to alice/repo")
+	push.Event.CreatedAt = now().Add(-30 * time.Minute)
+
+	var b strings.Builder
+	r := &waybarRenderer{w: &b}
+	if err := r.feed("alice", nil, []entry{push}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.feed("bob", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	var got waybarOutput
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("%v: %s", err, b.String())
+	}
+	want := waybarOutput{
+		Text:    "alice: 1⬆ 30m ago  bob: idle",
+		Tooltip: "May 04 08:30  Pushed 2 commit(s) to alice/repo",
+		Class:   "active",
+	}
+	if got != want {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if strings.Count(b.String(), "\n") != 1 {
+		t.Errorf("want one line, got %q", b.String())
+	}
+}
+
+func TestWaybarEscapesMarkup(t *testing.T) {
+	pinClock(t)
+	e := testEntry("IssuesEvent", "acme/app", "Opened an issue #1 “<b> & co” in acme/app")
+	e.Event.CreatedAt = now()
+	var b strings.Builder
+	r := &waybarRenderer{w: &b}
+	_ = r.feed("alice", nil, []entry{e})
+	_ = r.flush()
+	var got waybarOutput
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got.Tooltip, "“&lt;b&gt; &amp; co”") {
+		t.Errorf("tooltip not escaped: %q", got.Tooltip)
+	}
+}
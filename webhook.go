@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// webhookSink POSTs each new event to a URL, for automation services such as
+// IFTTT, Zapier, or n8n. The body is the event's JSON form (as in --json)
+// unless a template is given.
+type webhookSink struct {
+	url     string
+	headers http.Header
+	tmpl    *template.Template // nil for the default JSON body
+}
+
+// webhookFuncs extend the --template functions with json, which encodes a
+// value for safe embedding in a JSON body: {"text": {{json .Summary}}}.
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func newWebhookSink(url string, headers []string, body string) (webhookSink, error) {
+	s := webhookSink{url: url, headers: http.Header{}}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return s, fmt.Errorf("invalid header %q (want Name: value)", h)
+		}
+		s.headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	if body != "" {
+		t, err := template.New("webhook").Funcs(templateFuncs).Funcs(webhookFuncs).Option("missingkey=zero").Parse(body)
+		if err != nil {
+			return s, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		s.tmpl = t
+	}
+	return s, nil
+}
+
+func (s webhookSink) alert(ctx context.Context, _ string, entries []entry) error {
+	for _, e := range entries {
+		if err := s.post(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s webhookSink) post(ctx context.Context, e entry) error {
+	var body bytes.Buffer
+	if s.tmpl != nil {
+		if err := s.tmpl.Execute(&body, newTemplateEvent(e)); err != nil {
+			return fmt.Errorf("webhook template: %w", err)
+		}
+	} else if err := json.NewEncoder(&body).Encode(toJSONEvent(e)); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for name, values := range s.headers {
+		req.Header[name] = values
+	}
+	resp, err := sinkClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s", s.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSink(t *testing.T) {
+	var bodies []string
+	var auth, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		auth, contentType = r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		if strings.Contains(string(b), "fail") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	e := testEntry("WatchEvent", "a/one", `Starred "a/one"`)
+	e.Event.ID = "7"
+	e.User = "alice"
+
+	plain, err := newWebhookSink(srv.URL, []string{"Authorization: Bearer x"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.alert(context.Background(), "alice", []entry{e, e}); err != nil {
+		t.Fatal(err)
+	}
+	var got jsonEvent
+	if len(bodies) != 2 || json.Unmarshal([]byte(bodies[0]), &got) != nil || got.ID != "7" || got.Repo != "a/one" {
+		t.Errorf("default bodies: %q", bodies)
+	}
+	if auth != "Bearer x" || contentType != "application/json" {
+		t.Errorf("headers: %q, %q", auth, contentType)
+	}
+
+	bodies = nil
+	tmpl, err := newWebhookSink(srv.URL, []string{"Content-Type: text/plain"}, `{"value1": {{json .Summary}}, "value2": "{{.User}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpl.alert(context.Background(), "alice", []entry{e}); err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 1 || bodies[0] != `{"value1": "Starred \"a/one\"", "value2": "alice"}` || contentType != "text/plain" {
+		t.Errorf("templated: %q (%s)", bodies, contentType)
+	}
+
+	failing, _ := newWebhookSink(srv.URL, nil, `fail`)
+	if err := failing.alert(context.Background(), "alice", []entry{e}); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("failing hook: %v", err)
+	}
+	for _, bad := range []string{"NoColon", ": empty"} {
+		if _, err := newWebhookSink(srv.URL, []string{bad}, ""); err == nil {
+			t.Errorf("header %q accepted", bad)
+		}
+	}
+}
+
+// stalledServer never answers while the test runs.
+func stalledServer(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) }) // runs first, so Close doesn't wait forever
+	return srv
+}
+
+func TestWebhookSinkTimeout(t *testing.T) {
+	restore := sinkClient
+	sinkClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { sinkClient = restore }()
+
+	s, err := newWebhookSink(stalledServer(t).URL, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.post(context.Background(), testEntry("PushEvent", "alice/repo", "Pushed")); err == nil {
+		t.Error("stalled receiver: want a timeout error")
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// workingHoursShare is the share of events the reported active window covers.
+const workingHoursShare = 0.8
+
+// hoursReport is a user's activity by hour of day and day of week.
+type hoursReport struct {
+	Hours [24]int
+	Days  [7]int // indexed by time.Weekday
+	Total int
+}
+
+func workingHours(events []Event, loc *time.Location) hoursReport {
+	var r hoursReport
+	for _, ev := range events {
+		t := ev.CreatedAt.In(loc)
+		r.Hours[t.Hour()]++
+		r.Days[t.Weekday()]++
+		r.Total++
+	}
+	return r
+}
+
+// activeWindow finds the shortest run of hours, wrapping past midnight, that
+// holds at least share of all events. Among equally short runs it picks the
+// busiest.
+func activeWindow(hours [24]int, share float64) (start, length int) {
+	total := 0
+	for _, n := range hours {
+		total += n
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	for length = 1; length <= 24; length++ {
+		best, bestSum := -1, 0
+		for s := range 24 {
+			sum := 0
+			for i := range length {
+				sum += hours[(s+i)%24]
+			}
+			if float64(sum) >= share*float64(total) && sum > bestSum {
+				best, bestSum = s, sum
+			}
+		}
+		if best >= 0 {
+			return best, length
+		}
+	}
+	return 0, 24
+}
+
+// inferZone guesses the user's time zone from the UTC offsets in the author
+// dates of their recent commits, which GitHub only exposes in .patch views.
+// It checks up to three pushes and returns the most common offset.
+func inferZone(ctx context.Context, events []Event) (*time.Location, bool) {
+	offsets := map[int]int{}
+	checked := 0
+	for _, ev := range events {
+		if ev.Type != "PushEvent" || checked == 3 {
+			continue
+		}
+		var p payloadFields
+		if json.Unmarshal(ev.Payload, &p) != nil || p.Head == "" {
+			continue
+		}
+		checked++
+		if off, err := commitOffset(ctx, ev.Repo.Name, p.Head); err == nil {
+			offsets[off]++
+		}
+	}
+	best, bestN := 0, 0
+	for off, n := range offsets {
+		if n > bestN || (n == bestN && off < best) {
+			best, bestN = off, n
+		}
+	}
+	if bestN == 0 {
+		return nil, false
+	}
+	return time.FixedZone(formatOffset(best), best), true
+}
+
+// commitOffset reads the author date's UTC offset, in seconds, from the
+// "Date:" header of a commit's patch.
+func commitOffset(ctx context.Context, repo, sha string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, repoURL(repo)+"/commit/"+sha+".patch", nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fetching patch: %s", resp.Status)
+	}
+	// The header is at the top; don't read whole diffs.
+	sc := bufio.NewScanner(io.LimitReader(resp.Body, 16<<10))
+	for sc.Scan() {
+		if date, ok := strings.CutPrefix(sc.Text(), "Date: "); ok {
+			t, err := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", date)
+			if err != nil {
+				return 0, err
+			}
+			_, off := t.Zone()
+			return off, nil
+		}
+		if sc.Text() == "" {
+			break // end of the mail header
+		}
+	}
+	return 0, fmt.Errorf("no date in patch for %s", sha)
+}
+
+// formatOffset names a fixed zone like "UTC+05:30".
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("UTC%c%02d:%02d", sign, seconds/3600, seconds/60%60)
+}
+
+func printWorkingHours(w io.Writer, user string, events []Event, loc *time.Location, inferred bool) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No recent public activity.")
+		return
+	}
+	r := workingHours(events, loc)
+	zone := loc.String()
+	if inferred {
+		zone += ", inferred from commit dates"
+	}
+	fmt.Fprintf(w, "%s: %d events%s (times in %s)\n", user, r.Total, sinceOldest(events), zone)
+
+	start, length := activeWindow(r.Hours, workingHoursShare)
+	fmt.Fprintf(w, "Typical hours: %02d:00–%02d:00 (%d%% of events)\n", start, (start+length)%24, int(workingHoursShare*100))
+	var busy []string
+	for _, d := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		// A day counts as active with at least half its fair share of events.
+		if r.Days[d]*14 >= r.Total {
+			busy = append(busy, d.String()[:3])
+		}
+	}
+	fmt.Fprintf(w, "Active days: %s\n\n", strings.Join(busy, ", "))
+
+	peak := 0
+	for _, n := range r.Hours {
+		peak = max(peak, n)
+	}
+	for h, n := range r.Hours {
+		fmt.Fprintf(w, "%02d %s %d\n", h, strings.Repeat("█", n*30/peak), n)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActiveWindow(t *testing.T) {
+	var day [24]int
+	for h := 9; h < 17; h++ {
+		day[h] = 10
+	}
+	day[22] = 1
+	if s, l := activeWindow(day, 0.8); s != 9 || l != 7 {
+		t.Errorf("office hours: got %d+%d", s, l)
+	}
+
+	var night [24]int
+	night[22], night[23], night[0], night[1] = 5, 5, 5, 5
+	if s, l := activeWindow(night, 0.8); s != 22 || l != 4 {
+		t.Errorf("past midnight: got %d+%d", s, l)
+	}
+	if s, l := activeWindow([24]int{}, 0.8); s != 0 || l != 0 {
+		t.Errorf("empty: got %d+%d", s, l)
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	for secs, want := range map[int]string{0: "UTC+00:00", 19800: "UTC+05:30", -25200: "UTC-07:00"} {
+		if got := formatOffset(secs); got != want {
+			t.Errorf("formatOffset(%d) = %q, want %q", secs, got, want)
+		}
+	}
+}
+
+func TestInferZone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date := "Thu, 2 May 2024 14:01:45 -0700"
+		if r.URL.Path == "/alice/repo/commit/ccc.patch" {
+			date = "Thu, 2 May 2024 23:01:45 +0200"
+		}
+		_, _ = w.Write([]byte("From abc Mon Sep 17 00:00:00 2001\nFrom: Alice <a@example.com>\nDate: " + date + "\nSubject: [PATCH] x\n\n---\n"))
+	}))
+	defer srv.Close()
+	restore := webURL
+	webURL = srv.URL
+	defer func() { webURL = restore }()
+
+	push := func(head string) Event {
+		ev := Event{Type: "PushEvent", Payload: mustRaw(map[string]any{"head": head})}
+		ev.Repo.Name = "alice/repo"
+		return ev
+	}
+	loc, ok := inferZone(context.Background(), []Event{push("aaa"), {Type: "WatchEvent"}, push("bbb"), push("ccc"), push("ddd")})
+	if !ok || loc.String() != "UTC-07:00" {
+		t.Fatalf("got %v, %v", loc, ok)
+	}
+	if _, ok := inferZone(context.Background(), []Event{{Type: "WatchEvent"}}); ok {
+		t.Error("inferred a zone without pushes")
+	}
+}
+
+func TestPrintWorkingHours(t *testing.T) {
+	pinClock(t)
+	var events []Event
+	// Tuesday 2024-05-07, 09:00-12:59 UTC.
+	for h := 9; h < 13; h++ {
+		events = append(events, Event{Type: "PushEvent", CreatedAt: time.Date(2024, 5, 7, h, 30, 0, 0, time.UTC)})
+	}
+	var b strings.Builder
+	printWorkingHours(&b, "alice", events, time.FixedZone("UTC+02:00", 2*3600), true)
+	for _, want := range []string{
+		"alice: 4 events since May 7, 2024 (times in UTC+02:00, inferred from commit dates)",
+		"Typical hours: 11:00–15:00 (80% of events)",
+		"Active days: Tue\n",
+		"11 ██████████████████████████████ 1\n",
+		"10  0\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xbarRenderer buffers every user and writes an xbar (or SwiftBar) plugin's
+// output on flush: the menu bar title, then a dropdown section per user with
+// each event linked to its page.
+type xbarRenderer struct {
+	w        io.Writer
+	segments []string
+	menu     []string
+}
+
+// xbarText keeps text from being read as xbar syntax: " | " starts a line's
+// parameters and a leading "--" makes a submenu.
+func xbarText(s string) string {
+	s = strings.ReplaceAll(s, "|", "¦")
+	if strings.HasPrefix(s, "-") {
+		s = " " + s
+	}
+	return s
+}
+
+func (r *xbarRenderer) feed(user string, _ []Event, entries []entry) error {
+	events := make([]Event, len(entries))
+	r.menu = append(r.menu, "---", fmt.Sprintf("%s | href=%s", xbarText(user), webURL+"/"+user))
+	for i, e := range entries {
+		events[i] = e.Event
+		r.menu = append(r.menu, fmt.Sprintf("%s | href=%s", xbarText(actorPrefix(e)+e.Summary), eventURL(e.Event)))
+	}
+	if len(entries) == 0 {
+		r.menu = append(r.menu, "No recent activity")
+	}
+	r.segments = append(r.segments, statusSegment(user, events))
+	return nil
+}
+
+func (r *xbarRenderer) flush() error {
+	lines := append([]string{xbarText(strings.Join(r.segments, "  "))}, r.menu...)
+	lines = append(lines, "---", "Refresh | refresh=true")
+	_, err := fmt.Fprintln(r.w, strings.Join(lines, "\n"))
+	return err
+}
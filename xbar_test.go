@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXbarRenderer(t *testing.T) {
+	pinClock(t)
+	e := testEntry("IssuesEvent", "acme/app", "Opened an issue #5 “a | b” in acme/app")
+	e.Event.CreatedAt = now().Add(-2 * time.Hour)
+	e.Event.Payload = mustRaw(map[string]any{"action": "opened", "issue": map[string]any{"number": 5}})
+
+	var b strings.Builder
+	r := &xbarRenderer{w: &b}
+	if err := r.feed("alice", nil, []entry{e}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.feed("bob", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"alice: 2h ago  bob: idle",
+		"---",
+		"alice | href=" + webURL + "/alice",
+		"Opened an issue #5 “a ¦ b” in acme/app | href=" + webURL + "/acme/app/issues/5",
+		"---",
+		"bob | href=" + webURL + "/bob",
+		"No recent activity",
+		"---",
+		"Refresh | refresh=true",
+	}, "\n") + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestXbarText(t *testing.T) {
+	for in, want := range map[string]string{"a|b": "a¦b", "--x": " --x", "plain": "plain"} {
+		if got := xbarText(in); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}